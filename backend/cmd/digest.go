@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"webservices/registry"
+	"webservices/src/pkg/log"
+
+	"github.com/spf13/cobra"
+)
+
+func NewDigestCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "digest:send",
+		Short: "Send today daily digest email",
+		Run: func(cmd *cobra.Command, args []string) {
+			db, err := initDB(cmd)
+			if err != nil {
+				log.Error("Error initializing database: ", err)
+				return
+			}
+
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+			// socket.io is not needed, the digest only sent by email
+			services := registry.NewServices(registry.NewRepositories(db), nil)
+
+			if !dryRun {
+				sent, err := services.Digest.Send(context.Background(), time.Now())
+				if err != nil {
+					log.Errorf("Send digest failed: %v", err)
+					return
+				}
+				log.Successf("Daily digest sent to %d recipient(s)", sent)
+				return
+			}
+
+			digests, err := services.Digest.Build(time.Now())
+			if err != nil {
+				log.Errorf("Build digest failed: %v", err)
+				return
+			}
+
+			for i := range digests {
+				options, err := services.Digest.Render(&digests[i])
+				if err != nil {
+					log.Errorf("Render digest failed: %v", err)
+					return
+				}
+
+				fmt.Printf("From: %s\nTo: %v\nSubject: %s\n\n%s\n\n%s\n\n",
+					options.From, options.To, options.Subject, options.Body,
+					"------------------------------------------------------------")
+			}
+
+			log.Infof("[DRY RUN] %d digest(s) would be sent", len(digests))
+		},
+	}
+
+	cmd.Flags().Bool("dry-run", false, "Print the digest without sending it")
+
+	return cmd
+}
@@ -13,6 +13,7 @@ import (
 	"syscall"
 	"time"
 	"webservices/database"
+	"webservices/src/jobs"
 	"webservices/src/middleware"
 	"webservices/src/pkg"
 	c "webservices/src/pkg/common"
@@ -34,6 +35,7 @@ func NewServeCmd() *cobra.Command {
 			port, _ := cmd.Flags().GetString("port")
 			dsn, _ := cmd.Flags().GetString("dsn")
 			debug, _ := cmd.Flags().GetBool("debug")
+			schedule, _ := cmd.Flags().GetBool("scheduler")
 
 			log.SetFlags(log.LstdFlags | log.Lshortfile)
 			gin.SetMode(gin.ReleaseMode)
@@ -67,7 +69,12 @@ func NewServeCmd() *cobra.Command {
 				server.Start()
 			}()
 
+			if schedule {
+				server.Schedule(ctx)
+			}
+
 			<-ctx.Done()
+			server.WaitSchedule()
 
 			if err := database.Disconnect(); err != nil {
 				logger.Error("Close database connection", slog.Any("error", err))
@@ -81,12 +88,15 @@ func NewServeCmd() *cobra.Command {
 
 	cmd.Flags().StringP("host", "H", c.Env("HOST", "localhost"), "Server host")
 	cmd.Flags().StringP("port", "p", c.Env("PORT", "8000"), "Server port")
+	cmd.Flags().Bool("scheduler", c.Env("SCHEDULER", "true") == "true", "Run background scheduler (due date reminders)")
 
 	return cmd
 }
 
 type Server struct {
-	http *http.Server
+	http      *http.Server
+	io        *socket.Server
+	scheduler *jobs.Scheduler
 }
 
 func NewServer(host, port string) *Server {
@@ -125,6 +135,7 @@ func NewServer(host, port string) *Server {
 			Addr:    fmt.Sprintf("%s:%s", host, port),
 			Handler: router,
 		},
+		io: io,
 	}
 }
 
@@ -137,6 +148,19 @@ func (server *Server) Start() {
 	}
 }
 
+// Schedule start the background jobs, stopped when the context is done
+func (server *Server) Schedule(ctx context.Context) {
+	server.scheduler = routes.Job(ctx, server.io, database.DB())
+}
+
+func (server *Server) WaitSchedule() {
+	if server.scheduler == nil {
+		return
+	}
+	server.scheduler.Wait()
+	logger.Info("Scheduler stopped")
+}
+
 func (server *Server) Stop(timeout time.Duration) {
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
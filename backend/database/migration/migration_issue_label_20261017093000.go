@@ -0,0 +1,79 @@
+package migration
+
+import (
+	"fmt"
+	"strings"
+	"webservices/src/model"
+	"webservices/src/pkg/log"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SplitIssueLabels move the legacy free-text `issues.label` into the label catalog,
+// comma separated values become one label each. The column dropped afterward so it only runs once
+func SplitIssueLabels(tx *gorm.DB) error {
+	if !tx.Migrator().HasColumn(&model.Issue{}, "label") {
+		return nil
+	}
+
+	var rows []struct {
+		ID        string
+		ProjectID string
+		Label     string
+	}
+
+	if err := tx.Table("issues").
+		Select("id, project_id, label").
+		Where("label IS NOT NULL AND TRIM(label) != ''").
+		Scan(&rows).
+		Error; err != nil {
+		return fmt.Errorf("failed to fetch issue labels: %w", err)
+	}
+
+	// project_id + lower(name) => label id
+	catalog := make(map[string]string)
+	linked := 0
+
+	for _, row := range rows {
+		for _, name := range strings.Split(row.Label, ",") {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+
+			key := row.ProjectID + ":" + strings.ToLower(name)
+			labelID, ok := catalog[key]
+			if !ok {
+				var label model.Label
+				err := tx.Where("project_id = ? AND LOWER(name) = LOWER(?)", row.ProjectID, name).
+					Attrs(model.Label{ProjectID: row.ProjectID, Name: name}).
+					FirstOrCreate(&label).
+					Error
+				if err != nil {
+					return fmt.Errorf("failed to create label %q: %w", name, err)
+				}
+
+				labelID = label.ID
+				catalog[key] = labelID
+			}
+
+			if err := tx.Table("issue_labels").
+				Clauses(clause.OnConflict{DoNothing: true}).
+				Create(map[string]any{
+					"issue_id": row.ID,
+					"label_id": labelID,
+				}).Error; err != nil {
+				return fmt.Errorf("failed to link issue label: %w", err)
+			}
+			linked++
+		}
+	}
+
+	if err := tx.Migrator().DropColumn(&model.Issue{}, "label"); err != nil {
+		return fmt.Errorf("failed to drop issues.label: %w", err)
+	}
+
+	log.Infof("Moved %d issue labels into %d catalog entries", linked, len(catalog))
+	return nil
+}
@@ -0,0 +1,39 @@
+package migration
+
+import (
+	"fmt"
+	"webservices/src/pkg/log"
+
+	"gorm.io/gorm"
+)
+
+// BackfillIssueWatchers subscribe the reporter, assignee, creator & commenters of the existing issues,
+// the ones the notifications reached before the watchers. Only runs while no watcher recorded yet
+func BackfillIssueWatchers(tx *gorm.DB) error {
+	var exists bool
+	if err := tx.Raw("SELECT EXISTS (SELECT 1 FROM issue_watchers)").
+		Scan(&exists).Error; err != nil {
+		return fmt.Errorf("failed to check issue watchers: %w", err)
+	}
+
+	if exists {
+		return nil
+	}
+
+	result := tx.Exec(`
+		INSERT INTO issue_watchers (issue_id, user_id, watching)
+		SELECT DISTINCT w.issue_id, w.user_id, TRUE FROM (
+			SELECT id AS issue_id, reporter_id AS user_id FROM issues
+			UNION SELECT id, assignee_id FROM issues
+			UNION SELECT id, creator_id FROM issues
+			UNION SELECT issue_id, user_id FROM comments
+		) AS w
+		JOIN users ON users.id = w.user_id
+		ON CONFLICT DO NOTHING`)
+	if result.Error != nil {
+		return fmt.Errorf("failed to backfill issue watchers: %w", result.Error)
+	}
+
+	log.Infof("Subscribed %d issue watchers", result.RowsAffected)
+	return nil
+}
@@ -0,0 +1,57 @@
+package migration
+
+import (
+	"fmt"
+	"strings"
+	"webservices/src/pkg/log"
+	"webservices/src/types"
+
+	"gorm.io/gorm"
+)
+
+// BackfillStatusCategory fill `issues.status_category` from the default workflow once the status became
+// a plain column. The legacy `issue_status` enum dropped afterward so it only runs once
+func BackfillStatusCategory(tx *gorm.DB) error {
+	var exists bool
+	if err := tx.Raw("SELECT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'issue_status')").
+		Scan(&exists).Error; err != nil {
+		return fmt.Errorf("failed to check issue_status type: %w", err)
+	}
+
+	if !exists {
+		return nil
+	}
+
+	// the columns & their defaults still depending on the enum, converted ahead of the drop
+	for _, column := range [][2]string{
+		{"issues", "status"},
+		{"project_settings", "default_issue_status"},
+	} {
+		if err := tx.Exec(fmt.Sprintf(
+			"ALTER TABLE %[1]s ALTER COLUMN %[2]s TYPE varchar(40), ALTER COLUMN %[2]s SET DEFAULT 'todo'",
+			column[0], column[1])).Error; err != nil {
+			return fmt.Errorf("failed to convert %s.%s column: %w", column[0], column[1], err)
+		}
+	}
+
+	cases := make([]string, 0, len(types.IssueStatuses))
+	args := make([]any, 0, len(types.IssueStatuses)*2)
+	for _, status := range types.IssueStatuses {
+		cases = append(cases, "WHEN ? THEN ?")
+		args = append(args, status, status.DefaultCategory())
+	}
+
+	result := tx.Exec(fmt.Sprintf(
+		"UPDATE issues SET status_category = (CASE status %s ELSE 'todo' END)::status_category",
+		strings.Join(cases, " ")), args...)
+	if result.Error != nil {
+		return fmt.Errorf("failed to backfill status category: %w", result.Error)
+	}
+
+	if err := tx.Exec("DROP TYPE IF EXISTS issue_status").Error; err != nil {
+		return fmt.Errorf("failed to drop issue_status type: %w", err)
+	}
+
+	log.Infof("Categorized the status of %d issues", result.RowsAffected)
+	return nil
+}
@@ -16,7 +16,8 @@ A unified tool for managing all application components including:
 • HTTP Server - Start/Restart the web service
 • Database Migrations - Schema version control
 • Data Seeding - Populate database with initial data
-• Test Factories - Generate fake data for development`
+• Test Factories - Generate fake data for development
+• Daily Digest - Send or preview the digest email`
 
 func main() {
 	if err := NewCmd().Execute(); err != nil {
@@ -44,6 +45,7 @@ func NewCmd() *cobra.Command {
 	root.AddCommand(cmd.NewBackupCmd())
 	root.AddCommand(cmd.NewMFactoryCmd())
 	root.AddCommand(cmd.NewSeedCmd())
+	root.AddCommand(cmd.NewDigestCmd())
 	root.AddCommand(cmd.NewMakeModel())
 	root.AddCommand(cmd.NewMakeRepo())
 	root.AddCommand(cmd.NewMakeServices())
@@ -3,23 +3,43 @@ package registry
 import "webservices/src/controllers"
 
 type Controllers struct {
-	User    *controllers.UserController
-	Project *controllers.ProjectController
-	Issue   *controllers.IssueController
-	Notif   *controllers.NotificationController
-	Comment *controllers.CommentController
-	Item    *controllers.IssueItemController
-	Report  *controllers.ReportController
+	User     *controllers.UserController
+	Project  *controllers.ProjectController
+	Issue    *controllers.IssueController
+	Notif    *controllers.NotificationController
+	Comment  *controllers.CommentController
+	Item     *controllers.IssueItemController
+	Report   *controllers.ReportController
+	Sprint   *controllers.SprintController
+	Worklog  *controllers.WorklogController
+	Label    *controllers.LabelController
+	Filter   *controllers.SavedFilterController
+	Analytic *controllers.AnalyticController
+	Workflow *controllers.WorkflowController
+	Field    *controllers.CustomFieldController
+	Template *controllers.IssueTemplateController
+	Watcher  *controllers.WatcherController
+	Presence *controllers.PresenceController
 }
 
 func NewControllers(services *Services) *Controllers {
 	return &Controllers{
-		User:    controllers.NewUserController(services.User),
-		Project: controllers.NewProjectController(services.Project, services.Notif),
-		Issue:   controllers.NewIssueController(services.Issue, services.Notif, services.Mail),
-		Notif:   controllers.NewNotificationController(services.Mail, services.Project, services.Notif),
-		Comment: controllers.NewCommentController(services.Comment, services.Notif),
-		Item:    controllers.NewIssueItemController(services.Item),
-		Report:  controllers.NewReportController(services.Report),
+		User:     controllers.NewUserController(services.User),
+		Project:  controllers.NewProjectController(services.Project, services.Notif),
+		Issue:    controllers.NewIssueController(services.Issue, services.Notif, services.Mail, services.Filter, services.Analytic),
+		Notif:    controllers.NewNotificationController(services.Mail, services.Project, services.Notif),
+		Comment:  controllers.NewCommentController(services.Comment, services.Notif),
+		Item:     controllers.NewIssueItemController(services.Item),
+		Report:   controllers.NewReportController(services.Report),
+		Sprint:   controllers.NewSprintController(services.Sprint),
+		Worklog:  controllers.NewWorklogController(services.Worklog),
+		Label:    controllers.NewLabelController(services.Label),
+		Filter:   controllers.NewSavedFilterController(services.Filter),
+		Analytic: controllers.NewAnalyticController(services.Analytic),
+		Workflow: controllers.NewWorkflowController(services.Workflow),
+		Field:    controllers.NewCustomFieldController(services.Field),
+		Template: controllers.NewIssueTemplateController(services.Template, services.Notif),
+		Watcher:  controllers.NewWatcherController(services.Watcher),
+		Presence: controllers.NewPresenceController(services.Presence),
 	}
 }
@@ -2,6 +2,7 @@ package registry
 
 import (
 	"webservices/database/factory"
+	"webservices/database/migration"
 	"webservices/src/model"
 	"webservices/src/pkg/log"
 	"webservices/src/pkg/structers"
@@ -22,9 +23,19 @@ var Database = &structers.DatabaseRegistry{
 			},
 		},
 		{
-			Name: "issue_status",
+			Name: "status_category",
+			Values: func() []string {
+				var vals []string
+				for _, v := range types.StatusCategories {
+					vals = append(vals, v.String())
+				}
+				return vals
+			}(),
+		},
+		{
+			Name: "sprint_state",
 			Values: []string{
-				"draft", "todo", "on_progress", "done",
+				"planned", "active", "closed",
 			},
 		},
 		{
@@ -51,6 +62,46 @@ var Database = &structers.DatabaseRegistry{
 				"attachment", "web_link", "link_work",
 			},
 		},
+		{
+			Name: "issue_link_type",
+			Values: func() []string {
+				var vals []string
+				for _, v := range types.IssueLinkTypes {
+					vals = append(vals, v.String())
+				}
+				return vals
+			}(),
+		},
+		{
+			Name: "custom_field_type",
+			Values: func() []string {
+				var vals []string
+				for _, v := range types.CustomFieldTypes {
+					vals = append(vals, v.String())
+				}
+				return vals
+			}(),
+		},
+		{
+			Name: "filter_visibility",
+			Values: func() []string {
+				var vals []string
+				for _, v := range types.FilterVisibilities {
+					vals = append(vals, v.String())
+				}
+				return vals
+			}(),
+		},
+		{
+			Name: "board_grouping",
+			Values: func() []string {
+				var vals []string
+				for _, v := range types.BoardGroupings {
+					vals = append(vals, v.String())
+				}
+				return vals
+			}(),
+		},
 		{
 			Name: "user_project_role",
 			Values: []string{
@@ -87,25 +138,75 @@ var Database = &structers.DatabaseRegistry{
 				return vals
 			}(),
 		},
+		{
+			Name: "presence_status",
+			Values: func() []string {
+				var vals []string
+				for _, v := range types.PresenceStatuses {
+					vals = append(vals, v.String())
+				}
+				return vals
+			}(),
+		},
 	},
 	Models: []any{
 		&model.User{},
 		&model.Project{},
 		&model.UserProject{},
 		&model.ProjectSetting{},
+		&model.Sprint{},
+		&model.Label{},
+		&model.WorkflowStatus{},
+		&model.WorkflowTransition{},
+		&model.CustomField{},
 		&model.Issue{},
 		&model.Comment{},
+		&model.CommentReaction{},
+		&model.CommentRevision{},
 		&model.IssueItem{},
+		&model.Worklog{},
 		&model.RecentActivity{},
 		&model.Notification{},
+		&model.IssueReminder{},
+		&model.DigestLog{},
 		&model.Report{},
+		&model.SavedFilter{},
+		&model.IssueTemplate{},
+		&model.IssueRecurrence{},
+		&model.RecurrenceRun{},
+		&model.IssueWatcher{},
+		&model.PresenceSession{},
 	},
 	Tables: []string{
 		"users",
 		"projects",
 		"user_projects",
+		"sprints",
 		"issues",
+		"labels",
+		"issue_labels",
+		"workflow_statuses",
+		"workflow_transitions",
+		"custom_fields",
 		"issue_items",
+		"worklogs",
+		"issue_templates",
+		"issue_recurrences",
+		"issue_watchers",
+	},
+	Migrations: []func(*gorm.DB) error{
+		func(db *gorm.DB) error {
+			log.Info("Moving issue labels into the catalog...")
+			return migration.SplitIssueLabels(db)
+		},
+		func(db *gorm.DB) error {
+			log.Info("Categorizing issue statuses...")
+			return migration.BackfillStatusCategory(db)
+		},
+		func(db *gorm.DB) error {
+			log.Info("Subscribing the issue watchers...")
+			return migration.BackfillIssueWatchers(db)
+		},
 	},
 	Factories: []func(*gorm.DB) error{
 		func(db *gorm.DB) error {
@@ -8,11 +8,17 @@ import (
 )
 
 type Events struct {
-	Notif *events.NotificationEvent
+	Notif    *events.NotificationEvent
+	Issue    *events.IssueEvent
+	Project  *events.ProjectEvent
+	Presence *events.PresenceEvent
 }
 
 func NewEvents(user *model.User, socket *socket.Socket, services *Services) *Events {
 	return &Events{
-		Notif: events.NewNotificationEvent(user, socket, services.Notif),
+		Notif:    events.NewNotificationEvent(user, socket, services.Notif),
+		Issue:    events.NewIssueEvent(user, socket, services.Issue),
+		Project:  events.NewProjectEvent(user, socket, services.Project),
+		Presence: events.NewPresenceEvent(user, socket, services.Presence),
 	}
 }
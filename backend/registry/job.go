@@ -0,0 +1,21 @@
+package registry
+
+import "webservices/src/jobs"
+
+type Jobs struct {
+	Reminder   *jobs.ReminderJob
+	Digest     *jobs.DigestJob
+	Recurrence *jobs.RecurrenceJob
+	Comment    *jobs.CommentJob
+	Presence   *jobs.PresenceJob
+}
+
+func NewJobs(services *Services) *Jobs {
+	return &Jobs{
+		Reminder:   jobs.NewReminderJob(services.Issue, services.Notif),
+		Digest:     jobs.NewDigestJob(services.Digest),
+		Recurrence: jobs.NewRecurrenceJob(services.Template),
+		Comment:    jobs.NewCommentJob(services.Comment),
+		Presence:   jobs.NewPresenceJob(services.Presence),
+	}
+}
@@ -17,6 +17,19 @@ type Repositories struct {
 	Item        *repo.IssueItemRepository
 	UserProject *repo.UserProjectRepository
 	Report      *repo.ReportRepository
+	Sprint      *repo.SprintRepository
+	Worklog     *repo.WorklogRepository
+	Reminder    *repo.IssueReminderRepository
+	Digest      *repo.DigestLogRepository
+	Label       *repo.LabelRepository
+	Filter      *repo.SavedFilterRepository
+	Analytic    *repo.AnalyticRepository
+	Workflow    *repo.WorkflowRepository
+	Field       *repo.CustomFieldRepository
+	Template    *repo.IssueTemplateRepository
+	Recurrence  *repo.RecurrenceRepository
+	Watcher     *repo.WatcherRepository
+	Presence    *repo.PresenceRepository
 }
 
 func NewRepositories(db *gorm.DB) *Repositories {
@@ -31,5 +44,18 @@ func NewRepositories(db *gorm.DB) *Repositories {
 		Item:        repo.NewIssueItemRepository(db),
 		UserProject: repo.NewUserProjectRepository(db),
 		Report:      repo.NewReportRepository(db),
+		Sprint:      repo.NewSprintRepository(db),
+		Worklog:     repo.NewWorklogRepository(db),
+		Reminder:    repo.NewIssueReminderRepository(db),
+		Digest:      repo.NewDigestLogRepository(db),
+		Label:       repo.NewLabelRepository(db),
+		Filter:      repo.NewSavedFilterRepository(db),
+		Analytic:    repo.NewAnalyticRepository(db),
+		Workflow:    repo.NewWorkflowRepository(db),
+		Field:       repo.NewCustomFieldRepository(db),
+		Template:    repo.NewIssueTemplateRepository(db),
+		Recurrence:  repo.NewRecurrenceRepository(db),
+		Watcher:     repo.NewWatcherRepository(db),
+		Presence:    repo.NewPresenceRepository(db),
 	}
 }
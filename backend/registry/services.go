@@ -1,31 +1,62 @@
 package registry
 
 import (
+	"webservices/src/pkg/storage"
 	"webservices/src/services"
 
 	"github.com/zishang520/socket.io/v2/socket"
 )
 
 type Services struct {
-	User    *services.UserService
-	Project *services.ProjectService
-	Issue   *services.IssueService
-	Notif   *services.NotificationService
-	Comment *services.CommentService
-	Item    *services.IssueItemService
-	Mail    *services.MailService
-	Report  *services.ReportService
+	User     *services.UserService
+	Project  *services.ProjectService
+	Issue    *services.IssueService
+	Notif    *services.NotificationService
+	Comment  *services.CommentService
+	Item     *services.IssueItemService
+	Mail     *services.MailService
+	Report   *services.ReportService
+	Sprint   *services.SprintService
+	Worklog  *services.WorklogService
+	Digest   *services.DigestService
+	Label    *services.LabelService
+	Filter   *services.SavedFilterService
+	Analytic *services.AnalyticService
+	Workflow *services.WorkflowService
+	Field    *services.CustomFieldService
+	Template *services.IssueTemplateService
+	Watcher  *services.WatcherService
+	Presence *services.PresenceService
 }
 
 func NewServices(repos *Repositories, io *socket.Server) *Services {
+	mail := services.NewMailService(repos.User, nil)
+	issue := services.NewIssueService(io, repos.Issue, repos.User, repos.Project, repos.Activity, repos.Sprint, repos.Item, repos.Label, repos.Workflow, repos.Field, repos.Watcher, repos.UserProject)
+
+	storage, err := storage.New(storage.ConfigFromEnv())
+	if err != nil {
+		panic(err)
+	}
+
 	return &Services{
-		Mail:    services.NewMailService(repos.User, nil),
-		User:    services.NewUserService(repos.User),
-		Project: services.NewProjectService(io, repos.User, repos.Project, repos.Setting, repos.Activity, repos.UserProject),
-		Issue:   services.NewIssueService(repos.Issue, repos.User, repos.Project, repos.Activity),
-		Notif:   services.NewNotificationService(io, repos.User, repos.Project, repos.Issue, repos.Comment, repos.Notif, repos.UserProject),
-		Comment: services.NewCommentService(repos.User, repos.Comment, repos.Issue, repos.Activity),
-		Item:    services.NewIssueItemService(repos.Item, repos.Issue, repos.User, repos.Activity),
-		Report:  services.NewReportService(repos.Report),
+		Mail:     mail,
+		User:     services.NewUserService(repos.User),
+		Project:  services.NewProjectService(io, repos.User, repos.Project, repos.Setting, repos.Activity, repos.UserProject, repos.Workflow, repos.Issue),
+		Issue:    issue,
+		Notif:    services.NewNotificationService(io, repos.User, repos.Project, repos.Issue, repos.Comment, repos.Notif, repos.UserProject, repos.Reminder, repos.Watcher, mail),
+		Comment:  services.NewCommentService(io, repos.User, repos.Comment, repos.Issue, repos.Activity, repos.Watcher, repos.UserProject),
+		Item:     services.NewIssueItemService(io, repos.Item, repos.Issue, repos.User, repos.Setting, repos.Activity, storage),
+		Report:   services.NewReportService(repos.Report),
+		Sprint:   services.NewSprintService(io, repos.Sprint, repos.Issue, repos.User, repos.Activity),
+		Worklog:  services.NewWorklogService(repos.Worklog, repos.Issue, repos.User, repos.Setting, repos.Activity),
+		Digest:   services.NewDigestService(repos.Project, repos.Issue, repos.Comment, repos.Digest, mail),
+		Label:    services.NewLabelService(repos.Label, repos.User),
+		Filter:   services.NewSavedFilterService(repos.Filter, repos.User, repos.Workflow, repos.Field),
+		Analytic: services.NewAnalyticService(repos.Analytic, repos.Sprint, repos.Setting, repos.Workflow, repos.User),
+		Workflow: services.NewWorkflowService(repos.Workflow, repos.Setting, repos.User, repos.Activity),
+		Field:    services.NewCustomFieldService(repos.Field, repos.User),
+		Template: services.NewIssueTemplateService(repos.Template, repos.Recurrence, repos.User, issue),
+		Watcher:  services.NewWatcherService(repos.Watcher, repos.Issue, repos.User),
+		Presence: services.NewPresenceService(io, repos.Presence, repos.User, repos.UserProject, repos.Issue),
 	}
 }
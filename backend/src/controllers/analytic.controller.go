@@ -0,0 +1,87 @@
+package controllers
+
+import (
+	"strings"
+	"webservices/src/model"
+	"webservices/src/services"
+	"webservices/src/types/schemas"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AnalyticController struct {
+	analyticService *services.AnalyticService
+}
+
+func NewAnalyticController(analyticService *services.AnalyticService) *AnalyticController {
+	return &AnalyticController{
+		analyticService: analyticService,
+	}
+}
+
+func (ctrl *AnalyticController) Burndown(c *gin.Context) {
+	var user model.User
+	if err := user.GetContext(c); err != nil {
+		c.AbortWithStatusJSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	if user.ProjectID == nil || *user.ProjectID == "" {
+		c.AbortWithStatusJSON(400, gin.H{"error": "failed to fetch: project ID empty"})
+		return
+	}
+
+	var query schemas.BurndownQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": "Bad request"})
+		return
+	}
+
+	burndown, err := ctrl.analyticService.GetBurndown(user.ID, *user.ProjectID, query)
+	if err != nil {
+		c.AbortWithStatusJSON(analyticErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.AbortWithStatusJSON(200, gin.H{"data": burndown})
+}
+
+func (ctrl *AnalyticController) Velocity(c *gin.Context) {
+	var user model.User
+	if err := user.GetContext(c); err != nil {
+		c.AbortWithStatusJSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	if user.ProjectID == nil || *user.ProjectID == "" {
+		c.AbortWithStatusJSON(400, gin.H{"error": "failed to fetch: project ID empty"})
+		return
+	}
+
+	var query schemas.VelocityQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": "Bad request"})
+		return
+	}
+
+	velocity, err := ctrl.analyticService.GetVelocity(user.ID, *user.ProjectID, query)
+	if err != nil {
+		c.AbortWithStatusJSON(analyticErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.AbortWithStatusJSON(200, gin.H{"data": velocity})
+}
+
+func analyticErrorCode(err error) int {
+	message := err.Error()
+	switch {
+	case strings.Contains(message, "invalid date range"):
+		return 400
+	case strings.Contains(message, "permission denied"):
+		return 403
+	case strings.Contains(message, "not found"):
+		return 404
+	}
+	return 500
+}
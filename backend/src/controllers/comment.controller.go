@@ -1,8 +1,10 @@
 package controllers
 
 import (
+	"strings"
 	"webservices/src/model"
 	"webservices/src/pkg/logger"
+	"webservices/src/pkg/pagination"
 	"webservices/src/services"
 	"webservices/src/types/schemas"
 
@@ -31,14 +33,26 @@ func (ctrl *CommentController) GetComments(c *gin.Context) {
 		return
 	}
 
-	comments, err := ctrl.commentService.GetByIssue(issueID)
+	var page schemas.PageQuery
+	if err := c.ShouldBindQuery(&page); err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": "Bad request"})
+		return
+	}
+
+	params, err := pagination.New(page.Limit, page.Cursor)
+	if err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	comments, err := ctrl.commentService.GetByIssue(issueID, params)
 	if err != nil {
 		logger.Error(err)
 		c.AbortWithStatusJSON(400, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.AbortWithStatusJSON(200, gin.H{"data": comments})
+	c.AbortWithStatusJSON(200, gin.H{"data": comments.Items, "pagination": comments.Info})
 }
 
 func (ctrl *CommentController) Create(c *gin.Context) {
@@ -60,10 +74,10 @@ func (ctrl *CommentController) Create(c *gin.Context) {
 		return
 	}
 
-	comment, err := ctrl.commentService.Create(user.ID, issueID, body.Message)
+	comment, err := ctrl.commentService.Create(user.ID, issueID, body.Message, body.ParentID)
 	if err != nil {
 		logger.Error(err)
-		c.AbortWithStatusJSON(500, gin.H{"error": err.Error()})
+		c.AbortWithStatusJSON(commentErrorCode(err), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -100,9 +114,16 @@ func (ctrl *CommentController) Update(c *gin.Context) {
 	comment, err := ctrl.commentService.Update(commentID, user.ID, body.Message)
 	if err != nil {
 		logger.Error(err)
-		c.AbortWithStatusJSON(500, gin.H{"error": err.Error()})
+		c.AbortWithStatusJSON(commentErrorCode(err), gin.H{"error": err.Error()})
+		return
 	}
 
+	go func() {
+		if err := ctrl.notifService.PushMention(&user, &comment.Issue, comment, comment.Mentions); err != nil {
+			logger.Errorf("failed to push comment mentions: %s", err)
+		}
+	}()
+
 	c.AbortWithStatusJSON(200, gin.H{"data": comment})
 }
 
@@ -128,8 +149,106 @@ func (ctrl *CommentController) Delete(c *gin.Context) {
 	comment, err := ctrl.commentService.Delete(commentID, user.ID)
 	if err != nil {
 		logger.Error(err)
-		c.AbortWithStatusJSON(500, gin.H{"error": err.Error()})
+		c.AbortWithStatusJSON(commentErrorCode(err), gin.H{"error": err.Error()})
+		return
 	}
 
 	c.AbortWithStatusJSON(200, gin.H{"data": comment})
 }
+
+func (ctrl *CommentController) React(c *gin.Context) {
+	issueID := c.Param("id")
+	commentID := c.Param("comment_id")
+	if issueID == "" || commentID == "" {
+		c.AbortWithStatusJSON(404, gin.H{"error": "not found"})
+		return
+	}
+
+	var user model.User
+	if err := user.GetContext(c); err != nil {
+		c.AbortWithStatusJSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var body schemas.ToggleReaction
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": "bad request"})
+		return
+	}
+
+	reactions, err := ctrl.commentService.React(user.ID, issueID, commentID, body.Emoji)
+	if err != nil {
+		logger.Error(err)
+		c.AbortWithStatusJSON(commentErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.AbortWithStatusJSON(200, gin.H{"data": reactions})
+}
+
+func (ctrl *CommentController) GetHistory(c *gin.Context) {
+	issueID := c.Param("id")
+	commentID := c.Param("comment_id")
+	if issueID == "" || commentID == "" {
+		c.AbortWithStatusJSON(404, gin.H{"error": "not found"})
+		return
+	}
+
+	var user model.User
+	if err := user.GetContext(c); err != nil {
+		c.AbortWithStatusJSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	revisions, err := ctrl.commentService.GetHistory(user.ID, issueID, commentID)
+	if err != nil {
+		logger.Error(err)
+		c.AbortWithStatusJSON(commentErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.AbortWithStatusJSON(200, gin.H{"data": revisions})
+}
+
+func (ctrl *CommentController) Restore(c *gin.Context) {
+	issueID := c.Param("id")
+	commentID := c.Param("comment_id")
+	if issueID == "" || commentID == "" {
+		c.AbortWithStatusJSON(404, gin.H{"error": "not found"})
+		return
+	}
+
+	var user model.User
+	if err := user.GetContext(c); err != nil {
+		c.AbortWithStatusJSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	comment, err := ctrl.commentService.Restore(user.ID, issueID, commentID)
+	if err != nil {
+		logger.Error(err)
+		c.AbortWithStatusJSON(commentErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.AbortWithStatusJSON(200, gin.H{"data": comment})
+}
+
+func commentErrorCode(err error) int {
+	switch {
+	case strings.Contains(err.Error(), "invalid mention"),
+		strings.Contains(err.Error(), "invalid reaction"),
+		strings.Contains(err.Error(), "deleted comment"):
+		return 400
+	case strings.Contains(err.Error(), "not deleted"),
+		strings.Contains(err.Error(), "no longer be restored"):
+		return 409
+	case strings.Contains(err.Error(), "you can only"),
+		strings.Contains(err.Error(), "permission denied"):
+		return 403
+	case strings.Contains(err.Error(), "not found"):
+		return 404
+	default:
+		return 500
+	}
+}
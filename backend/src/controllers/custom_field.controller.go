@@ -0,0 +1,137 @@
+package controllers
+
+import (
+	"strings"
+	"webservices/src/model"
+	"webservices/src/services"
+	"webservices/src/types/schemas"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CustomFieldController struct {
+	fieldService *services.CustomFieldService
+}
+
+func NewCustomFieldController(fieldService *services.CustomFieldService) *CustomFieldController {
+	return &CustomFieldController{
+		fieldService: fieldService,
+	}
+}
+
+func (ctrl *CustomFieldController) GetFields(c *gin.Context) {
+	projectID := c.Param("id")
+	if projectID == "" {
+		c.AbortWithStatusJSON(404, gin.H{"error": "Not found"})
+		return
+	}
+
+	var user model.User
+	if err := user.GetContext(c); err != nil {
+		c.AbortWithStatusJSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	fields, err := ctrl.fieldService.GetByProject(user.ID, projectID)
+	if err != nil {
+		c.AbortWithStatusJSON(fieldErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.AbortWithStatusJSON(200, gin.H{"data": fields})
+}
+
+func (ctrl *CustomFieldController) Create(c *gin.Context) {
+	projectID := c.Param("id")
+	if projectID == "" {
+		c.AbortWithStatusJSON(404, gin.H{"error": "Not found"})
+		return
+	}
+
+	var user model.User
+	if err := user.GetContext(c); err != nil {
+		c.AbortWithStatusJSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var body schemas.CreateCustomField
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": "Bad request"})
+		return
+	}
+
+	field, err := ctrl.fieldService.Create(user.ID, projectID, body)
+	if err != nil {
+		c.AbortWithStatusJSON(fieldErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.AbortWithStatusJSON(200, gin.H{"data": field})
+}
+
+func (ctrl *CustomFieldController) Update(c *gin.Context) {
+	projectID := c.Param("id")
+	fieldID := c.Param("field_id")
+	if projectID == "" || fieldID == "" {
+		c.AbortWithStatusJSON(404, gin.H{"error": "Not found"})
+		return
+	}
+
+	var user model.User
+	if err := user.GetContext(c); err != nil {
+		c.AbortWithStatusJSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var body schemas.UpdateCustomField
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": "Bad request"})
+		return
+	}
+
+	field, err := ctrl.fieldService.Update(user.ID, projectID, fieldID, body)
+	if err != nil {
+		c.AbortWithStatusJSON(fieldErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.AbortWithStatusJSON(200, gin.H{"data": field})
+}
+
+func (ctrl *CustomFieldController) Delete(c *gin.Context) {
+	projectID := c.Param("id")
+	fieldID := c.Param("field_id")
+	if projectID == "" || fieldID == "" {
+		c.AbortWithStatusJSON(404, gin.H{"error": "Not found"})
+		return
+	}
+
+	var user model.User
+	if err := user.GetContext(c); err != nil {
+		c.AbortWithStatusJSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	if err := ctrl.fieldService.Delete(user.ID, projectID, fieldID); err != nil {
+		c.AbortWithStatusJSON(fieldErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.AbortWithStatusJSON(200, gin.H{"message": "Custom field deleted successfully"})
+}
+
+func fieldErrorCode(err error) int {
+	switch {
+	case strings.Contains(err.Error(), "permission denied"):
+		return 403
+	case strings.Contains(err.Error(), "not found"),
+		strings.Contains(err.Error(), "invalid input syntax for type uuid"):
+		return 404
+	case strings.Contains(err.Error(), "already exists"):
+		return 409
+	case strings.Contains(err.Error(), "invalid custom field"):
+		return 400
+	default:
+		return 500
+	}
+}
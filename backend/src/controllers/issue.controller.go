@@ -1,30 +1,41 @@
 package controllers
 
 import (
+	"errors"
+	"io"
 	"strings"
 	"webservices/src/model"
+	"webservices/src/pkg/jql"
 	"webservices/src/pkg/logger"
+	"webservices/src/pkg/pagination"
 	"webservices/src/services"
+	"webservices/src/types"
 	"webservices/src/types/schemas"
 
 	"github.com/gin-gonic/gin"
 )
 
 type IssueController struct {
-	issueService *services.IssueService
-	notifService *services.NotificationService
-	mailService  *services.MailService
+	issueService    *services.IssueService
+	notifService    *services.NotificationService
+	mailService     *services.MailService
+	filterService   *services.SavedFilterService
+	analyticService *services.AnalyticService
 }
 
 func NewIssueController(
 	issueService *services.IssueService,
 	notifService *services.NotificationService,
 	mailService *services.MailService,
+	filterService *services.SavedFilterService,
+	analyticService *services.AnalyticService,
 ) *IssueController {
 	return &IssueController{
-		issueService: issueService,
-		notifService: notifService,
-		mailService:  mailService,
+		issueService:    issueService,
+		notifService:    notifService,
+		mailService:     mailService,
+		filterService:   filterService,
+		analyticService: analyticService,
 	}
 }
 
@@ -40,13 +51,25 @@ func (ctrl *IssueController) AnalyticIssues(c *gin.Context) {
 		return
 	}
 
-	issues, err := ctrl.issueService.GetByProject(*user.ProjectID)
+	var query schemas.AnalyticQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": "Bad request"})
+		return
+	}
+
+	analytics, err := ctrl.analyticService.GetIssueAnalytics(user.ID, *user.ProjectID, query)
 	if err != nil {
-		c.AbortWithStatusJSON(500, gin.H{"error": err.Error()})
+		code := 500
+		if strings.Contains(err.Error(), "invalid date range") {
+			code = 400
+		} else if strings.Contains(err.Error(), "permission denied") {
+			code = 403
+		}
+		c.AbortWithStatusJSON(code, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.AbortWithStatusJSON(200, gin.H{"data": issues})
+	c.AbortWithStatusJSON(200, gin.H{"data": analytics})
 }
 
 func (ctrl *IssueController) GetIssues(c *gin.Context) {
@@ -63,13 +86,29 @@ func (ctrl *IssueController) GetIssues(c *gin.Context) {
 		return
 	}
 
-	issues, err := ctrl.issueService.GetWithParents(*user.ProjectID, parents)
+	var page schemas.PageQuery
+	if err := c.ShouldBindQuery(&page); err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": "Bad request"})
+		return
+	}
+
+	params, err := pagination.New(page.Limit, page.Cursor)
 	if err != nil {
-		c.AbortWithStatusJSON(500, gin.H{"error": err.Error()})
+		c.AbortWithStatusJSON(400, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.AbortWithStatusJSON(200, gin.H{"data": issues})
+	issues, err := ctrl.issueService.GetWithParents(*user.ProjectID, parents, params)
+	if err != nil {
+		code := 500
+		if strings.Contains(err.Error(), "invalid cursor") {
+			code = 400
+		}
+		c.AbortWithStatusJSON(code, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.AbortWithStatusJSON(200, gin.H{"data": issues.Items, "pagination": issues.Info})
 }
 
 func (ctrl *IssueController) GetIssuesWithFilter(c *gin.Context) {
@@ -91,13 +130,72 @@ func (ctrl *IssueController) GetIssuesWithFilter(c *gin.Context) {
 	}
 
 	projectID := *user.ProjectID
-	issues, err := ctrl.issueService.GetWithFilter(projectID, filter)
+
+	var saved *model.SavedFilter
+	if filter.FilterID != nil && *filter.FilterID != "" {
+		var err error
+		saved, err = ctrl.filterService.GetFilter(user.ID, projectID, *filter.FilterID)
+		if err != nil {
+			code := 500
+			if strings.Contains(err.Error(), "not found") {
+				code = 404
+			}
+			c.AbortWithStatusJSON(code, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	// the board kept whole unless a page asked for
+	var (
+		issues []model.Issue
+		info   *pagination.Info
+		err    error
+	)
+
+	if filter.Limit > 0 || filter.Cursor != "" {
+		var params pagination.Params
+		if params, err = pagination.New(filter.Limit, filter.Cursor); err != nil {
+			c.AbortWithStatusJSON(400, gin.H{"error": err.Error()})
+			return
+		}
+
+		var page *pagination.Page[model.Issue]
+		if page, err = ctrl.issueService.GetPageWithFilter(user.ID, projectID, filter, saved, params); err == nil {
+			issues, info = page.Items, &page.Info
+		}
+	} else {
+		issues, err = ctrl.issueService.GetWithFilter(user.ID, projectID, filter, saved)
+	}
+
 	if err != nil {
-		c.AbortWithStatusJSON(500, gin.H{"error": err.Error()})
+		// the position allow the board to highlight the invalid part of the query
+		var queryErr *jql.Error
+		if errors.As(err, &queryErr) {
+			c.AbortWithStatusJSON(400, gin.H{"error": queryErr.Message, "position": queryErr.Pos, "end": queryErr.End})
+			return
+		}
+
+		code := 500
+		if strings.Contains(err.Error(), "saved filter") ||
+			strings.Contains(err.Error(), "ORDER BY not allowed") ||
+			strings.Contains(err.Error(), "invalid cursor") {
+			code = 400
+		}
+		c.AbortWithStatusJSON(code, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.AbortWithStatusJSON(200, gin.H{"data": issues})
+	response := gin.H{"data": issues}
+	if info != nil {
+		response["pagination"] = info
+	}
+
+	// the saved filter returned along so the board able to apply its grouping
+	if saved != nil {
+		response["filter"] = saved
+	}
+
+	c.AbortWithStatusJSON(200, response)
 }
 
 func (ctrl *IssueController) GetIssueByID(c *gin.Context) {
@@ -146,7 +244,19 @@ func (ctrl *IssueController) GetActivitiesByIssue(c *gin.Context) {
 		return
 	}
 
-	activities, err := ctrl.issueService.GetActivities(id)
+	var page schemas.PageQuery
+	if err := c.ShouldBindQuery(&page); err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": "Bad request"})
+		return
+	}
+
+	params, err := pagination.New(page.Limit, page.Cursor)
+	if err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	activities, err := ctrl.issueService.GetActivities(id, params)
 	if err != nil {
 		code := 500
 		if strings.Contains(err.Error(), "not found") ||
@@ -157,7 +267,7 @@ func (ctrl *IssueController) GetActivitiesByIssue(c *gin.Context) {
 		return
 	}
 
-	c.AbortWithStatusJSON(200, gin.H{"data": activities})
+	c.AbortWithStatusJSON(200, gin.H{"data": activities.Items, "pagination": activities.Info})
 }
 
 func (ctrl *IssueController) Upsert(c *gin.Context) {
@@ -196,20 +306,133 @@ func (ctrl *IssueController) Upsert(c *gin.Context) {
 	}
 
 	if err != nil {
-		c.AbortWithStatusJSON(400, gin.H{"error": err.Error()})
+		code := 400
+		if strings.Contains(err.Error(), "task limit reached") {
+			code = 409
+		}
+		c.AbortWithStatusJSON(code, gin.H{"error": err.Error()})
 		return
 	}
 
+	// asked for a done status, parked waiting the approver
+	reviewRequested := body.Status != "" && body.Status != types.IssueStatusInReview &&
+		issue.Status == types.IssueStatusInReview
+
 	go func() {
 		ctrl.notifService.PushIssue(&user, issue, isCreate)
+		if err := ctrl.notifService.PushMention(&user, issue, nil, issue.Mentions); err != nil {
+			logger.Errorf("failed to push issue mentions: %s", err)
+		}
+
 		if err := ctrl.mailService.IssueAssign(&user, user.Project, issue); err != nil {
 			logger.Errorf("failed to send assign issue email: %s", err)
 		}
+
+		if reviewRequested {
+			if err := ctrl.notifService.PushReviewRequest(&user, issue); err != nil {
+				logger.Errorf("failed to push review request: %s", err)
+			}
+		}
 	}()
 
 	c.AbortWithStatusJSON(200, gin.H{"data": issue})
 }
 
+func (ctrl *IssueController) Bulk(c *gin.Context) {
+	var user model.User
+	if err := user.GetContext(c); err != nil {
+		c.AbortWithStatusJSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var body schemas.BulkIssue
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": "Bad request"})
+		return
+	}
+
+	result, err := ctrl.issueService.Bulk(user.ID, body)
+	if err != nil {
+		code := 400
+		switch {
+		case strings.Contains(err.Error(), "permission denied"):
+			code = 403
+		case strings.Contains(err.Error(), "not found"):
+			code = 404
+		case strings.Contains(err.Error(), "task limit reached"):
+			code = 409
+		}
+		c.AbortWithStatusJSON(code, gin.H{"error": err.Error()})
+		return
+	}
+
+	go func() {
+		ctrl.notifService.PushBulk(&user, result)
+
+		for i := range result.Review {
+			if err := ctrl.notifService.PushReviewRequest(&user, &result.Review[i]); err != nil {
+				logger.Errorf("failed to push review request: %s", err)
+			}
+		}
+	}()
+
+	c.AbortWithStatusJSON(200, gin.H{"data": result})
+}
+
+func (ctrl *IssueController) Approve(c *gin.Context) {
+	ctrl.review(c, true)
+}
+
+func (ctrl *IssueController) Reject(c *gin.Context) {
+	ctrl.review(c, false)
+}
+
+func (ctrl *IssueController) review(c *gin.Context, approve bool) {
+	id := c.Param("id")
+	if id == "" {
+		c.AbortWithStatusJSON(404, gin.H{"error": "Not found"})
+		return
+	}
+
+	var user model.User
+	if err := user.GetContext(c); err != nil {
+		c.AbortWithStatusJSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var body schemas.ReviewIssue
+	if err := c.ShouldBindJSON(&body); err != nil && !errors.Is(err, io.EOF) {
+		c.AbortWithStatusJSON(400, gin.H{"error": "Bad request"})
+		return
+	}
+
+	var (
+		issue *model.Issue
+		err   error
+	)
+
+	if approve {
+		issue, err = ctrl.issueService.Approve(user.ID, id, body.Reason)
+	} else {
+		issue, err = ctrl.issueService.Reject(user.ID, id, body.Reason)
+	}
+
+	if err != nil {
+		code := 400
+		if strings.Contains(err.Error(), "not found") {
+			code = 404
+		} else if strings.Contains(err.Error(), "permission denied") {
+			code = 403
+		}
+		c.AbortWithStatusJSON(code, gin.H{"error": err.Error()})
+		return
+	}
+
+	go ctrl.notifService.PushReviewResult(&user, issue, approve, body.Reason)
+
+	c.AbortWithStatusJSON(200, gin.H{"data": issue})
+}
+
 func (ctrl *IssueController) UpdateOrder(c *gin.Context) {
 	var body schemas.IssueOrder
 	if err := c.ShouldBindJSON(&body); err != nil {
@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"mime/multipart"
 	"strings"
 	"webservices/src/model"
 	"webservices/src/services"
@@ -71,6 +72,44 @@ func (ctrl *IssueItemController) Create(c *gin.Context) {
 	c.AbortWithStatusJSON(200, gin.H{"data": item})
 }
 
+func (ctrl *IssueItemController) Upload(c *gin.Context) {
+	issueID := c.Param("id")
+	if issueID == "" {
+		c.AbortWithStatusJSON(404, gin.H{"error": "not found"})
+		return
+	}
+
+	var user model.User
+	if err := user.GetContext(c); err != nil {
+		c.AbortWithStatusJSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	// files validated & set by the upload middleware
+	files, ok := c.MustGet("attachment").([]*multipart.FileHeader)
+	if !ok || len(files) == 0 {
+		c.AbortWithStatusJSON(400, gin.H{"error": "bad request"})
+		return
+	}
+
+	item, err := ctrl.itemService.Upload(user.ID, issueID, files[0])
+	if err != nil {
+		statusCode := 500
+		if strings.Contains(err.Error(), "not found") {
+			statusCode = 404
+		} else if strings.Contains(err.Error(), "permission denied") ||
+			strings.Contains(err.Error(), "attachments are disabled") {
+			statusCode = 403
+		} else if strings.Contains(err.Error(), "attachment too large") {
+			statusCode = 413
+		}
+		c.AbortWithStatusJSON(statusCode, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.AbortWithStatusJSON(200, gin.H{"data": item})
+}
+
 func (ctrl *IssueItemController) Update(c *gin.Context) {
 	issueID := c.Param("id")
 	itemID := c.Param("item_id")
@@ -0,0 +1,291 @@
+package controllers
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"time"
+	"webservices/src/model"
+	"webservices/src/pkg/logger"
+	"webservices/src/services"
+	"webservices/src/types/schemas"
+
+	"github.com/gin-gonic/gin"
+)
+
+type IssueTemplateController struct {
+	templateService *services.IssueTemplateService
+	notifService    *services.NotificationService
+}
+
+func NewIssueTemplateController(
+	templateService *services.IssueTemplateService,
+	notifService *services.NotificationService,
+) *IssueTemplateController {
+	return &IssueTemplateController{
+		templateService: templateService,
+		notifService:    notifService,
+	}
+}
+
+func (ctrl *IssueTemplateController) GetTemplates(c *gin.Context) {
+	projectID := c.Param("id")
+	if projectID == "" {
+		c.AbortWithStatusJSON(404, gin.H{"error": "Not found"})
+		return
+	}
+
+	var user model.User
+	if err := user.GetContext(c); err != nil {
+		c.AbortWithStatusJSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	templates, err := ctrl.templateService.GetTemplates(user.ID, projectID)
+	if err != nil {
+		c.AbortWithStatusJSON(templateErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.AbortWithStatusJSON(200, gin.H{"data": templates})
+}
+
+func (ctrl *IssueTemplateController) CreateTemplate(c *gin.Context) {
+	projectID := c.Param("id")
+	if projectID == "" {
+		c.AbortWithStatusJSON(404, gin.H{"error": "Not found"})
+		return
+	}
+
+	var user model.User
+	if err := user.GetContext(c); err != nil {
+		c.AbortWithStatusJSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var body schemas.CreateIssueTemplate
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": "Bad request"})
+		return
+	}
+
+	template, err := ctrl.templateService.CreateTemplate(user.ID, projectID, body)
+	if err != nil {
+		c.AbortWithStatusJSON(templateErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.AbortWithStatusJSON(200, gin.H{"data": template})
+}
+
+func (ctrl *IssueTemplateController) UpdateTemplate(c *gin.Context) {
+	projectID := c.Param("id")
+	templateID := c.Param("template_id")
+	if projectID == "" || templateID == "" {
+		c.AbortWithStatusJSON(404, gin.H{"error": "Not found"})
+		return
+	}
+
+	var user model.User
+	if err := user.GetContext(c); err != nil {
+		c.AbortWithStatusJSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var body schemas.CreateIssueTemplate
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": "Bad request"})
+		return
+	}
+
+	template, err := ctrl.templateService.UpdateTemplate(user.ID, projectID, templateID, body)
+	if err != nil {
+		c.AbortWithStatusJSON(templateErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.AbortWithStatusJSON(200, gin.H{"data": template})
+}
+
+func (ctrl *IssueTemplateController) DeleteTemplate(c *gin.Context) {
+	projectID := c.Param("id")
+	templateID := c.Param("template_id")
+	if projectID == "" || templateID == "" {
+		c.AbortWithStatusJSON(404, gin.H{"error": "Not found"})
+		return
+	}
+
+	var user model.User
+	if err := user.GetContext(c); err != nil {
+		c.AbortWithStatusJSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	if err := ctrl.templateService.DeleteTemplate(user.ID, projectID, templateID); err != nil {
+		c.AbortWithStatusJSON(templateErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.AbortWithStatusJSON(200, gin.H{"message": "Template deleted successfully"})
+}
+
+func (ctrl *IssueTemplateController) Apply(c *gin.Context) {
+	projectID := c.Param("id")
+	templateID := c.Param("template_id")
+	if projectID == "" || templateID == "" {
+		c.AbortWithStatusJSON(404, gin.H{"error": "Not found"})
+		return
+	}
+
+	var user model.User
+	if err := user.GetContext(c); err != nil {
+		c.AbortWithStatusJSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var body schemas.ApplyIssueTemplate
+	if err := c.ShouldBindJSON(&body); err != nil && !errors.Is(err, io.EOF) {
+		c.AbortWithStatusJSON(400, gin.H{"error": "Bad request"})
+		return
+	}
+
+	at := time.Now()
+	if body.Date != nil {
+		at = body.Date.Time
+	}
+
+	issue, err := ctrl.templateService.Apply(user.ID, projectID, templateID, at)
+	if err != nil {
+		c.AbortWithStatusJSON(templateErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	go func() {
+		// the notification worded with the active project
+		if user.ProjectID != nil && *user.ProjectID == projectID {
+			ctrl.notifService.PushIssue(&user, issue, true)
+		}
+
+		if err := ctrl.notifService.PushMention(&user, issue, nil, issue.Mentions); err != nil {
+			logger.Errorf("failed to push issue mentions: %s", err)
+		}
+	}()
+
+	c.AbortWithStatusJSON(200, gin.H{"data": issue})
+}
+
+func (ctrl *IssueTemplateController) GetRecurrences(c *gin.Context) {
+	projectID := c.Param("id")
+	if projectID == "" {
+		c.AbortWithStatusJSON(404, gin.H{"error": "Not found"})
+		return
+	}
+
+	var user model.User
+	if err := user.GetContext(c); err != nil {
+		c.AbortWithStatusJSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	recurrences, err := ctrl.templateService.GetRecurrences(user.ID, projectID)
+	if err != nil {
+		c.AbortWithStatusJSON(templateErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.AbortWithStatusJSON(200, gin.H{"data": recurrences})
+}
+
+func (ctrl *IssueTemplateController) CreateRecurrence(c *gin.Context) {
+	projectID := c.Param("id")
+	if projectID == "" {
+		c.AbortWithStatusJSON(404, gin.H{"error": "Not found"})
+		return
+	}
+
+	var user model.User
+	if err := user.GetContext(c); err != nil {
+		c.AbortWithStatusJSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var body schemas.CreateRecurrence
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": "Bad request"})
+		return
+	}
+
+	recurrence, err := ctrl.templateService.CreateRecurrence(user.ID, projectID, body)
+	if err != nil {
+		c.AbortWithStatusJSON(templateErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.AbortWithStatusJSON(200, gin.H{"data": recurrence})
+}
+
+func (ctrl *IssueTemplateController) UpdateRecurrence(c *gin.Context) {
+	projectID := c.Param("id")
+	recurrenceID := c.Param("recurrence_id")
+	if projectID == "" || recurrenceID == "" {
+		c.AbortWithStatusJSON(404, gin.H{"error": "Not found"})
+		return
+	}
+
+	var user model.User
+	if err := user.GetContext(c); err != nil {
+		c.AbortWithStatusJSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var body schemas.CreateRecurrence
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": "Bad request"})
+		return
+	}
+
+	recurrence, err := ctrl.templateService.UpdateRecurrence(user.ID, projectID, recurrenceID, body)
+	if err != nil {
+		c.AbortWithStatusJSON(templateErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.AbortWithStatusJSON(200, gin.H{"data": recurrence})
+}
+
+func (ctrl *IssueTemplateController) DeleteRecurrence(c *gin.Context) {
+	projectID := c.Param("id")
+	recurrenceID := c.Param("recurrence_id")
+	if projectID == "" || recurrenceID == "" {
+		c.AbortWithStatusJSON(404, gin.H{"error": "Not found"})
+		return
+	}
+
+	var user model.User
+	if err := user.GetContext(c); err != nil {
+		c.AbortWithStatusJSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	if err := ctrl.templateService.DeleteRecurrence(user.ID, projectID, recurrenceID); err != nil {
+		c.AbortWithStatusJSON(templateErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.AbortWithStatusJSON(200, gin.H{"message": "Recurrence deleted successfully"})
+}
+
+func templateErrorCode(err error) int {
+	switch {
+	case strings.Contains(err.Error(), "permission denied"):
+		return 403
+	case strings.Contains(err.Error(), "not found"),
+		strings.Contains(err.Error(), "invalid input syntax for type uuid"):
+		return 404
+	case strings.Contains(err.Error(), "already exists"),
+		strings.Contains(err.Error(), "task limit reached"):
+		return 409
+	default:
+		return 400
+	}
+}
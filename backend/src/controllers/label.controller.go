@@ -0,0 +1,135 @@
+package controllers
+
+import (
+	"strings"
+	"webservices/src/model"
+	"webservices/src/services"
+	"webservices/src/types/schemas"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LabelController struct {
+	labelService *services.LabelService
+}
+
+func NewLabelController(labelService *services.LabelService) *LabelController {
+	return &LabelController{
+		labelService: labelService,
+	}
+}
+
+func (ctrl *LabelController) GetLabels(c *gin.Context) {
+	projectID := c.Param("id")
+	if projectID == "" {
+		c.AbortWithStatusJSON(404, gin.H{"error": "Not found"})
+		return
+	}
+
+	var user model.User
+	if err := user.GetContext(c); err != nil {
+		c.AbortWithStatusJSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	labels, err := ctrl.labelService.GetByProject(user.ID, projectID)
+	if err != nil {
+		c.AbortWithStatusJSON(labelErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.AbortWithStatusJSON(200, gin.H{"data": labels})
+}
+
+func (ctrl *LabelController) Create(c *gin.Context) {
+	projectID := c.Param("id")
+	if projectID == "" {
+		c.AbortWithStatusJSON(404, gin.H{"error": "Not found"})
+		return
+	}
+
+	var user model.User
+	if err := user.GetContext(c); err != nil {
+		c.AbortWithStatusJSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var body schemas.CreateLabel
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": "Bad request"})
+		return
+	}
+
+	label, err := ctrl.labelService.Create(user.ID, projectID, body)
+	if err != nil {
+		c.AbortWithStatusJSON(labelErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.AbortWithStatusJSON(200, gin.H{"data": label})
+}
+
+func (ctrl *LabelController) Update(c *gin.Context) {
+	projectID := c.Param("id")
+	labelID := c.Param("label_id")
+	if projectID == "" || labelID == "" {
+		c.AbortWithStatusJSON(404, gin.H{"error": "Not found"})
+		return
+	}
+
+	var user model.User
+	if err := user.GetContext(c); err != nil {
+		c.AbortWithStatusJSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var body schemas.CreateLabel
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": "Bad request"})
+		return
+	}
+
+	label, err := ctrl.labelService.Update(user.ID, projectID, labelID, body)
+	if err != nil {
+		c.AbortWithStatusJSON(labelErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.AbortWithStatusJSON(200, gin.H{"data": label})
+}
+
+func (ctrl *LabelController) Delete(c *gin.Context) {
+	projectID := c.Param("id")
+	labelID := c.Param("label_id")
+	if projectID == "" || labelID == "" {
+		c.AbortWithStatusJSON(404, gin.H{"error": "Not found"})
+		return
+	}
+
+	var user model.User
+	if err := user.GetContext(c); err != nil {
+		c.AbortWithStatusJSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	if err := ctrl.labelService.Delete(user.ID, projectID, labelID); err != nil {
+		c.AbortWithStatusJSON(labelErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.AbortWithStatusJSON(200, gin.H{"message": "Label deleted successfully"})
+}
+
+func labelErrorCode(err error) int {
+	switch {
+	case strings.Contains(err.Error(), "permission denied"):
+		return 403
+	case strings.Contains(err.Error(), "not found"),
+		strings.Contains(err.Error(), "invalid input syntax for type uuid"):
+		return 404
+	case strings.Contains(err.Error(), "already exists"):
+		return 409
+	default:
+		return 400
+	}
+}
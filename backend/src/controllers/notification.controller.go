@@ -5,6 +5,7 @@ import (
 	"strings"
 	"webservices/src/model"
 	"webservices/src/pkg/logger"
+	"webservices/src/pkg/pagination"
 	"webservices/src/services"
 	"webservices/src/types"
 	"webservices/src/types/schemas"
@@ -37,14 +38,26 @@ func (ctrl *NotificationController) GetNotifications(c *gin.Context) {
 		return
 	}
 
-	notifications, err := ctrl.notifService.GetByUser(user.ID)
+	var page schemas.PageQuery
+	if err := c.ShouldBindQuery(&page); err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": "Bad request"})
+		return
+	}
+
+	params, err := pagination.New(page.Limit, page.Cursor)
+	if err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	notifications, err := ctrl.notifService.GetByUser(user.ID, params)
 	if err != nil {
 		message := fmt.Sprintf("failed to fetch issues: %s", err.Error())
 		c.AbortWithStatusJSON(500, gin.H{"error": message})
 		return
 	}
 
-	c.AbortWithStatusJSON(200, gin.H{"data": notifications})
+	c.AbortWithStatusJSON(200, gin.H{"data": notifications.Items, "pagination": notifications.Info})
 }
 
 func (ctrl *NotificationController) InviteProject(c *gin.Context) {
@@ -0,0 +1,53 @@
+package controllers
+
+import (
+	"strings"
+	"webservices/src/model"
+	"webservices/src/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PresenceController struct {
+	presenceService *services.PresenceService
+}
+
+func NewPresenceController(presenceService *services.PresenceService) *PresenceController {
+	return &PresenceController{
+		presenceService: presenceService,
+	}
+}
+
+// GetByProject the current presence, the changes pushed after as `presence:update` to the project room
+func (ctrl *PresenceController) GetByProject(c *gin.Context) {
+	projectID := c.Param("id")
+	if projectID == "" {
+		c.AbortWithStatusJSON(404, gin.H{"error": "Not found"})
+		return
+	}
+
+	var user model.User
+	if err := user.GetContext(c); err != nil {
+		c.AbortWithStatusJSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	presence, err := ctrl.presenceService.GetByProject(user.ID, projectID)
+	if err != nil {
+		c.AbortWithStatusJSON(presenceErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.AbortWithStatusJSON(200, gin.H{"data": presence})
+}
+
+func presenceErrorCode(err error) int {
+	switch {
+	case strings.Contains(err.Error(), "permission denied"):
+		return 403
+	case strings.Contains(err.Error(), "invalid input syntax for type uuid"):
+		return 404
+	default:
+		return 500
+	}
+}
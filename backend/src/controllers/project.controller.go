@@ -195,6 +195,9 @@ func (ctrl *ProjectController) UpdateSetting(c *gin.Context) {
 		code := 500
 		if strings.Contains(err.Error(), "not found") {
 			code = 404
+		} else if strings.Contains(err.Error(), "invalid default status") ||
+			strings.Contains(err.Error(), "invalid approval workflow") {
+			code = 400
 		}
 		c.AbortWithStatusJSON(code, gin.H{"error": err.Error()})
 		return
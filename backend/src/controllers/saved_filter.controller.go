@@ -0,0 +1,170 @@
+package controllers
+
+import (
+	"errors"
+	"strings"
+	"webservices/src/model"
+	"webservices/src/pkg/jql"
+	"webservices/src/services"
+	"webservices/src/types/schemas"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SavedFilterController struct {
+	filterService *services.SavedFilterService
+}
+
+func NewSavedFilterController(filterService *services.SavedFilterService) *SavedFilterController {
+	return &SavedFilterController{
+		filterService: filterService,
+	}
+}
+
+func (ctrl *SavedFilterController) GetFilters(c *gin.Context) {
+	projectID := c.Param("id")
+	if projectID == "" {
+		c.AbortWithStatusJSON(404, gin.H{"error": "Not found"})
+		return
+	}
+
+	var user model.User
+	if err := user.GetContext(c); err != nil {
+		c.AbortWithStatusJSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	filters, err := ctrl.filterService.GetByProject(user.ID, projectID)
+	if err != nil {
+		ctrl.abort(c, err)
+		return
+	}
+
+	c.AbortWithStatusJSON(200, gin.H{"data": filters})
+}
+
+func (ctrl *SavedFilterController) GetFilterByID(c *gin.Context) {
+	projectID := c.Param("id")
+	filterID := c.Param("filter_id")
+	if projectID == "" || filterID == "" {
+		c.AbortWithStatusJSON(404, gin.H{"error": "Not found"})
+		return
+	}
+
+	var user model.User
+	if err := user.GetContext(c); err != nil {
+		c.AbortWithStatusJSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	filter, err := ctrl.filterService.GetFilter(user.ID, projectID, filterID)
+	if err != nil {
+		ctrl.abort(c, err)
+		return
+	}
+
+	c.AbortWithStatusJSON(200, gin.H{"data": filter})
+}
+
+func (ctrl *SavedFilterController) Create(c *gin.Context) {
+	projectID := c.Param("id")
+	if projectID == "" {
+		c.AbortWithStatusJSON(404, gin.H{"error": "Not found"})
+		return
+	}
+
+	var user model.User
+	if err := user.GetContext(c); err != nil {
+		c.AbortWithStatusJSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var body schemas.CreateSavedFilter
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": "Bad request"})
+		return
+	}
+
+	filter, err := ctrl.filterService.Create(user.ID, projectID, body)
+	if err != nil {
+		ctrl.abort(c, err)
+		return
+	}
+
+	c.AbortWithStatusJSON(200, gin.H{"data": filter})
+}
+
+func (ctrl *SavedFilterController) Update(c *gin.Context) {
+	projectID := c.Param("id")
+	filterID := c.Param("filter_id")
+	if projectID == "" || filterID == "" {
+		c.AbortWithStatusJSON(404, gin.H{"error": "Not found"})
+		return
+	}
+
+	var user model.User
+	if err := user.GetContext(c); err != nil {
+		c.AbortWithStatusJSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var body schemas.CreateSavedFilter
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": "Bad request"})
+		return
+	}
+
+	filter, err := ctrl.filterService.Update(user.ID, projectID, filterID, body)
+	if err != nil {
+		ctrl.abort(c, err)
+		return
+	}
+
+	c.AbortWithStatusJSON(200, gin.H{"data": filter})
+}
+
+func (ctrl *SavedFilterController) Delete(c *gin.Context) {
+	projectID := c.Param("id")
+	filterID := c.Param("filter_id")
+	if projectID == "" || filterID == "" {
+		c.AbortWithStatusJSON(404, gin.H{"error": "Not found"})
+		return
+	}
+
+	var user model.User
+	if err := user.GetContext(c); err != nil {
+		c.AbortWithStatusJSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	if err := ctrl.filterService.Delete(user.ID, projectID, filterID); err != nil {
+		ctrl.abort(c, err)
+		return
+	}
+
+	c.AbortWithStatusJSON(200, gin.H{"message": "Filter deleted successfully"})
+}
+
+func (ctrl *SavedFilterController) abort(c *gin.Context, err error) {
+	var queryErr *jql.Error
+	if errors.As(err, &queryErr) {
+		c.AbortWithStatusJSON(400, gin.H{
+			"error":    queryErr.Message,
+			"field":    queryErr.Field,
+			"position": queryErr.Pos,
+			"end":      queryErr.End,
+		})
+		return
+	}
+
+	code := 400
+	switch {
+	case strings.Contains(err.Error(), "permission denied"):
+		code = 403
+	case strings.Contains(err.Error(), "not found"),
+		strings.Contains(err.Error(), "invalid input syntax for type uuid"):
+		code = 404
+	}
+
+	c.AbortWithStatusJSON(code, gin.H{"error": err.Error()})
+}
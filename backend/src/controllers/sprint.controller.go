@@ -0,0 +1,246 @@
+package controllers
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"webservices/src/model"
+	"webservices/src/pkg/logger"
+	"webservices/src/services"
+	"webservices/src/types/schemas"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SprintController struct {
+	sprintService *services.SprintService
+}
+
+func NewSprintController(sprintService *services.SprintService) *SprintController {
+	return &SprintController{
+		sprintService: sprintService,
+	}
+}
+
+func (ctrl *SprintController) GetSprints(c *gin.Context) {
+	var user model.User
+	if err := user.GetContext(c); err != nil {
+		c.AbortWithStatusJSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var filter schemas.FilterSprint
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": "Bad request"})
+		return
+	}
+
+	if user.ProjectID == nil || *user.ProjectID == "" {
+		c.AbortWithStatusJSON(400, gin.H{"error": "failed to fetch: project ID empty"})
+		return
+	}
+
+	sprints, err := ctrl.sprintService.GetByProject(*user.ProjectID, filter.State)
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.AbortWithStatusJSON(200, gin.H{"data": sprints})
+}
+
+func (ctrl *SprintController) GetSprintByID(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		c.AbortWithStatusJSON(404, gin.H{"error": "Not found"})
+		return
+	}
+
+	var user model.User
+	if err := user.GetContext(c); err != nil {
+		c.AbortWithStatusJSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	sprint, err := ctrl.sprintService.GetSprint(id)
+	if err != nil {
+		code := 500
+		if strings.Contains(err.Error(), "not found") ||
+			strings.Contains(err.Error(), "incorrect UUID format") {
+			code = 404
+		}
+		c.AbortWithStatusJSON(code, gin.H{"error": err.Error()})
+		return
+	}
+
+	if user.ProjectID == nil || *user.ProjectID != sprint.ProjectID {
+		c.AbortWithStatusJSON(404, gin.H{"error": "Not found"})
+		return
+	}
+
+	c.AbortWithStatusJSON(200, gin.H{"data": sprint})
+}
+
+func (ctrl *SprintController) Upsert(c *gin.Context) {
+	var user model.User
+	if err := user.GetContext(c); err != nil {
+		c.AbortWithStatusJSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var body schemas.CreateSprint
+	if err := c.ShouldBindJSON(&body); err != nil {
+		logger.Error(err)
+		c.AbortWithStatusJSON(400, gin.H{"error": "Bad request"})
+		return
+	}
+
+	if body.ProjectID == nil {
+		if user.ProjectID == nil {
+			c.AbortWithStatusJSON(400, gin.H{"error": "you dont have any active project"})
+			return
+		}
+		body.ProjectID = user.ProjectID
+	}
+
+	var (
+		sprint *model.Sprint
+		err    error
+	)
+
+	if body.ID == nil || *body.ID == "" {
+		sprint, err = ctrl.sprintService.Create(user.ID, body)
+	} else {
+		sprint, err = ctrl.sprintService.Update(user.ID, body)
+	}
+
+	if err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.AbortWithStatusJSON(200, gin.H{"data": sprint})
+}
+
+func (ctrl *SprintController) Start(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		c.AbortWithStatusJSON(404, gin.H{"error": "Not found"})
+		return
+	}
+
+	var user model.User
+	if err := user.GetContext(c); err != nil {
+		c.AbortWithStatusJSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	sprint, err := ctrl.sprintService.Start(user.ID, id)
+	if err != nil {
+		code := 400
+		if strings.Contains(err.Error(), "not found") {
+			code = 404
+		}
+		c.AbortWithStatusJSON(code, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.AbortWithStatusJSON(200, gin.H{"data": sprint})
+}
+
+func (ctrl *SprintController) Close(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		c.AbortWithStatusJSON(404, gin.H{"error": "Not found"})
+		return
+	}
+
+	var user model.User
+	if err := user.GetContext(c); err != nil {
+		c.AbortWithStatusJSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	// body is optional, closing without it moving unfinished issues to the backlog
+	var body schemas.CloseSprint
+	if err := c.ShouldBindJSON(&body); err != nil && !errors.Is(err, io.EOF) {
+		c.AbortWithStatusJSON(400, gin.H{"error": "Bad request"})
+		return
+	}
+
+	sprint, moved, err := ctrl.sprintService.Close(user.ID, id, body.NextSprintID)
+	if err != nil {
+		code := 400
+		if strings.Contains(err.Error(), "not found") {
+			code = 404
+		}
+		c.AbortWithStatusJSON(code, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.AbortWithStatusJSON(200, gin.H{
+		"data": gin.H{
+			"sprint": sprint,
+			"moved":  moved,
+		},
+	})
+}
+
+func (ctrl *SprintController) MoveIssues(c *gin.Context) {
+	var user model.User
+	if err := user.GetContext(c); err != nil {
+		c.AbortWithStatusJSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var body schemas.AssignSprint
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": "Bad request"})
+		return
+	}
+
+	if user.ProjectID == nil || *user.ProjectID == "" {
+		c.AbortWithStatusJSON(400, gin.H{"error": "you dont have any active project"})
+		return
+	}
+
+	// `/sprint/backlog` has no id param, moving the issues out of any sprint
+	var sprintID *string
+	if id := c.Param("id"); id != "" {
+		sprintID = &id
+	}
+
+	issues, err := ctrl.sprintService.MoveIssues(user.ID, *user.ProjectID, sprintID, body.IssueIDs)
+	if err != nil {
+		code := 400
+		if strings.Contains(err.Error(), "not found") {
+			code = 404
+		}
+		c.AbortWithStatusJSON(code, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.AbortWithStatusJSON(200, gin.H{"data": issues})
+}
+
+func (ctrl *SprintController) Delete(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		c.AbortWithStatusJSON(404, gin.H{"error": "Not found"})
+		return
+	}
+
+	var user model.User
+	if err := user.GetContext(c); err != nil {
+		c.AbortWithStatusJSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	if err := ctrl.sprintService.Delete(user.ID, id); err != nil {
+		logger.Errorf("failed to delete sprint: %s", err)
+		c.AbortWithStatusJSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.AbortWithStatusJSON(200, gin.H{"message": "Sprint deleted successfully"})
+}
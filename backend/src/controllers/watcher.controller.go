@@ -0,0 +1,93 @@
+package controllers
+
+import (
+	"strings"
+	"webservices/src/model"
+	"webservices/src/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WatcherController struct {
+	watcherService *services.WatcherService
+}
+
+func NewWatcherController(watcherService *services.WatcherService) *WatcherController {
+	return &WatcherController{
+		watcherService: watcherService,
+	}
+}
+
+func (ctrl *WatcherController) GetWatchers(c *gin.Context) {
+	issueID := c.Param("id")
+	if issueID == "" {
+		c.AbortWithStatusJSON(404, gin.H{"error": "Not found"})
+		return
+	}
+
+	var user model.User
+	if err := user.GetContext(c); err != nil {
+		c.AbortWithStatusJSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	watchers, err := ctrl.watcherService.GetByIssue(user.ID, issueID)
+	if err != nil {
+		c.AbortWithStatusJSON(watcherErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.AbortWithStatusJSON(200, gin.H{"data": watchers})
+}
+
+func (ctrl *WatcherController) Watch(c *gin.Context) {
+	ctrl.set(c, true)
+}
+
+func (ctrl *WatcherController) Unwatch(c *gin.Context) {
+	ctrl.set(c, false)
+}
+
+func (ctrl *WatcherController) set(c *gin.Context, watching bool) {
+	issueID := c.Param("id")
+	if issueID == "" {
+		c.AbortWithStatusJSON(404, gin.H{"error": "Not found"})
+		return
+	}
+
+	var user model.User
+	if err := user.GetContext(c); err != nil {
+		c.AbortWithStatusJSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var (
+		watchers []model.IssueWatcher
+		err      error
+	)
+
+	if watching {
+		watchers, err = ctrl.watcherService.Watch(user.ID, issueID)
+	} else {
+		watchers, err = ctrl.watcherService.Unwatch(user.ID, issueID)
+	}
+
+	if err != nil {
+		c.AbortWithStatusJSON(watcherErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.AbortWithStatusJSON(200, gin.H{"data": watchers})
+}
+
+func watcherErrorCode(err error) int {
+	switch {
+	case strings.Contains(err.Error(), "permission denied"):
+		return 403
+	case strings.Contains(err.Error(), "not found"),
+		strings.Contains(err.Error(), "invalid input syntax for type uuid"):
+		return 404
+	default:
+		return 500
+	}
+}
@@ -0,0 +1,84 @@
+package controllers
+
+import (
+	"strings"
+	"webservices/src/model"
+	"webservices/src/services"
+	"webservices/src/types/schemas"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WorkflowController struct {
+	workflowService *services.WorkflowService
+}
+
+func NewWorkflowController(workflowService *services.WorkflowService) *WorkflowController {
+	return &WorkflowController{
+		workflowService: workflowService,
+	}
+}
+
+func (ctrl *WorkflowController) GetWorkflow(c *gin.Context) {
+	projectID := c.Param("id")
+	if projectID == "" {
+		c.AbortWithStatusJSON(404, gin.H{"error": "Not found"})
+		return
+	}
+
+	var user model.User
+	if err := user.GetContext(c); err != nil {
+		c.AbortWithStatusJSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	workflow, err := ctrl.workflowService.GetByProject(user.ID, projectID)
+	if err != nil {
+		c.AbortWithStatusJSON(workflowErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.AbortWithStatusJSON(200, gin.H{"data": workflow})
+}
+
+func (ctrl *WorkflowController) Update(c *gin.Context) {
+	projectID := c.Param("id")
+	if projectID == "" {
+		c.AbortWithStatusJSON(404, gin.H{"error": "Not found"})
+		return
+	}
+
+	var user model.User
+	if err := user.GetContext(c); err != nil {
+		c.AbortWithStatusJSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var body schemas.UpdateWorkflow
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": "Bad request"})
+		return
+	}
+
+	workflow, err := ctrl.workflowService.Update(user.ID, projectID, body)
+	if err != nil {
+		c.AbortWithStatusJSON(workflowErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.AbortWithStatusJSON(200, gin.H{"data": workflow})
+}
+
+func workflowErrorCode(err error) int {
+	switch {
+	case strings.Contains(err.Error(), "permission denied"):
+		return 403
+	case strings.Contains(err.Error(), "not found"),
+		strings.Contains(err.Error(), "invalid input syntax for type uuid"):
+		return 404
+	case strings.Contains(err.Error(), "invalid workflow"):
+		return 400
+	default:
+		return 500
+	}
+}
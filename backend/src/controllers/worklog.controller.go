@@ -0,0 +1,177 @@
+package controllers
+
+import (
+	"strings"
+	"webservices/src/model"
+	"webservices/src/services"
+	"webservices/src/types/schemas"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WorklogController struct {
+	worklogService *services.WorklogService
+}
+
+func NewWorklogController(worklogService *services.WorklogService) *WorklogController {
+	return &WorklogController{
+		worklogService: worklogService,
+	}
+}
+
+func (ctrl *WorklogController) GetWorklogs(c *gin.Context) {
+	issueID := c.Param("id")
+	if issueID == "" {
+		c.AbortWithStatusJSON(404, gin.H{"error": "not found"})
+		return
+	}
+
+	var user model.User
+	if err := user.GetContext(c); err != nil {
+		c.AbortWithStatusJSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	summary, worklogs, err := ctrl.worklogService.GetByIssue(user.ID, issueID)
+	if err != nil {
+		statusCode := 500
+		if strings.Contains(err.Error(), "not found") {
+			statusCode = 404
+		}
+		c.AbortWithStatusJSON(statusCode, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.AbortWithStatusJSON(200, gin.H{
+		"data": gin.H{
+			"summary":  summary,
+			"worklogs": worklogs,
+		},
+	})
+}
+
+func (ctrl *WorklogController) Create(c *gin.Context) {
+	issueID := c.Param("id")
+	if issueID == "" {
+		c.AbortWithStatusJSON(404, gin.H{"error": "not found"})
+		return
+	}
+
+	var user model.User
+	if err := user.GetContext(c); err != nil {
+		c.AbortWithStatusJSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var body schemas.CreateWorklog
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": "bad request"})
+		return
+	}
+
+	worklog, err := ctrl.worklogService.Create(user.ID, issueID, body)
+	if err != nil {
+		statusCode := 400
+		if strings.Contains(err.Error(), "not found") {
+			statusCode = 404
+		}
+		c.AbortWithStatusJSON(statusCode, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.AbortWithStatusJSON(200, gin.H{"data": worklog})
+}
+
+func (ctrl *WorklogController) Update(c *gin.Context) {
+	issueID := c.Param("id")
+	worklogID := c.Param("worklog_id")
+
+	if issueID == "" || worklogID == "" {
+		c.AbortWithStatusJSON(404, gin.H{"error": "not found"})
+		return
+	}
+
+	var user model.User
+	if err := user.GetContext(c); err != nil {
+		c.AbortWithStatusJSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var body schemas.CreateWorklog
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": "bad request"})
+		return
+	}
+
+	worklog, err := ctrl.worklogService.Update(worklogID, user.ID, body)
+	if err != nil {
+		statusCode := 400
+		if strings.Contains(err.Error(), "not found") {
+			statusCode = 404
+		}
+		c.AbortWithStatusJSON(statusCode, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.AbortWithStatusJSON(200, gin.H{"data": worklog})
+}
+
+func (ctrl *WorklogController) Delete(c *gin.Context) {
+	issueID := c.Param("id")
+	worklogID := c.Param("worklog_id")
+
+	if issueID == "" || worklogID == "" {
+		c.AbortWithStatusJSON(404, gin.H{"error": "not found"})
+		return
+	}
+
+	var user model.User
+	if err := user.GetContext(c); err != nil {
+		c.AbortWithStatusJSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	worklog, err := ctrl.worklogService.Delete(worklogID, user.ID)
+	if err != nil {
+		statusCode := 400
+		if strings.Contains(err.Error(), "not found") {
+			statusCode = 404
+		}
+		c.AbortWithStatusJSON(statusCode, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.AbortWithStatusJSON(200, gin.H{"data": worklog})
+}
+
+func (ctrl *WorklogController) UpdateEstimate(c *gin.Context) {
+	issueID := c.Param("id")
+	if issueID == "" {
+		c.AbortWithStatusJSON(404, gin.H{"error": "not found"})
+		return
+	}
+
+	var user model.User
+	if err := user.GetContext(c); err != nil {
+		c.AbortWithStatusJSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var body schemas.UpdateEstimate
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": "bad request"})
+		return
+	}
+
+	summary, err := ctrl.worklogService.UpdateEstimate(user.ID, issueID, body)
+	if err != nil {
+		statusCode := 400
+		if strings.Contains(err.Error(), "not found") {
+			statusCode = 404
+		}
+		c.AbortWithStatusJSON(statusCode, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.AbortWithStatusJSON(200, gin.H{"data": summary})
+}
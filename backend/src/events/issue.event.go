@@ -0,0 +1,62 @@
+package events
+
+import (
+	"webservices/src/model"
+	"webservices/src/services"
+
+	s "github.com/zishang520/socket.io/v2/socket"
+)
+
+type IssueEvent struct {
+	user         *model.User
+	socket       *s.Socket
+	issueService *services.IssueService
+}
+
+func NewIssueEvent(
+	user *model.User,
+	socket *s.Socket,
+	issueService *services.IssueService,
+) *IssueEvent {
+	return &IssueEvent{
+		user:         user,
+		socket:       socket,
+		issueService: issueService,
+	}
+}
+
+// Join the issue room, receiving the changes of the issue, its comments & items as `services.Delta`
+func (e *IssueEvent) Join(a ...any) {
+	issueID, ok := stringArg(a)
+	if !ok {
+		e.socket.Emit("issue:error", "issue id required")
+		return
+	}
+
+	if err := e.issueService.ValidateAccess(e.user.ID, issueID); err != nil {
+		e.socket.Emit("issue:error", err.Error())
+		return
+	}
+
+	e.socket.Join(s.Room(services.IssueRoom(issueID)))
+	e.socket.Emit("issue:joined", issueID)
+}
+
+func (e *IssueEvent) Leave(a ...any) {
+	issueID, ok := stringArg(a)
+	if !ok {
+		return
+	}
+
+	e.socket.Leave(s.Room(services.IssueRoom(issueID)))
+}
+
+// stringArg the first event argument, the room ID
+func stringArg(a []any) (string, bool) {
+	if len(a) == 0 {
+		return "", false
+	}
+
+	value, ok := a[0].(string)
+	return value, ok && value != ""
+}
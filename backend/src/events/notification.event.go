@@ -3,6 +3,7 @@ package events
 import (
 	"webservices/src/model"
 	"webservices/src/pkg/logger"
+	"webservices/src/pkg/pagination"
 	"webservices/src/services"
 
 	s "github.com/zishang520/socket.io/v2/socket"
@@ -26,13 +27,14 @@ func NewNotificationEvent(
 	}
 }
 
+// GetByUser emit the latest page only, the older ones fetched through `GET /notifications`
 func (e *NotificationEvent) GetByUser(a ...any) {
-	notifications, err := e.notifService.GetByUser(e.user.ID)
+	notifications, err := e.notifService.GetByUser(e.user.ID, pagination.Params{Limit: pagination.DefaultLimit})
 	if err != nil {
 		e.socket.Emit("notification:error", err)
 		return
 	}
-	e.socket.Emit("notification:get", notifications)
+	e.socket.Emit("notification:get", notifications.Items)
 }
 
 func (e *NotificationEvent) Read(a ...any) {
@@ -0,0 +1,80 @@
+package events
+
+import (
+	"webservices/src/model"
+	"webservices/src/pkg/common"
+	"webservices/src/pkg/logger"
+	"webservices/src/services"
+	"webservices/src/types"
+	"webservices/src/types/schemas"
+
+	s "github.com/zishang520/socket.io/v2/socket"
+)
+
+type PresenceEvent struct {
+	user            *model.User
+	socket          *s.Socket
+	presenceService *services.PresenceService
+}
+
+func NewPresenceEvent(
+	user *model.User,
+	socket *s.Socket,
+	presenceService *services.PresenceService,
+) *PresenceEvent {
+	return &PresenceEvent{
+		user:            user,
+		socket:          socket,
+		presenceService: presenceService,
+	}
+}
+
+func (e *PresenceEvent) Connect() {
+	if err := e.presenceService.Connect(e.user.ID, string(e.socket.Id())); err != nil {
+		logger.Errorf("failed to open presence session socket_id=%s err=%s", e.socket.Id(), err)
+	}
+}
+
+func (e *PresenceEvent) Disconnect() {
+	if err := e.presenceService.Disconnect(string(e.socket.Id())); err != nil {
+		logger.Errorf("failed to close presence session socket_id=%s err=%s", e.socket.Id(), err)
+	}
+}
+
+// Ping sent by the client every minute, the silent sessions expire
+func (e *PresenceEvent) Ping(a ...any) {
+	if err := e.presenceService.Ping(e.user.ID, string(e.socket.Id())); err != nil {
+		e.socket.Emit("presence:error", err.Error())
+	}
+}
+
+// Status "online" or "away", following the client focus & idle time
+func (e *PresenceEvent) Status(a ...any) {
+	status, ok := stringArg(a)
+	if !ok {
+		e.socket.Emit("presence:error", "status required")
+		return
+	}
+
+	if err := e.presenceService.SetStatus(e.user.ID, string(e.socket.Id()),
+		types.PresenceStatus(status)); err != nil {
+		e.socket.Emit("presence:error", err.Error())
+	}
+}
+
+// Focus the issue open, see `schemas.FocusPresence`. no payload once closed
+func (e *PresenceEvent) Focus(a ...any) {
+	var focus schemas.FocusPresence
+	if len(a) > 0 && a[0] != nil {
+		var err error
+		if focus, err = common.BindMap[schemas.FocusPresence](a[0]); err != nil {
+			e.socket.Emit("presence:error", err.Error())
+			return
+		}
+	}
+
+	if err := e.presenceService.Focus(e.user.ID, string(e.socket.Id()),
+		focus.IssueID, focus.Editing); err != nil {
+		e.socket.Emit("presence:error", err.Error())
+	}
+}
@@ -0,0 +1,52 @@
+package events
+
+import (
+	"webservices/src/model"
+	"webservices/src/services"
+
+	s "github.com/zishang520/socket.io/v2/socket"
+)
+
+type ProjectEvent struct {
+	user           *model.User
+	socket         *s.Socket
+	projectService *services.ProjectService
+}
+
+func NewProjectEvent(
+	user *model.User,
+	socket *s.Socket,
+	projectService *services.ProjectService,
+) *ProjectEvent {
+	return &ProjectEvent{
+		user:           user,
+		socket:         socket,
+		projectService: projectService,
+	}
+}
+
+// Join the project room, receiving the board changes as `services.Delta`
+func (e *ProjectEvent) Join(a ...any) {
+	projectID, ok := stringArg(a)
+	if !ok {
+		e.socket.Emit("project:error", "project id required")
+		return
+	}
+
+	if err := e.projectService.ValidateAccess(e.user.ID, projectID); err != nil {
+		e.socket.Emit("project:error", err.Error())
+		return
+	}
+
+	e.socket.Join(s.Room(services.ProjectRoom(projectID)))
+	e.socket.Emit("project:joined", projectID)
+}
+
+func (e *ProjectEvent) Leave(a ...any) {
+	projectID, ok := stringArg(a)
+	if !ok {
+		return
+	}
+
+	e.socket.Leave(s.Room(services.ProjectRoom(projectID)))
+}
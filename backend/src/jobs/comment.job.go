@@ -0,0 +1,33 @@
+package jobs
+
+import (
+	"context"
+	"time"
+	"webservices/src/pkg/logger"
+	"webservices/src/services"
+)
+
+type CommentJob struct {
+	commentService *services.CommentService
+}
+
+func NewCommentJob(commentService *services.CommentService) *CommentJob {
+	return &CommentJob{
+		commentService: commentService,
+	}
+}
+
+// Purge erase the message of the comments deleted past the retention,
+// the placeholders kept so the replies stay threaded
+func (j *CommentJob) Purge(ctx context.Context) error {
+	purged, err := j.commentService.Purge(time.Now())
+	if err != nil {
+		return err
+	}
+
+	if purged > 0 {
+		logger.Infof("Deleted comments purged: %d", purged)
+	}
+
+	return nil
+}
@@ -0,0 +1,47 @@
+package jobs
+
+import (
+	"context"
+	"strconv"
+	"time"
+	c "webservices/src/pkg/common"
+	"webservices/src/pkg/logger"
+	"webservices/src/services"
+)
+
+type DigestJob struct {
+	digestService *services.DigestService
+	hour          int
+}
+
+func NewDigestJob(digestService *services.DigestService) *DigestJob {
+	hour, err := strconv.Atoi(c.Env("DIGEST_HOUR", "8"))
+	if err != nil || hour < 0 || hour > 23 {
+		hour = 8
+	}
+
+	return &DigestJob{
+		digestService: digestService,
+		hour:          hour,
+	}
+}
+
+// Run send the daily digest once the `DIGEST_HOUR` passed, the digest log
+// keep it sent once a day even the job run hourly or the server restarted
+func (j *DigestJob) Run(ctx context.Context) error {
+	now := time.Now()
+	if now.Hour() < j.hour {
+		return nil
+	}
+
+	sent, err := j.digestService.Send(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	if sent > 0 {
+		logger.Infof("Daily digest sent to %d recipient(s)", sent)
+	}
+
+	return nil
+}
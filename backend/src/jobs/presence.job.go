@@ -0,0 +1,32 @@
+package jobs
+
+import (
+	"context"
+	"time"
+	"webservices/src/pkg/logger"
+	"webservices/src/services"
+)
+
+type PresenceJob struct {
+	presenceService *services.PresenceService
+}
+
+func NewPresenceJob(presenceService *services.PresenceService) *PresenceJob {
+	return &PresenceJob{
+		presenceService: presenceService,
+	}
+}
+
+// Run expire the sessions of the sockets lost without a disconnect, e.g. the server restarted
+func (j *PresenceJob) Run(ctx context.Context) error {
+	expired, err := j.presenceService.Expire(time.Now())
+	if err != nil {
+		return err
+	}
+
+	if expired > 0 {
+		logger.Debugf("Presence sessions expired: %d", expired)
+	}
+
+	return nil
+}
@@ -0,0 +1,33 @@
+package jobs
+
+import (
+	"context"
+	"time"
+	"webservices/src/pkg/logger"
+	"webservices/src/services"
+)
+
+type RecurrenceJob struct {
+	templateService *services.IssueTemplateService
+}
+
+func NewRecurrenceJob(templateService *services.IssueTemplateService) *RecurrenceJob {
+	return &RecurrenceJob{
+		templateService: templateService,
+	}
+}
+
+// Run create the issues of the recurrences due today, the recurrence runs
+// keep each occurrence generated once even the server restarted
+func (j *RecurrenceJob) Run(ctx context.Context) error {
+	created, err := j.templateService.Run(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if created > 0 {
+		logger.Infof("Recurring issues created: %d", created)
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package jobs
+
+import (
+	"context"
+	"time"
+	"webservices/src/pkg/logger"
+	"webservices/src/services"
+	"webservices/src/types"
+)
+
+// DueSoonWindow how far ahead an issue due date counted as "due soon"
+const DueSoonWindow = 24 * time.Hour
+
+type ReminderJob struct {
+	issueService *services.IssueService
+	notifService *services.NotificationService
+}
+
+func NewReminderJob(issueService *services.IssueService, notifService *services.NotificationService) *ReminderJob {
+	return &ReminderJob{
+		issueService: issueService,
+		notifService: notifService,
+	}
+}
+
+// Run scan the open issues and push the "due soon" / "overdue" reminder,
+// following the project `NotifyOnDueDate` & `NotifyOnOverdue` settings
+func (j *ReminderJob) Run(ctx context.Context) error {
+	now := time.Now()
+	issues, err := j.issueService.GetDueBefore(now.Add(DueSoonWindow))
+	if err != nil {
+		return err
+	}
+
+	for i := range issues {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		issue := &issues[i]
+		settings := issue.Project.Setting
+
+		threshold := types.ReminderDueSoon
+		if issue.DueDate.Before(now) {
+			threshold = types.ReminderOverdue
+		}
+
+		if settings != nil {
+			if threshold == types.ReminderDueSoon && !settings.NotifyOnDueDate ||
+				threshold == types.ReminderOverdue && !settings.NotifyOnOverdue {
+				continue
+			}
+		}
+
+		if err := j.notifService.PushDueReminder(issue, threshold); err != nil {
+			logger.Errorf("failed to push reminder issue_id=%s: %s", issue.ID, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package jobs
+
+import (
+	"context"
+	"sync"
+	"time"
+	"webservices/src/pkg/logger"
+)
+
+type task struct {
+	name     string
+	interval time.Duration
+	run      func(ctx context.Context) error
+}
+
+// Scheduler running the registered tasks periodically in background until the context is done
+type Scheduler struct {
+	tasks []task
+	wg    sync.WaitGroup
+}
+
+func NewScheduler() *Scheduler {
+	return &Scheduler{}
+}
+
+func (s *Scheduler) Every(interval time.Duration, name string, run func(ctx context.Context) error) {
+	s.tasks = append(s.tasks, task{
+		name:     name,
+		interval: interval,
+		run:      run,
+	})
+}
+
+func (s *Scheduler) Start(ctx context.Context) {
+	for _, t := range s.tasks {
+		s.wg.Add(1)
+		go s.loop(ctx, t)
+	}
+	logger.Infof("Scheduler started with %d task(s)", len(s.tasks))
+}
+
+// Wait blocking until all the running tasks stopped
+func (s *Scheduler) Wait() {
+	s.wg.Wait()
+}
+
+func (s *Scheduler) loop(ctx context.Context, t task) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+
+	// run once on start, the missed ticks while the server down are catch up here
+	s.exec(ctx, t)
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Infof("Scheduler task %s stopped", t.name)
+			return
+		case <-ticker.C:
+			s.exec(ctx, t)
+		}
+	}
+}
+
+func (s *Scheduler) exec(ctx context.Context, t task) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Errorf("Scheduler task %s panic: %v", t.name, r)
+		}
+	}()
+
+	if err := t.run(ctx); err != nil && ctx.Err() == nil {
+		logger.Errorf("Scheduler task %s failed: %s", t.name, err)
+	}
+}
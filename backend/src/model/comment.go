@@ -3,15 +3,25 @@ package model
 import "time"
 
 type Comment struct {
-	ID        string    `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()" json:"id"`
-	UserID    string    `gorm:"type:uuid;index" json:"userId"`
-	IssueID   string    `gorm:"type:uuid;index" json:"issueId"`
-	Message   string    `json:"message"`
-	CreatedAt time.Time `gorm:"column:created_at;default:now();<-:create" json:"createdAt"`
-	UpdatedAt time.Time `gorm:"column:updated_at;autoUpdateTime" json:"updatedAt"`
+	ID        string     `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()" json:"id"`
+	UserID    string     `gorm:"type:uuid;index" json:"userId"`
+	IssueID   string     `gorm:"type:uuid;index" json:"issueId"`
+	ParentID  *string    `gorm:"type:uuid;column:parent_id" json:"parentId,omitempty" comment:"the comment replied to"`
+	ThreadID  *string    `gorm:"type:uuid;column:thread_id;index" json:"threadId,omitempty" comment:"the top-level comment of the thread, empty on the top-level ones"`
+	Message   string     `json:"message"`
+	CreatedAt time.Time  `gorm:"column:created_at;default:now();<-:create" json:"createdAt"`
+	UpdatedAt time.Time  `gorm:"column:updated_at;autoUpdateTime" json:"updatedAt"`
+	DeletedAt *time.Time `gorm:"column:deleted_at;index" json:"deletedAt,omitempty" comment:"soft deleted, the message kept for the restore"`
+	DeletedBy *string    `gorm:"type:uuid;column:deleted_by" json:"deletedBy,omitempty"`
+	Mentions  []string   `gorm:"-:all" json:"mentions,omitempty" comment:"the users newly mentioned by the last change"`
 
-	User  User  `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"user,omitzero"`
-	Issue Issue `gorm:"foreignKey:IssueID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"issue,omitzero"`
+	Replies   []Comment         `gorm:"-:all" json:"replies,omitempty" comment:"filled by the comment tree"`
+	Reactions []ReactionSummary `gorm:"-:all" json:"reactions,omitempty"`
+
+	User   User     `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"user,omitzero"`
+	Issue  Issue    `gorm:"foreignKey:IssueID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"issue,omitzero"`
+	Parent *Comment `gorm:"foreignKey:ParentID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+	Thread *Comment `gorm:"foreignKey:ThreadID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
 
 	Activities []RecentActivity `gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"activities,omitempty"`
 }
@@ -19,3 +29,14 @@ type Comment struct {
 func (Comment) TableName() string {
 	return "comments"
 }
+
+// Redact the placeholder left of a deleted comment, keeping its place in the thread
+func (c *Comment) Redact() {
+	if c.DeletedAt == nil {
+		return
+	}
+
+	c.Message = ""
+	c.Mentions = nil
+	c.Reactions = nil
+}
@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+// CommentReaction an emoji left by the user on the comment, once per emoji
+type CommentReaction struct {
+	ID        string    `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()" json:"id"`
+	CommentID string    `gorm:"type:uuid;not null;uniqueIndex:idx_comment_reaction" json:"commentId"`
+	UserID    string    `gorm:"type:uuid;not null;uniqueIndex:idx_comment_reaction" json:"userId"`
+	Emoji     string    `gorm:"type:varchar(32);not null;uniqueIndex:idx_comment_reaction" json:"emoji"`
+	CreatedAt time.Time `gorm:"column:created_at;default:now();<-:create" json:"createdAt"`
+
+	Comment Comment `gorm:"foreignKey:CommentID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+	User    User    `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+}
+
+func (CommentReaction) TableName() string {
+	return "comment_reactions"
+}
+
+// ReactionSummary the users reacted with the emoji, ordered by the first reaction
+type ReactionSummary struct {
+	Emoji   string   `json:"emoji"`
+	Count   int      `json:"count"`
+	UserIDs []string `json:"userIds"`
+}
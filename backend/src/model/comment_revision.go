@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// CommentRevision the message of the comment replaced by an edit
+type CommentRevision struct {
+	ID        string    `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()" json:"id"`
+	CommentID string    `gorm:"type:uuid;not null;index" json:"commentId"`
+	UserID    string    `gorm:"type:uuid;not null" json:"userId" comment:"the editor"`
+	Message   string    `json:"message" comment:"the message before the edit"`
+	CreatedAt time.Time `gorm:"column:created_at;default:now();<-:create" json:"createdAt" comment:"the edit time"`
+
+	Comment Comment `gorm:"foreignKey:CommentID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+	User    User    `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"user,omitzero"`
+}
+
+func (CommentRevision) TableName() string {
+	return "comment_revisions"
+}
@@ -0,0 +1,37 @@
+package model
+
+import (
+	"time"
+	"webservices/src/types"
+
+	"gorm.io/datatypes"
+)
+
+// CustomField a project defined issue field, the values stored on `Issue.CustomFields` by its `Key`
+type CustomField struct {
+	ID        string                      `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()" json:"id"`
+	ProjectID string                      `gorm:"type:uuid;not null;uniqueIndex:idx_custom_field_key" json:"projectId"`
+	Key       string                      `gorm:"type:varchar(40);not null;uniqueIndex:idx_custom_field_key" json:"key" comment:"immutable, referenced by the issues & the queries as cf.<key>"`
+	Name      string                      `gorm:"not null" json:"name"`
+	Type      types.CustomFieldType       `gorm:"type:custom_field_type;not null" json:"type"`
+	Options   datatypes.JSONSlice[string] `gorm:"type:jsonb;not null;default:'[]'" json:"options"`
+	Required  bool                        `gorm:"not null;default:false" json:"required"`
+	Position  int                         `gorm:"not null;default:0" json:"position"`
+	CreatedAt time.Time                   `gorm:"column:created_at;default:now();<-:create" json:"createdAt"`
+	UpdatedAt time.Time                   `gorm:"column:updated_at;autoUpdateTime" json:"updatedAt"`
+
+	Project Project `gorm:"foreignKey:ProjectID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"project,omitzero"`
+}
+
+func (CustomField) TableName() string {
+	return "custom_fields"
+}
+
+func (f *CustomField) HasOption(option string) bool {
+	for _, o := range f.Options {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
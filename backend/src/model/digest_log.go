@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// DigestLog keep track the daily digest already sent, one per user per project a day
+type DigestLog struct {
+	ID        string    `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()" json:"id"`
+	UserID    string    `gorm:"type:uuid;not null;uniqueIndex:idx_digest_log" json:"userId"`
+	ProjectID string    `gorm:"type:uuid;not null;uniqueIndex:idx_digest_log" json:"projectId"`
+	Date      time.Time `gorm:"type:date;not null;uniqueIndex:idx_digest_log" json:"date"`
+	CreatedAt time.Time `gorm:"column:created_at;default:now();<-:create" json:"createdAt"`
+
+	User    User    `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+	Project Project `gorm:"foreignKey:ProjectID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+}
+
+func (DigestLog) TableName() string {
+	return "digest_logs"
+}
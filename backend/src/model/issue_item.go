@@ -6,18 +6,24 @@ import (
 )
 
 type IssueItem struct {
-	ID        string              `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()" json:"id"`
-	IssueID   string              `gorm:"type:uuid;index" json:"issueId"`
-	Type      types.IssueItemType `gorm:"not null;type:issue_item_type;" json:"type"`
-	AssetID   *string             `json:"assetId"`
-	PublicID  *string             `json:"publicId"`
-	Url       *string             `json:"url"`
-	Text      *string             `json:"text"`
-	CreatedAt time.Time           `gorm:"column:created_at;default:now();<-:create" json:"createdAt"`
-	UpdatedAt time.Time           `gorm:"column:updated_at;autoUpdateTime" json:"updatedAt"`
+	ID            string               `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()" json:"id"`
+	IssueID       string               `gorm:"type:uuid;index" json:"issueId"`
+	Type          types.IssueItemType  `gorm:"not null;type:issue_item_type;" json:"type"`
+	AssetID       *string              `json:"assetId"`
+	PublicID      *string              `json:"publicId"`
+	Url           *string              `json:"url"`
+	Text          *string              `json:"text"`
+	Storage       *string              `json:"storage,omitempty" comment:"storage driver when uploaded to the server, the blob key kept on public_id"`
+	Size          *int64               `json:"size,omitempty"`     // bytes
+	MimeType      *string              `json:"mimeType,omitempty"` // uploaded file content type
+	LinkType      *types.IssueLinkType `gorm:"type:issue_link_type" json:"linkType,omitempty"`
+	LinkedIssueID *string              `gorm:"type:uuid;index" json:"linkedIssueId,omitempty"`
+	CreatedAt     time.Time            `gorm:"column:created_at;default:now();<-:create" json:"createdAt"`
+	UpdatedAt     time.Time            `gorm:"column:updated_at;autoUpdateTime" json:"updatedAt"`
 
-	Issue      Issue            `gorm:"foreignKey:IssueID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"issue,omitzero"`
-	Activities []RecentActivity `gorm:"foreignKey:ItemID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"activities,omitempty"`
+	Issue       Issue            `gorm:"foreignKey:IssueID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"issue,omitzero"`
+	LinkedIssue *Issue           `gorm:"foreignKey:LinkedIssueID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"linkedIssue,omitempty"`
+	Activities  []RecentActivity `gorm:"foreignKey:ItemID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"activities,omitempty"`
 }
 
 func (IssueItem) TableName() string {
@@ -0,0 +1,43 @@
+package model
+
+import "time"
+
+// IssueRecurrence create an issue from the template on every occurrence of the `Rule`
+type IssueRecurrence struct {
+	ID         string     `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()" json:"id"`
+	ProjectID  string     `gorm:"type:uuid;not null;index" json:"projectId"`
+	TemplateID string     `gorm:"type:uuid;not null;index" json:"templateId"`
+	Rule       string     `gorm:"not null" json:"rule" comment:"see rrule.Parse, e.g. FREQ=WEEKLY;BYDAY=MO"`
+	StartDate  time.Time  `gorm:"type:date;column:start_date;not null" json:"startDate"`
+	NextRunAt  *time.Time `gorm:"type:date;column:next_run_at;index" json:"nextRunAt,omitempty" comment:"the next occurrence, empty once the rule ended"`
+	LastRunAt  *time.Time `gorm:"type:date;column:last_run_at" json:"lastRunAt,omitempty"`
+	Active     bool       `gorm:"not null;default:true" json:"active"`
+	CreatorID  string     `gorm:"type:uuid;not null" json:"creatorId" comment:"the issues created on behalf of"`
+	CreatedAt  time.Time  `gorm:"column:created_at;default:now();<-:create" json:"createdAt"`
+	UpdatedAt  time.Time  `gorm:"column:updated_at;autoUpdateTime" json:"updatedAt"`
+
+	Project  Project        `gorm:"foreignKey:ProjectID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"project,omitzero"`
+	Template *IssueTemplate `gorm:"foreignKey:TemplateID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"template,omitempty"`
+	Creator  *User          `gorm:"foreignKey:CreatorID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"creator,omitempty"`
+}
+
+func (IssueRecurrence) TableName() string {
+	return "issue_recurrences"
+}
+
+// RecurrenceRun keep track the occurrences already generated, one per recurrence a day
+// so the job restarting never create the issue twice
+type RecurrenceRun struct {
+	ID           string    `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()" json:"id"`
+	RecurrenceID string    `gorm:"type:uuid;not null;uniqueIndex:idx_recurrence_run" json:"recurrenceId"`
+	Occurrence   time.Time `gorm:"type:date;not null;uniqueIndex:idx_recurrence_run" json:"occurrence"`
+	IssueID      *string   `gorm:"type:uuid;column:issue_id" json:"issueId,omitempty"`
+	CreatedAt    time.Time `gorm:"column:created_at;default:now();<-:create" json:"createdAt"`
+
+	Recurrence IssueRecurrence `gorm:"foreignKey:RecurrenceID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+	Issue      *Issue          `gorm:"foreignKey:IssueID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL" json:"-"`
+}
+
+func (RecurrenceRun) TableName() string {
+	return "recurrence_runs"
+}
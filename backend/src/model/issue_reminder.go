@@ -0,0 +1,23 @@
+package model
+
+import (
+	"time"
+	"webservices/src/types"
+)
+
+// IssueReminder keep track the due date alerts already sent, so a user is alerted once per issue per threshold
+type IssueReminder struct {
+	ID        string                  `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()" json:"id"`
+	IssueID   string                  `gorm:"type:uuid;not null;uniqueIndex:idx_issue_reminder" json:"issueId"`
+	UserID    string                  `gorm:"type:uuid;not null;uniqueIndex:idx_issue_reminder" json:"userId"`
+	Threshold types.ReminderThreshold `gorm:"not null;uniqueIndex:idx_issue_reminder" json:"threshold"`
+	DueDate   time.Time               `gorm:"column:due_date;not null;uniqueIndex:idx_issue_reminder" json:"dueDate"` // re-alert when the due date moved
+	CreatedAt time.Time               `gorm:"column:created_at;default:now();<-:create" json:"createdAt"`
+
+	Issue Issue `gorm:"foreignKey:IssueID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+	User  User  `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+}
+
+func (IssueReminder) TableName() string {
+	return "issue_reminders"
+}
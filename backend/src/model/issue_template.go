@@ -0,0 +1,46 @@
+package model
+
+import (
+	"strconv"
+	"strings"
+	"time"
+	"webservices/src/types"
+
+	"gorm.io/datatypes"
+)
+
+// IssueTemplate a preset applied to create an issue, the `Checklist` items created as its subtasks
+type IssueTemplate struct {
+	ID           string                      `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()" json:"id"`
+	ProjectID    string                      `gorm:"type:uuid;not null;uniqueIndex:idx_issue_template_name" json:"projectId"`
+	Name         string                      `gorm:"type:varchar(100);not null;uniqueIndex:idx_issue_template_name" json:"name"`
+	TitlePattern string                      `gorm:"column:title_pattern;not null" json:"titlePattern" comment:"placeholders {{date}}, {{week}}, {{month}} & {{year}} filled on apply"`
+	Description  *string                     `json:"description,omitempty"`
+	Type         types.IssueType             `gorm:"type:issue_type;default:'task'" json:"type"`
+	Priority     *types.IssuePriority        `gorm:"type:issue_priority" json:"priority,omitempty" comment:"empty using the project default"`
+	AssigneeID   *string                     `gorm:"type:uuid;column:assignee_id" json:"assigneeId,omitempty" comment:"empty using the project assignment"`
+	Checklist    datatypes.JSONSlice[string] `gorm:"type:jsonb;not null;default:'[]'" json:"checklist"`
+	CustomFields datatypes.JSONMap           `gorm:"type:jsonb;column:custom_fields;not null;default:'{}'" json:"customFields"`
+	CreatorID    string                      `gorm:"type:uuid;not null" json:"creatorId"`
+	CreatedAt    time.Time                   `gorm:"column:created_at;default:now();<-:create" json:"createdAt"`
+	UpdatedAt    time.Time                   `gorm:"column:updated_at;autoUpdateTime" json:"updatedAt"`
+
+	Project  Project `gorm:"foreignKey:ProjectID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"project,omitzero"`
+	Assignee *User   `gorm:"foreignKey:AssigneeID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL" json:"assignee,omitempty"`
+	Creator  *User   `gorm:"foreignKey:CreatorID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"creator,omitempty"`
+}
+
+func (IssueTemplate) TableName() string {
+	return "issue_templates"
+}
+
+// RenderTitle fill the title placeholders with the `at` date
+func (t *IssueTemplate) RenderTitle(at time.Time) string {
+	_, week := at.ISOWeek()
+	return strings.NewReplacer(
+		"{{date}}", at.Format("2006-01-02"),
+		"{{week}}", strconv.Itoa(week),
+		"{{month}}", at.Format("January"),
+		"{{year}}", strconv.Itoa(at.Year()),
+	).Replace(t.TitlePattern)
+}
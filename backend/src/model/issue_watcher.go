@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// IssueWatcher the user subscribed to the issue notifications. the row kept with `Watching` off once
+// the user unwatched, so the auto subscription (reporter, assignee, commenter) never re-add them
+type IssueWatcher struct {
+	ID        string    `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()" json:"id"`
+	IssueID   string    `gorm:"type:uuid;not null;uniqueIndex:idx_issue_watcher" json:"issueId"`
+	UserID    string    `gorm:"type:uuid;not null;uniqueIndex:idx_issue_watcher;index" json:"userId"`
+	Watching  bool      `gorm:"not null;default:true" json:"watching"`
+	CreatedAt time.Time `gorm:"column:created_at;default:now();<-:create" json:"createdAt"`
+	UpdatedAt time.Time `gorm:"column:updated_at;autoUpdateTime" json:"updatedAt"`
+
+	Issue Issue `gorm:"foreignKey:IssueID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+	User  User  `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"user,omitzero"`
+}
+
+func (IssueWatcher) TableName() string {
+	return "issue_watchers"
+}
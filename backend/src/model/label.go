@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+type Label struct {
+	ID        string    `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()" json:"id"`
+	ProjectID string    `gorm:"type:uuid;not null;uniqueIndex:idx_label_name" json:"projectId"`
+	Name      string    `gorm:"not null;uniqueIndex:idx_label_name" json:"name"`
+	Color     string    `gorm:"not null;default:'#6b7280'" json:"color"`
+	CreatedAt time.Time `gorm:"column:created_at;default:now();<-:create" json:"createdAt"`
+	UpdatedAt time.Time `gorm:"column:updated_at;autoUpdateTime" json:"updatedAt"`
+
+	Project Project `gorm:"foreignKey:ProjectID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"project,omitzero"`
+	Issues  []Issue `gorm:"many2many:issue_labels;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"issues,omitempty"`
+}
+
+func (Label) TableName() string {
+	return "labels"
+}
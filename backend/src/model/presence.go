@@ -0,0 +1,47 @@
+package model
+
+import (
+	"time"
+	"webservices/src/types"
+)
+
+// PresenceSession a connected socket of the user, kept alive by its events & expired once stale
+type PresenceSession struct {
+	ID        string               `gorm:"primaryKey;type:varchar(64)" json:"id" comment:"the socket id"`
+	UserID    string               `gorm:"type:uuid;not null;index" json:"userId"`
+	ProjectID *string              `gorm:"type:uuid;index" json:"projectId,omitempty" comment:"the project of the open issue"`
+	IssueID   *string              `gorm:"type:uuid;index" json:"issueId,omitempty" comment:"the issue open"`
+	Editing   bool                 `gorm:"not null;default:false" json:"editing" comment:"editing the issue description"`
+	Status    types.PresenceStatus `gorm:"type:presence_status;not null;default:'online'" json:"status"`
+	SeenAt    time.Time            `gorm:"not null;default:now();index" json:"seenAt" comment:"the last event of the socket"`
+	CreatedAt time.Time            `gorm:"column:created_at;default:now();<-:create" json:"createdAt"`
+
+	User    User     `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+	Project *Project `gorm:"foreignKey:ProjectID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL" json:"-"`
+	Issue   *Issue   `gorm:"foreignKey:IssueID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL" json:"-"`
+}
+
+func (PresenceSession) TableName() string {
+	return "presence_sessions"
+}
+
+// MemberPresence the member status over all its sessions
+type MemberPresence struct {
+	UserID string               `json:"userId"`
+	Status types.PresenceStatus `json:"status"`
+	SeenAt *time.Time           `json:"seenAt,omitempty" comment:"the last session event, or the last request once offline"`
+}
+
+// IssuePresence the members having the issue open
+type IssuePresence struct {
+	IssueID string   `json:"issueId"`
+	Viewers []string `json:"viewers"`
+	Editors []string `json:"editors" comment:"editing the description, listed in the viewers too"`
+}
+
+// ProjectPresence pushed to the project room as `presence:update` on every change
+type ProjectPresence struct {
+	ProjectID string           `json:"projectId"`
+	Members   []MemberPresence `json:"members"`
+	Issues    []IssuePresence  `json:"issues"`
+}
@@ -24,6 +24,7 @@ type Project struct {
 	ActiveUsers []User           `gorm:"foreignKey:ProjectID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"activeUsers,omitempty"`
 	Users       []User           `gorm:"many2many:user_projects;" json:"users,omitempty"`
 	Issues      []Issue          `gorm:"constraint:OnDelete:CASCADE;" json:"issues,omitempty"`
+	Sprints     []Sprint         `gorm:"constraint:OnDelete:CASCADE;" json:"sprints,omitempty"`
 	Activities  []RecentActivity `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"activities,omitempty"`
 }
 
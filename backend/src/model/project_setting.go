@@ -11,7 +11,7 @@ type ProjectSetting struct {
 	AutoAssignment         bool                   `gorm:"column:auto_assignment;default:false" json:"autoAssignment"`
 	AssignmentMethod       types.AssignmentMethod `gorm:"type:assignment_method;column:assignment_method;default:'round_robin'" json:"assignmentMethod"`
 	DefaultIssuePriority   types.IssuePriority    `gorm:"type:issue_priority;column:default_issue_priority;default:'medium'" json:"defaultIssuePriority"`
-	DefaultIssueStatus     types.IssueStatus      `gorm:"type:issue_status;column:default_issue_status;default:'todo'" json:"defaultIssueStatus"`
+	DefaultIssueStatus     types.IssueStatus      `gorm:"type:varchar(40);column:default_issue_status;default:'todo'" json:"defaultIssueStatus"`
 	EnableTimeTracking     bool                   `gorm:"column:enable_time_tracking;default:false" json:"enableTimeTracking"`
 	TimeTrackingUnit       *types.TimeUnit        `gorm:"type:time_unit;column:time_tracking_unit;default:'hours'" json:"timeTrackingUnit,omitempty"`
 	RequireDueDate         bool                   `gorm:"column:require_due_date;default:false" json:"requireDueDate"`
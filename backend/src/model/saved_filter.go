@@ -0,0 +1,27 @@
+package model
+
+import (
+	"time"
+	"webservices/src/types"
+)
+
+// SavedFilter a board view, `Query` & `Sort` are the jql query and its ORDER BY part
+type SavedFilter struct {
+	ID         string                 `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()" json:"id"`
+	ProjectID  string                 `gorm:"type:uuid;index;not null" json:"projectId"`
+	OwnerID    string                 `gorm:"type:uuid;index;not null" json:"ownerId"`
+	Name       string                 `gorm:"not null" json:"name"`
+	Query      string                 `gorm:"not null;default:''" json:"query"`
+	Sort       string                 `gorm:"not null;default:''" json:"sort"`
+	GroupBy    types.BoardGrouping    `gorm:"type:board_grouping;default:'status'" json:"groupBy"`
+	Visibility types.FilterVisibility `gorm:"type:filter_visibility;default:'private'" json:"visibility"`
+	CreatedAt  time.Time              `gorm:"column:created_at;default:now();<-:create" json:"createdAt"`
+	UpdatedAt  time.Time              `gorm:"column:updated_at;autoUpdateTime" json:"updatedAt"`
+
+	Project Project `gorm:"foreignKey:ProjectID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"project,omitzero"`
+	Owner   *User   `gorm:"foreignKey:OwnerID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"owner,omitempty"`
+}
+
+func (SavedFilter) TableName() string {
+	return "saved_filters"
+}
@@ -0,0 +1,26 @@
+package model
+
+import (
+	"time"
+	"webservices/src/types"
+)
+
+type Sprint struct {
+	ID        string            `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()" json:"id"`
+	ProjectID string            `gorm:"type:uuid;index;not null" json:"projectId"`
+	Name      string            `gorm:"not null" json:"name"`
+	Goal      *string           `json:"goal,omitempty"`
+	State     types.SprintState `gorm:"type:sprint_state;default:'planned'" json:"state"`
+	StartDate *time.Time        `gorm:"column:start_date" json:"startDate,omitempty"`
+	EndDate   *time.Time        `gorm:"column:end_date" json:"endDate,omitempty"`
+	ClosedAt  *time.Time        `gorm:"column:closed_at" json:"closedAt,omitempty"`
+	CreatedAt time.Time         `gorm:"column:created_at;default:now();<-:create" json:"createdAt"`
+	UpdatedAt time.Time         `gorm:"column:updated_at;autoUpdateTime" json:"updatedAt"`
+
+	Project Project `gorm:"foreignKey:ProjectID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"project,omitzero"`
+	Issues  []Issue `gorm:"foreignKey:SprintID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"issues,omitempty"`
+}
+
+func (Sprint) TableName() string {
+	return "sprints"
+}
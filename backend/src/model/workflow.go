@@ -0,0 +1,128 @@
+package model
+
+import (
+	"time"
+	"webservices/src/pkg/common"
+	"webservices/src/types"
+)
+
+// WorkflowStatus a status (board column) of the project workflow, ordered by `Position`
+type WorkflowStatus struct {
+	ID        string               `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()" json:"id"`
+	ProjectID string               `gorm:"type:uuid;not null;uniqueIndex:idx_workflow_status" json:"projectId"`
+	Key       types.IssueStatus    `gorm:"type:varchar(40);not null;uniqueIndex:idx_workflow_status" json:"key"`
+	Name      string               `gorm:"not null" json:"name"`
+	Category  types.StatusCategory `gorm:"type:status_category;not null;default:'todo'" json:"category"`
+	Position  int                  `gorm:"not null;default:0" json:"position"`
+	CreatedAt time.Time            `gorm:"column:created_at;default:now();<-:create" json:"createdAt"`
+	UpdatedAt time.Time            `gorm:"column:updated_at;autoUpdateTime" json:"updatedAt"`
+
+	Project Project `gorm:"foreignKey:ProjectID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"project,omitzero"`
+}
+
+func (WorkflowStatus) TableName() string {
+	return "workflow_statuses"
+}
+
+// WorkflowTransition an allowed status change, `FromStatus` nil allowing it from any status
+type WorkflowTransition struct {
+	ID         string             `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()" json:"id"`
+	ProjectID  string             `gorm:"type:uuid;not null;index" json:"projectId"`
+	FromStatus *types.IssueStatus `gorm:"type:varchar(40);column:from_status" json:"from"`
+	ToStatus   types.IssueStatus  `gorm:"type:varchar(40);column:to_status;not null" json:"to"`
+	CreatedAt  time.Time          `gorm:"column:created_at;default:now();<-:create" json:"createdAt"`
+
+	Project Project `gorm:"foreignKey:ProjectID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"project,omitzero"`
+}
+
+func (WorkflowTransition) TableName() string {
+	return "workflow_transitions"
+}
+
+// Workflow the statuses & transitions of a project, the project without any status use the default one.
+// a workflow without transition allow every change
+type Workflow struct {
+	ProjectID   string               `json:"projectId"`
+	Custom      bool                 `json:"custom"`
+	Statuses    []WorkflowStatus     `json:"statuses"`
+	Transitions []WorkflowTransition `json:"transitions"`
+}
+
+// DefaultWorkflow the built-in statuses, `in_review` only along the approval workflow
+func DefaultWorkflow(projectID string, approval bool) *Workflow {
+	workflow := &Workflow{
+		ProjectID:   projectID,
+		Statuses:    make([]WorkflowStatus, 0, len(types.IssueStatuses)),
+		Transitions: make([]WorkflowTransition, 0),
+	}
+
+	for _, key := range types.IssueStatuses {
+		if key == types.IssueStatusInReview && !approval {
+			continue
+		}
+
+		workflow.Statuses = append(workflow.Statuses, WorkflowStatus{
+			ProjectID: projectID,
+			Key:       key,
+			Name:      common.ToUpper(key.ToString()),
+			Category:  key.DefaultCategory(),
+			Position:  len(workflow.Statuses),
+		})
+	}
+
+	return workflow
+}
+
+func (w *Workflow) Status(key types.IssueStatus) *WorkflowStatus {
+	for i := range w.Statuses {
+		if w.Statuses[i].Key == key {
+			return &w.Statuses[i]
+		}
+	}
+	return nil
+}
+
+// Category of the status, the one no longer on the workflow falling back to its default category
+func (w *Workflow) Category(key types.IssueStatus) types.StatusCategory {
+	if status := w.Status(key); status != nil {
+		return status.Category
+	}
+	return key.DefaultCategory()
+}
+
+func (w *Workflow) IsDone(key types.IssueStatus) bool {
+	return w.Category(key) == types.CategoryDone
+}
+
+// First the first status of the category following the workflow order
+func (w *Workflow) First(category types.StatusCategory) *WorkflowStatus {
+	for i := range w.Statuses {
+		if w.Statuses[i].Category == category {
+			return &w.Statuses[i]
+		}
+	}
+	return nil
+}
+
+func (w *Workflow) CanTransition(from, to types.IssueStatus) bool {
+	if from == to || len(w.Transitions) == 0 {
+		return true
+	}
+
+	for _, transition := range w.Transitions {
+		if transition.ToStatus == to &&
+			(transition.FromStatus == nil || *transition.FromStatus == from) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (w *Workflow) Keys() []string {
+	keys := make([]string, len(w.Statuses))
+	for i, status := range w.Statuses {
+		keys[i] = string(status.Key)
+	}
+	return keys
+}
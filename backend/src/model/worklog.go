@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+type Worklog struct {
+	ID        string    `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()" json:"id"`
+	IssueID   string    `gorm:"type:uuid;index;not null" json:"issueId"`
+	UserID    string    `gorm:"type:uuid;index;not null" json:"userId"`
+	Minutes   int       `gorm:"not null" json:"minutes"`
+	Date      time.Time `gorm:"type:date;not null" json:"date"`
+	Note      *string   `json:"note,omitempty"`
+	CreatedAt time.Time `gorm:"column:created_at;default:now();<-:create" json:"createdAt"`
+	UpdatedAt time.Time `gorm:"column:updated_at;autoUpdateTime" json:"updatedAt"`
+	Duration  float64   `gorm:"-:all" json:"duration" comment:"minutes converted to project time_tracking_unit"`
+
+	User  User  `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"user,omitzero"`
+	Issue Issue `gorm:"foreignKey:IssueID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"issue,omitzero"`
+}
+
+func (Worklog) TableName() string {
+	return "worklogs"
+}
@@ -31,6 +31,16 @@ func Ptr[T any](s T) *T {
 	return &s
 }
 
+// Coalesce the first non-nil pointer
+func Coalesce[T any](values ...*T) *T {
+	for _, value := range values {
+		if value != nil {
+			return value
+		}
+	}
+	return nil
+}
+
 func BindMap[T any](data any) (T, error) {
 	var output T
 
@@ -0,0 +1,476 @@
+package jql
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"webservices/src/types"
+
+	"gorm.io/gorm"
+)
+
+type fieldKind int
+
+const (
+	fieldEnum fieldKind = iota
+	fieldUser
+	fieldLabel
+	fieldDate
+	fieldParent
+	fieldText
+)
+
+type field struct {
+	kind   fieldKind
+	column string
+	values []string // allowed values of the enum fields, nil for the status validated against `Context.Statuses`
+}
+
+var fields = map[string]field{
+	"status":   {kind: fieldEnum, column: "issues.status"},
+	"category": {kind: fieldEnum, column: "issues.status_category", values: enumValues(types.StatusCategories)},
+	"priority": {kind: fieldEnum, column: "issues.priority", values: enumValues(types.IssuePriorities)},
+	"type":     {kind: fieldEnum, column: "issues.type", values: enumValues(types.IssueTypes)},
+	"assignee": {kind: fieldUser, column: "issues.assignee_id"},
+	"reporter": {kind: fieldUser, column: "issues.reporter_id"},
+	"label":    {kind: fieldLabel},
+	"created":  {kind: fieldDate, column: "issues.created_at"},
+	"updated":  {kind: fieldDate, column: "issues.updated_at"},
+	"start":    {kind: fieldDate, column: "issues.start_date"},
+	"due":      {kind: fieldDate, column: "issues.due_date"},
+	"done":     {kind: fieldDate, column: "issues.done_date"},
+	"parent":   {kind: fieldParent, column: "issues.parents"},
+	"text":     {kind: fieldText},
+	"title":    {kind: fieldText, column: "issues.title"},
+}
+
+var operators = map[fieldKind][]string{
+	fieldEnum:   {"=", "!=", "IN", "NOT IN"},
+	fieldUser:   {"=", "!=", "IN", "NOT IN", "IS EMPTY", "IS NOT EMPTY"},
+	fieldLabel:  {"=", "!=", "IN", "NOT IN", "IS EMPTY", "IS NOT EMPTY"},
+	fieldDate:   {"=", "!=", ">", ">=", "<", "<=", "IS EMPTY", "IS NOT EMPTY"},
+	fieldParent: {"=", "!=", "IN", "NOT IN", "IS EMPTY", "IS NOT EMPTY"},
+	fieldText:   {"~", "!~"},
+}
+
+// nullable columns are ordered with the empty ones last
+var orderColumns = map[string]string{
+	"created":  "issues.created_at",
+	"updated":  "issues.updated_at",
+	"start":    "issues.start_date",
+	"due":      "issues.due_date",
+	"done":     "issues.done_date",
+	"priority": "issues.priority",
+	"status":   "issues.status",
+	"category": "issues.status_category",
+	"type":     "issues.type",
+	"title":    "issues.title",
+	"rank":     "issues.order_index",
+}
+
+var (
+	uuidPattern     = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	relativePattern = regexp.MustCompile(`^([+-]?)(\d+)([mhdw])$`)
+)
+
+// Context is the request dependent values, `currentUser()` and the relative dates resolved with it.
+// `Statuses` the project workflow status keys, `Fields` the project custom fields
+type Context struct {
+	UserID   string
+	Now      time.Time
+	Statuses []string
+	Fields   []CustomField
+}
+
+// Compiled is the parameterized SQL of the query, every user input is passed through `Args`
+type Compiled struct {
+	Where string
+	Args  []any
+	Order []string
+}
+
+// Apply add the conditions & ordering into the gorm query
+func (c *Compiled) Apply(db *gorm.DB) *gorm.DB {
+	if c.Where != "" {
+		db = db.Where(c.Where, c.Args...)
+	}
+
+	for _, order := range c.Order {
+		db = db.Order(order)
+	}
+
+	return db
+}
+
+func Compile(query *Query, ctx Context) (*Compiled, error) {
+	if ctx.Now.IsZero() {
+		ctx.Now = time.Now()
+	}
+
+	c := &compiler{ctx: ctx}
+	compiled := &Compiled{}
+
+	if query.Where != nil {
+		where, err := c.node(query.Where)
+		if err != nil {
+			return nil, err
+		}
+		compiled.Where = where
+		compiled.Args = c.args
+	}
+
+	for _, order := range query.OrderBy {
+		column := orderColumns[order.Field]
+		if order.Desc {
+			compiled.Order = append(compiled.Order, column+" DESC NULLS LAST")
+		} else {
+			compiled.Order = append(compiled.Order, column+" ASC NULLS LAST")
+		}
+	}
+
+	return compiled, nil
+}
+
+type compiler struct {
+	ctx  Context
+	args []any
+}
+
+func (c *compiler) node(node Node) (string, error) {
+	switch n := node.(type) {
+	case *Binary:
+		left, err := c.node(n.Left)
+		if err != nil {
+			return "", err
+		}
+
+		right, err := c.node(n.Right)
+		if err != nil {
+			return "", err
+		}
+
+		return "(" + left + " " + n.Op + " " + right + ")", nil
+
+	case *Not:
+		expr, err := c.node(n.Expr)
+		if err != nil {
+			return "", err
+		}
+		return "NOT " + expr, nil
+
+	case *Text:
+		return c.text("", "~", n.Value), nil
+
+	case *Clause:
+		return c.clause(n)
+	}
+
+	return "", fmt.Errorf("unsupported node %T", node)
+}
+
+func (c *compiler) clause(clause *Clause) (string, error) {
+	if strings.HasPrefix(clause.Field, customPrefix) {
+		return c.custom(clause)
+	}
+
+	f := fields[clause.Field]
+
+	allowed := false
+	for _, op := range operators[f.kind] {
+		allowed = allowed || op == clause.Op
+	}
+
+	if !allowed {
+		return "", errorAt(clause.opTok, "operator %s is not supported by %s, use one of: %s",
+			clause.Op, clause.Field, strings.Join(operators[f.kind], ", "))
+	}
+
+	switch f.kind {
+	case fieldEnum:
+		return c.enum(f, clause)
+	case fieldUser:
+		return c.user(f, clause)
+	case fieldLabel:
+		return c.label(clause)
+	case fieldDate:
+		return c.date(f, clause)
+	case fieldParent:
+		return c.parent(f, clause)
+	default:
+		if clause.Values[0].Kind == valueFunc {
+			return "", errorAt(clause.Values[0].token, "expected a text, got function %s()", clause.Values[0].Raw)
+		}
+		return c.text(f.column, clause.Op, clause.Values[0].Raw), nil
+	}
+}
+
+func (c *compiler) enum(f field, clause *Clause) (string, error) {
+	allowedValues := f.values
+	if allowedValues == nil {
+		allowedValues = c.ctx.Statuses
+	}
+
+	values := make([]string, 0, len(clause.Values))
+	for _, v := range clause.Values {
+		value := strings.ReplaceAll(strings.ToLower(strings.TrimSpace(v.Raw)), " ", "_")
+
+		valid := false
+		for _, allowed := range allowedValues {
+			valid = valid || allowed == value
+		}
+
+		if !valid || v.Kind == valueFunc {
+			return "", errorAt(v.token, "invalid %s %q, expected one of: %s",
+				clause.Field, v.Raw, strings.Join(allowedValues, ", "))
+		}
+		values = append(values, value)
+	}
+
+	return c.in(f.column, clause.Op, values), nil
+}
+
+// user matched by id, `currentUser()`, email or name
+func (c *compiler) user(f field, clause *Clause) (string, error) {
+	if sql, ok := c.empty(f.column, clause.Op); ok {
+		return sql, nil
+	}
+
+	ids := make([]string, 0)
+	names := make([]string, 0)
+
+	for _, v := range clause.Values {
+		switch {
+		case v.Kind == valueFunc && strings.EqualFold(v.Raw, "currentUser"):
+			ids = append(ids, c.ctx.UserID)
+		case v.Kind == valueFunc:
+			return "", errorAt(v.token, "unknown function %s(), expected currentUser()", v.Raw)
+		case uuidPattern.MatchString(v.Raw):
+			ids = append(ids, v.Raw)
+		default:
+			names = append(names, strings.ToLower(v.Raw))
+		}
+	}
+
+	conditions := make([]string, 0, 2)
+	if len(ids) > 0 {
+		conditions = append(conditions, f.column+" IN ?")
+		c.args = append(c.args, ids)
+	}
+
+	if len(names) > 0 {
+		conditions = append(conditions, f.column+" IN (SELECT id FROM users WHERE LOWER(email) IN ? OR LOWER(name) IN ?)")
+		c.args = append(c.args, names, names)
+	}
+
+	return c.negate(clause.Op, "("+strings.Join(conditions, " OR ")+")"), nil
+}
+
+// label matched by id or the name (case-insensitive)
+func (c *compiler) label(clause *Clause) (string, error) {
+	switch clause.Op {
+	case "IS EMPTY":
+		return "issues.id NOT IN (SELECT issue_id FROM issue_labels)", nil
+	case "IS NOT EMPTY":
+		return "issues.id IN (SELECT issue_id FROM issue_labels)", nil
+	}
+
+	ids := make([]string, 0)
+	names := make([]string, 0)
+
+	for _, v := range clause.Values {
+		switch {
+		case v.Kind == valueFunc:
+			return "", errorAt(v.token, "expected a label, got function %s()", v.Raw)
+		case uuidPattern.MatchString(v.Raw):
+			ids = append(ids, v.Raw)
+		default:
+			names = append(names, strings.ToLower(v.Raw))
+		}
+	}
+
+	conditions := make([]string, 0, 2)
+	if len(ids) > 0 {
+		conditions = append(conditions, "labels.id IN ?")
+		c.args = append(c.args, ids)
+	}
+
+	if len(names) > 0 {
+		conditions = append(conditions, "LOWER(labels.name) IN ?")
+		c.args = append(c.args, names)
+	}
+
+	sql := "issues.id IN (SELECT issue_labels.issue_id FROM issue_labels " +
+		"JOIN labels ON labels.id = issue_labels.label_id WHERE " + strings.Join(conditions, " OR ") + ")"
+
+	return c.negate(clause.Op, sql), nil
+}
+
+func (c *compiler) date(f field, clause *Clause) (string, error) {
+	if sql, ok := c.empty(f.column, clause.Op); ok {
+		return sql, nil
+	}
+
+	value, wholeDay, err := c.resolveDate(clause.Values[0])
+	if err != nil {
+		return "", err
+	}
+
+	// a plain date covering the whole day
+	if wholeDay {
+		nextDay := value.AddDate(0, 0, 1)
+		switch clause.Op {
+		case "=":
+			c.args = append(c.args, value, nextDay)
+			return "(" + f.column + " >= ? AND " + f.column + " < ?)", nil
+		case "!=":
+			c.args = append(c.args, value, nextDay)
+			return "(" + f.column + " < ? OR " + f.column + " >= ?)", nil
+		case ">":
+			c.args = append(c.args, nextDay)
+			return f.column + " >= ?", nil
+		case "<=":
+			c.args = append(c.args, nextDay)
+			return f.column + " < ?", nil
+		}
+	}
+
+	c.args = append(c.args, value)
+	return f.column + " " + clause.Op + " ?", nil
+}
+
+// resolveDate parse the absolute date (2006-01-02, RFC3339), relative (-7d, 2w, -3h, 30m)
+// or the date functions, `wholeDay` true for the plain date
+func (c *compiler) resolveDate(v Value) (time.Time, bool, error) {
+	now := c.ctx.Now
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	if v.Kind == valueFunc {
+		switch strings.ToLower(v.Raw) {
+		case "now":
+			return now, false, nil
+		case "startofday":
+			return today, false, nil
+		case "startofweek":
+			weekday := (int(today.Weekday()) + 6) % 7 // monday first
+			return today.AddDate(0, 0, -weekday), false, nil
+		case "startofmonth":
+			return today.AddDate(0, 0, 1-today.Day()), false, nil
+		}
+		return time.Time{}, false, errorAt(v.token,
+			"unknown function %s(), expected now(), startOfDay(), startOfWeek() or startOfMonth()", v.Raw)
+	}
+
+	if match := relativePattern.FindStringSubmatch(v.Raw); match != nil {
+		amount, err := strconv.Atoi(match[2])
+		if err != nil {
+			return time.Time{}, false, errorAt(v.token, "invalid relative date %q", v.Raw)
+		}
+
+		if match[1] == "-" {
+			amount = -amount
+		}
+
+		switch match[3] {
+		case "m":
+			return now.Add(time.Duration(amount) * time.Minute), false, nil
+		case "h":
+			return now.Add(time.Duration(amount) * time.Hour), false, nil
+		case "d":
+			return now.AddDate(0, 0, amount), false, nil
+		default:
+			return now.AddDate(0, 0, amount*7), false, nil
+		}
+	}
+
+	if date, err := time.ParseInLocation("2006-01-02", v.Raw, now.Location()); err == nil {
+		return date, true, nil
+	}
+
+	if date, err := time.Parse(time.RFC3339, v.Raw); err == nil {
+		return date, false, nil
+	}
+
+	return time.Time{}, false, errorAt(v.token,
+		"invalid date %q, expected YYYY-MM-DD, a relative date like -7d or a date function", v.Raw)
+}
+
+func (c *compiler) parent(f field, clause *Clause) (string, error) {
+	switch clause.Op {
+	case "IS EMPTY":
+		return "(" + f.column + " IS NULL OR " + f.column + " = '')", nil
+	case "IS NOT EMPTY":
+		return "(" + f.column + " IS NOT NULL AND " + f.column + " != '')", nil
+	}
+
+	values := make([]string, 0, len(clause.Values))
+	for _, v := range clause.Values {
+		if !uuidPattern.MatchString(v.Raw) {
+			return "", errorAt(v.token, "invalid parent %q, expected an issue id", v.Raw)
+		}
+		values = append(values, v.Raw)
+	}
+
+	return c.in(f.column, clause.Op, values), nil
+}
+
+// text search with ILIKE, empty column search on both the title and description
+func (c *compiler) text(column, op, value string) string {
+	pattern := "%" + strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value) + "%"
+
+	sql := column + " ILIKE ?"
+	c.args = append(c.args, pattern)
+
+	if column == "" {
+		sql = "(issues.title ILIKE ? OR COALESCE(issues.description, '') ILIKE ?)"
+		c.args = append(c.args, pattern)
+	}
+
+	if op == "!~" {
+		return "NOT " + sql
+	}
+	return sql
+}
+
+func (c *compiler) in(column, op string, values []string) string {
+	switch op {
+	case "=":
+		c.args = append(c.args, values[0])
+		return column + " = ?"
+	case "!=":
+		c.args = append(c.args, values[0])
+		return column + " != ?"
+	case "NOT IN":
+		c.args = append(c.args, values)
+		return column + " NOT IN ?"
+	default:
+		c.args = append(c.args, values)
+		return column + " IN ?"
+	}
+}
+
+func (c *compiler) empty(column, op string) (string, bool) {
+	switch op {
+	case "IS EMPTY":
+		return column + " IS NULL", true
+	case "IS NOT EMPTY":
+		return column + " IS NOT NULL", true
+	}
+	return "", false
+}
+
+func (c *compiler) negate(op, sql string) string {
+	if op == "!=" || op == "NOT IN" {
+		return "NOT " + sql
+	}
+	return sql
+}
+
+func enumValues[T ~string](values []T) []string {
+	result := make([]string, len(values))
+	for i, v := range values {
+		result[i] = string(v)
+	}
+	return result
+}
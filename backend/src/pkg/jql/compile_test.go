@@ -0,0 +1,162 @@
+package jql
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+	"webservices/src/types"
+)
+
+func TestCompile(t *testing.T) {
+	now := time.Date(2026, 10, 17, 15, 30, 0, 0, time.UTC)
+	ctx := Context{
+		UserID:   "u1",
+		Now:      now,
+		Statuses: []string{"todo", "on_progress", "done"},
+		Fields: []CustomField{
+			{Key: "points", Type: types.FieldNumber},
+			{Key: "severity", Type: types.FieldSelect, Options: []string{"low", "high"}},
+		},
+	}
+
+	tests := []struct {
+		input string
+		where string
+		args  []any
+		order []string
+	}{
+		{input: "priority = HIGH", where: "issues.priority = ?", args: []any{"high"}},
+		{
+			input: `status NOT IN (todo, "On Progress")`,
+			where: "issues.status NOT IN ?",
+			args:  []any{[]string{"todo", "on_progress"}},
+		},
+		{input: "assignee = currentUser()", where: "(issues.assignee_id IN ?)", args: []any{[]string{"u1"}}},
+		{
+			input: "reporter != Alice",
+			where: "NOT (issues.reporter_id IN (SELECT id FROM users WHERE LOWER(email) IN ? OR LOWER(name) IN ?))",
+			args:  []any{[]string{"alice"}, []string{"alice"}},
+		},
+		{input: "assignee IS EMPTY", where: "issues.assignee_id IS NULL"},
+		{input: "label IS NOT EMPTY", where: "issues.id IN (SELECT issue_id FROM issue_labels)"},
+		{input: "due < -7d", where: "issues.due_date < ?", args: []any{now.AddDate(0, 0, -7)}},
+		{input: "updated >= -3h", where: "issues.updated_at >= ?", args: []any{now.Add(-3 * time.Hour)}},
+		{
+			input: "created = 2026-01-02",
+			where: "(issues.created_at >= ? AND issues.created_at < ?)",
+			args: []any{
+				time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC),
+				time.Date(2026, 1, 3, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			input: "done > startOfWeek()",
+			where: "issues.done_date > ?",
+			args:  []any{time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC)},
+		},
+		{
+			input: "login",
+			where: "(issues.title ILIKE ? OR COALESCE(issues.description, '') ILIKE ?)",
+			args:  []any{"%login%", "%login%"},
+		},
+		{input: `title !~ "50%"`, where: "NOT issues.title ILIKE ?", args: []any{`%50\%%`}},
+		{
+			input: "type = bug OR NOT priority = low",
+			where: "(issues.type = ? OR NOT issues.priority = ?)",
+			args:  []any{"bug", "low"},
+		},
+		{
+			input: "cf.points >= 3",
+			where: "(issues.custom_fields->>?::text)::numeric >= ?",
+			args:  []any{"points", 3.0},
+		},
+		{
+			input: "cf.severity IN (HIGH)",
+			where: "issues.custom_fields->>?::text IN ?",
+			args:  []any{"severity", []string{"high"}},
+		},
+		{
+			input: "ORDER BY due DESC, title",
+			order: []string{"issues.due_date DESC NULLS LAST", "issues.title ASC NULLS LAST"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			query, err := Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse(%q) error: %v", tt.input, err)
+			}
+
+			compiled, err := Compile(query, ctx)
+			if err != nil {
+				t.Fatalf("Compile(%q) error: %v", tt.input, err)
+			}
+
+			if compiled.Where != tt.where {
+				t.Errorf("Compile(%q) where\n got %s\nwant %s", tt.input, compiled.Where, tt.where)
+			}
+
+			if len(compiled.Args) != 0 || len(tt.args) != 0 {
+				if !reflect.DeepEqual(compiled.Args, tt.args) {
+					t.Errorf("Compile(%q) args = %#v, want %#v", tt.input, compiled.Args, tt.args)
+				}
+			}
+
+			if !reflect.DeepEqual(compiled.Order, tt.order) {
+				t.Errorf("Compile(%q) order = %v, want %v", tt.input, compiled.Order, tt.order)
+			}
+		})
+	}
+}
+
+func TestCompileError(t *testing.T) {
+	ctx := Context{
+		UserID:   "u1",
+		Statuses: []string{"todo", "done"},
+		Fields:   []CustomField{{Key: "points", Type: types.FieldNumber}},
+	}
+
+	tests := []struct {
+		input   string
+		pos     int
+		end     int
+		message string
+	}{
+		{input: "priority ~ high", pos: 9, end: 10, message: "operator ~ is not supported by priority"},
+		{input: "status = on_progress", pos: 9, end: 20, message: "invalid status"},
+		{input: "status IS EMPTY", pos: 7, end: 15, message: "operator IS EMPTY is not supported"},
+		{input: "due < tomorrow", pos: 6, end: 14, message: "invalid date"},
+		{input: "due = yesterday()", pos: 6, end: 17, message: "unknown function yesterday()"},
+		{input: "parent = abc", pos: 9, end: 12, message: "invalid parent"},
+		{input: "label = now()", pos: 8, end: 13, message: "expected a label"},
+		{input: "cf.nope = 1", pos: 0, end: 7, message: "unknown custom field"},
+		{input: "cf.points = many", pos: 12, end: 16, message: "expected a number"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			query, err := Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse(%q) error: %v", tt.input, err)
+			}
+
+			_, err = Compile(query, ctx)
+
+			var jqlErr *Error
+			if !errors.As(err, &jqlErr) {
+				t.Fatalf("Compile(%q) error = %v, want *Error", tt.input, err)
+			}
+
+			if jqlErr.Pos != tt.pos || jqlErr.End != tt.end {
+				t.Errorf("Compile(%q) error at [%d, %d), want [%d, %d)", tt.input, jqlErr.Pos, jqlErr.End, tt.pos, tt.end)
+			}
+
+			if !strings.Contains(jqlErr.Message, tt.message) {
+				t.Errorf("Compile(%q) error = %q, want containing %q", tt.input, jqlErr.Message, tt.message)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package jql
+
+import (
+	"strconv"
+	"strings"
+	"webservices/src/types"
+)
+
+// customPrefix the project custom fields are queried as `cf.<key>`
+const customPrefix = "cf."
+
+// CustomField a project defined field the query able to filter on
+type CustomField struct {
+	Key     string
+	Type    types.CustomFieldType
+	Options []string
+}
+
+var customOperators = map[types.CustomFieldType][]string{
+	types.FieldText:        {"~", "!~", "IS EMPTY", "IS NOT EMPTY"},
+	types.FieldNumber:      {"=", "!=", ">", ">=", "<", "<=", "IS EMPTY", "IS NOT EMPTY"},
+	types.FieldSelect:      {"=", "!=", "IN", "NOT IN", "IS EMPTY", "IS NOT EMPTY"},
+	types.FieldMultiSelect: {"=", "!=", "IN", "NOT IN", "IS EMPTY", "IS NOT EMPTY"},
+	types.FieldDate:        {"=", "!=", ">", ">=", "<", "<=", "IS EMPTY", "IS NOT EMPTY"},
+	types.FieldUser:        {"=", "!=", "IN", "NOT IN", "IS EMPTY", "IS NOT EMPTY"},
+}
+
+// custom the values are kept on the `issues.custom_fields` jsonb, the key always passed as an argument
+func (c *compiler) custom(clause *Clause) (string, error) {
+	key := strings.TrimPrefix(clause.Field, customPrefix)
+
+	var f *CustomField
+	for i := range c.ctx.Fields {
+		if c.ctx.Fields[i].Key == key {
+			f = &c.ctx.Fields[i]
+		}
+	}
+
+	if f == nil {
+		return "", errorAt(clause.token, "unknown custom field %q", key)
+	}
+
+	allowed := false
+	for _, op := range customOperators[f.Type] {
+		allowed = allowed || op == clause.Op
+	}
+
+	if !allowed {
+		return "", errorAt(clause.opTok, "operator %s is not supported by %s, use one of: %s",
+			clause.Op, clause.Field, strings.Join(customOperators[f.Type], ", "))
+	}
+
+	switch clause.Op {
+	case "IS EMPTY":
+		c.args = append(c.args, f.Key)
+		return "issues.custom_fields->?::text IS NULL", nil
+	case "IS NOT EMPTY":
+		c.args = append(c.args, f.Key)
+		return "issues.custom_fields->?::text IS NOT NULL", nil
+	}
+
+	for _, v := range clause.Values {
+		if v.Kind == valueFunc && !(f.Type == types.FieldDate ||
+			(f.Type == types.FieldUser && strings.EqualFold(v.Raw, "currentUser"))) {
+			return "", errorAt(v.token, "expected a value, got function %s()", v.Raw)
+		}
+	}
+
+	switch f.Type {
+	case types.FieldText:
+		pattern := "%" + strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(clause.Values[0].Raw) + "%"
+		c.args = append(c.args, f.Key, pattern)
+
+		sql := "COALESCE(issues.custom_fields->>?::text, '') ILIKE ?"
+		if clause.Op == "!~" {
+			return "NOT " + sql, nil
+		}
+		return sql, nil
+
+	case types.FieldNumber:
+		number, err := strconv.ParseFloat(clause.Values[0].Raw, 64)
+		if err != nil {
+			return "", errorAt(clause.Values[0].token, "invalid %s %q, expected a number", clause.Field, clause.Values[0].Raw)
+		}
+		c.args = append(c.args, f.Key, number)
+		return "(issues.custom_fields->>?::text)::numeric " + clause.Op + " ?", nil
+
+	case types.FieldDate:
+		value, _, err := c.resolveDate(clause.Values[0])
+		if err != nil {
+			return "", err
+		}
+		c.args = append(c.args, f.Key, value.Format("2006-01-02"))
+		return "(issues.custom_fields->>?::text)::date " + clause.Op + " ?::date", nil
+
+	case types.FieldUser:
+		// matched by id, `currentUser()`, email or name, lowercased as the uuid text is
+		values := make([]string, 0, len(clause.Values))
+		for _, v := range clause.Values {
+			if v.Kind == valueFunc {
+				values = append(values, strings.ToLower(c.ctx.UserID))
+			} else {
+				values = append(values, strings.ToLower(v.Raw))
+			}
+		}
+
+		c.args = append(c.args, f.Key, values, values, values)
+		return c.negate(clause.Op, "issues.custom_fields->>?::text IN (SELECT id::text FROM users "+
+			"WHERE id::text IN ? OR LOWER(email) IN ? OR LOWER(name) IN ?)"), nil
+	}
+
+	values := make([]string, 0, len(clause.Values))
+	for _, v := range clause.Values {
+		valid := false
+		for _, option := range f.Options {
+			if strings.EqualFold(option, strings.TrimSpace(v.Raw)) {
+				valid = true
+				values = append(values, option)
+			}
+		}
+
+		if !valid {
+			return "", errorAt(v.token, "invalid %s %q, expected one of: %s",
+				clause.Field, v.Raw, strings.Join(f.Options, ", "))
+		}
+	}
+
+	// multi select matching the issues having any of the options
+	if f.Type == types.FieldMultiSelect {
+		c.args = append(c.args, f.Key, values)
+		return c.negate(clause.Op, "EXISTS (SELECT 1 FROM jsonb_array_elements_text("+
+			"COALESCE(issues.custom_fields->?::text, '[]')) AS v(value) WHERE v.value IN ?)"), nil
+	}
+
+	c.args = append(c.args, f.Key, values)
+	return c.negate(clause.Op, "issues.custom_fields->>?::text IN ?"), nil
+}
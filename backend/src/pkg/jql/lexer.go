@@ -0,0 +1,108 @@
+package jql
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF    tokenKind = iota
+	tokenWord             // field, keyword or bare value
+	tokenString           // quoted value
+	tokenOp               // = != > >= < <= ~ !~
+	tokenLParen
+	tokenRParen
+	tokenComma
+)
+
+// token position are rune offsets of the input, `end` exclusive
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+	end  int
+}
+
+func (t token) is(keyword string) bool {
+	return t.kind == tokenWord && strings.EqualFold(t.text, keyword)
+}
+
+func (t token) String() string {
+	if t.kind == tokenEOF {
+		return "end of query"
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+const specialChars = `()=,!<>~"'`
+
+func lex(input string) ([]token, error) {
+	runes := []rune(input)
+	tokens := make([]token, 0)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenLParen, text: "(", pos: i, end: i + 1})
+			i++
+
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenRParen, text: ")", pos: i, end: i + 1})
+			i++
+
+		case r == ',':
+			tokens = append(tokens, token{kind: tokenComma, text: ",", pos: i, end: i + 1})
+			i++
+
+		case r == '"' || r == '\'':
+			start := i
+			var value strings.Builder
+			i++
+			for ; i < len(runes) && runes[i] != r; i++ {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+				}
+				value.WriteRune(runes[i])
+			}
+
+			if i >= len(runes) {
+				return nil, &Error{Pos: start, End: len(runes), Message: "unterminated string"}
+			}
+
+			i++
+			tokens = append(tokens, token{kind: tokenString, text: value.String(), pos: start, end: i})
+
+		case strings.ContainsRune("=!<>~", r):
+			start := i
+			op := string(r)
+			if i+1 < len(runes) && (runes[i+1] == '=' || (r == '!' && runes[i+1] == '~')) {
+				op += string(runes[i+1])
+			}
+
+			if op == "!" || op == "~=" {
+				return nil, &Error{Pos: start, End: start + len(op), Message: fmt.Sprintf("invalid operator %q", op)}
+			}
+
+			i += len([]rune(op))
+			tokens = append(tokens, token{kind: tokenOp, text: op, pos: start, end: i})
+
+		default:
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && !strings.ContainsRune(specialChars, runes[i]) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenWord, text: string(runes[start:i]), pos: start, end: i})
+		}
+	}
+
+	tokens = append(tokens, token{kind: tokenEOF, pos: len(runes), end: len(runes)})
+	return tokens, nil
+}
@@ -0,0 +1,125 @@
+package jql
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestLex(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []token
+	}{
+		{
+			name:  "empty",
+			input: "   ",
+			want:  []token{{kind: tokenEOF, pos: 3, end: 3}},
+		},
+		{
+			name:  "clause",
+			input: "status != done",
+			want: []token{
+				{kind: tokenWord, text: "status", pos: 0, end: 6},
+				{kind: tokenOp, text: "!=", pos: 7, end: 9},
+				{kind: tokenWord, text: "done", pos: 10, end: 14},
+				{kind: tokenEOF, pos: 14, end: 14},
+			},
+		},
+		{
+			name:  "operators without spaces",
+			input: "due>=-7d",
+			want: []token{
+				{kind: tokenWord, text: "due", pos: 0, end: 3},
+				{kind: tokenOp, text: ">=", pos: 3, end: 5},
+				{kind: tokenWord, text: "-7d", pos: 5, end: 8},
+				{kind: tokenEOF, pos: 8, end: 8},
+			},
+		},
+		{
+			name:  "not contains",
+			input: "title !~ x",
+			want: []token{
+				{kind: tokenWord, text: "title", pos: 0, end: 5},
+				{kind: tokenOp, text: "!~", pos: 6, end: 8},
+				{kind: tokenWord, text: "x", pos: 9, end: 10},
+				{kind: tokenEOF, pos: 10, end: 10},
+			},
+		},
+		{
+			name:  "list",
+			input: "type IN (bug,task)",
+			want: []token{
+				{kind: tokenWord, text: "type", pos: 0, end: 4},
+				{kind: tokenWord, text: "IN", pos: 5, end: 7},
+				{kind: tokenLParen, text: "(", pos: 8, end: 9},
+				{kind: tokenWord, text: "bug", pos: 9, end: 12},
+				{kind: tokenComma, text: ",", pos: 12, end: 13},
+				{kind: tokenWord, text: "task", pos: 13, end: 17},
+				{kind: tokenRParen, text: ")", pos: 17, end: 18},
+				{kind: tokenEOF, pos: 18, end: 18},
+			},
+		},
+		{
+			name:  "quoted with escape",
+			input: `"say \"hi\"" 'x'`,
+			want: []token{
+				{kind: tokenString, text: `say "hi"`, pos: 0, end: 12},
+				{kind: tokenString, text: "x", pos: 13, end: 16},
+				{kind: tokenEOF, pos: 16, end: 16},
+			},
+		},
+		{
+			name:  "rune offsets",
+			input: "title ~ 日本 x",
+			want: []token{
+				{kind: tokenWord, text: "title", pos: 0, end: 5},
+				{kind: tokenOp, text: "~", pos: 6, end: 7},
+				{kind: tokenWord, text: "日本", pos: 8, end: 10},
+				{kind: tokenWord, text: "x", pos: 11, end: 12},
+				{kind: tokenEOF, pos: 12, end: 12},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := lex(tt.input)
+			if err != nil {
+				t.Fatalf("lex(%q) error: %v", tt.input, err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("lex(%q)\n got %+v\nwant %+v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLexError(t *testing.T) {
+	tests := []struct {
+		input string
+		pos   int
+		end   int
+	}{
+		{input: `title ~ "open`, pos: 8, end: 13},
+		{input: "status ! done", pos: 7, end: 8},
+		{input: "status ~= done", pos: 7, end: 9},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := lex(tt.input)
+
+			var jqlErr *Error
+			if !errors.As(err, &jqlErr) {
+				t.Fatalf("lex(%q) error = %v, want *Error", tt.input, err)
+			}
+
+			if jqlErr.Pos != tt.pos || jqlErr.End != tt.end {
+				t.Errorf("lex(%q) error at [%d, %d), want [%d, %d)", tt.input, jqlErr.Pos, jqlErr.End, tt.pos, tt.end)
+			}
+		})
+	}
+}
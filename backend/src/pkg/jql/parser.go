@@ -0,0 +1,424 @@
+package jql
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Error is a parse or compile error, `Pos` and `End` are rune offsets of the query so the UI able to highlight it
+type Error struct {
+	Pos     int    `json:"position"`
+	End     int    `json:"end"`
+	Message string `json:"message"`
+	Field   string `json:"field,omitempty" comment:"the input holding the error when validating several of them"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("invalid query at position %d: %s", e.Pos, e.Message)
+}
+
+func errorAt(t token, format string, args ...any) *Error {
+	return &Error{Pos: t.pos, End: t.end, Message: fmt.Sprintf(format, args...)}
+}
+
+type Node interface {
+	node()
+}
+
+// Binary is `AND` / `OR` of two expressions
+type Binary struct {
+	Op    string
+	Left  Node
+	Right Node
+}
+
+type Not struct {
+	Expr Node
+}
+
+// Clause is a `field op value(s)` condition
+type Clause struct {
+	Field  string
+	Op     string // = != > >= < <= ~ !~ IN, NOT IN, IS EMPTY, IS NOT EMPTY
+	Values []Value
+	token  token
+	opTok  token
+}
+
+// Text is a bare word or string, matched against the title & description
+type Text struct {
+	Value string
+}
+
+type valueKind int
+
+const (
+	valueWord valueKind = iota
+	valueString
+	valueFunc
+)
+
+type Value struct {
+	Raw   string
+	Kind  valueKind
+	token token
+}
+
+type Order struct {
+	Field string
+	Desc  bool
+}
+
+type Query struct {
+	Where   Node // nil matching everything
+	OrderBy []Order
+}
+
+func (*Binary) node() {}
+func (*Not) node()    {}
+func (*Clause) node() {}
+func (*Text) node()   {}
+
+type parser struct {
+	tokens []token
+	i      int
+}
+
+// Parse the query string, e.g.
+//
+//	status IN (todo, on_progress) AND assignee = currentUser() AND NOT label = blocked
+//	due < -7d OR "login error" ORDER BY priority DESC, created
+//	cf.severity IN (high, critical) AND cf.points >= 3
+func Parse(input string) (*Query, error) {
+	tokens, err := lex(input)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	query := &Query{}
+
+	if p.peek().kind != tokenEOF && !p.peek().is("ORDER") {
+		if query.Where, err = p.parseOr(); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.peek().is("ORDER") {
+		p.next()
+		if t := p.next(); !t.is("BY") {
+			return nil, errorAt(t, "expected BY after ORDER, got %s", t)
+		}
+
+		if query.OrderBy, err = p.parseOrder(); err != nil {
+			return nil, err
+		}
+	}
+
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, errorAt(t, "unexpected %s", t)
+	}
+
+	return query, nil
+}
+
+// ParseOrder parse the ORDER BY part only, e.g. `priority DESC, created`
+func ParseOrder(input string) ([]Order, error) {
+	tokens, err := lex(input)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	if p.peek().kind == tokenEOF {
+		return nil, nil
+	}
+
+	orders, err := p.parseOrder()
+	if err != nil {
+		return nil, err
+	}
+
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, errorAt(t, "unexpected %s", t)
+	}
+
+	return orders, nil
+}
+
+// And join both expressions, nil one is ignored
+func And(left, right Node) Node {
+	if left == nil {
+		return right
+	}
+
+	if right == nil {
+		return left
+	}
+
+	return &Binary{Op: "AND", Left: left, Right: right}
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.i]
+}
+
+func (p *parser) peekAt(offset int) token {
+	if p.i+offset >= len(p.tokens) {
+		return p.tokens[len(p.tokens)-1]
+	}
+	return p.tokens[p.i+offset]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.i]
+	if t.kind != tokenEOF {
+		p.i++
+	}
+	return t
+}
+
+func (p *parser) parseOr() (Node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().is("OR") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &Binary{Op: "OR", Left: left, Right: right}
+	}
+
+	return left, nil
+}
+
+// adjacent terms without operator are joined with AND
+func (p *parser) parseAnd() (Node, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		t := p.peek()
+		if t.is("AND") {
+			p.next()
+		} else if t.kind == tokenEOF || t.kind == tokenRParen || t.is("OR") || t.is("ORDER") {
+			return left, nil
+		}
+
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &Binary{Op: "AND", Left: left, Right: right}
+	}
+}
+
+func (p *parser) parseNot() (Node, error) {
+	if p.peek().is("NOT") {
+		p.next()
+		expr, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &Not{Expr: expr}, nil
+	}
+
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (Node, error) {
+	t := p.peek()
+
+	switch t.kind {
+	case tokenLParen:
+		p.next()
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		if closing := p.next(); closing.kind != tokenRParen {
+			return nil, errorAt(t, "missing closing parenthesis")
+		}
+		return expr, nil
+
+	case tokenString:
+		p.next()
+		return &Text{Value: t.text}, nil
+
+	case tokenWord:
+		if isReserved(t.text) {
+			return nil, errorAt(t, "unexpected keyword %s", strings.ToUpper(t.text))
+		}
+
+		next := p.peekAt(1)
+		if next.kind == tokenOp || next.is("IN") || next.is("IS") ||
+			(next.is("NOT") && p.peekAt(2).is("IN")) {
+			return p.parseClause()
+		}
+
+		p.next()
+		return &Text{Value: t.text}, nil
+	}
+
+	return nil, errorAt(t, "unexpected %s", t)
+}
+
+func (p *parser) parseClause() (Node, error) {
+	field := p.next()
+	name := strings.ToLower(field.text)
+	if _, ok := fields[name]; !ok && !strings.HasPrefix(name, customPrefix) {
+		return nil, errorAt(field, "unknown field %q", field.text)
+	}
+
+	clause := &Clause{Field: name, token: field}
+
+	op := p.next()
+	clause.opTok = op
+	switch {
+	case op.kind == tokenOp:
+		clause.Op = op.text
+
+	case op.is("IN"):
+		clause.Op = "IN"
+
+	case op.is("NOT"):
+		clause.opTok.end = p.next().end // IN
+		clause.Op = "NOT IN"
+
+	case op.is("IS"):
+		clause.Op = "IS EMPTY"
+		if p.peek().is("NOT") {
+			p.next()
+			clause.Op = "IS NOT EMPTY"
+		}
+
+		t := p.next()
+		if !t.is("EMPTY") && !t.is("NULL") {
+			return nil, errorAt(t, "expected EMPTY after %s, got %s", strings.TrimSuffix(clause.Op, " EMPTY"), t)
+		}
+		clause.opTok.end = t.end
+		return clause, nil
+	}
+
+	if clause.Op == "IN" || clause.Op == "NOT IN" {
+		values, err := p.parseList()
+		if err != nil {
+			return nil, err
+		}
+		clause.Values = values
+		return clause, nil
+	}
+
+	// `= EMPTY` and `!= EMPTY` are the same as IS (NOT) EMPTY
+	if t := p.peek(); (t.is("EMPTY") || t.is("NULL")) && (clause.Op == "=" || clause.Op == "!=") {
+		clause.opTok.end = p.next().end
+		clause.Op = map[string]string{"=": "IS EMPTY", "!=": "IS NOT EMPTY"}[clause.Op]
+		return clause, nil
+	}
+
+	value, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	clause.Values = []Value{value}
+
+	return clause, nil
+}
+
+func (p *parser) parseList() ([]Value, error) {
+	open := p.next()
+	if open.kind != tokenLParen {
+		return nil, errorAt(open, "expected ( after IN, got %s", open)
+	}
+
+	values := make([]Value, 0)
+	for {
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+
+		t := p.next()
+		if t.kind == tokenRParen {
+			return values, nil
+		}
+
+		if t.kind != tokenComma {
+			return nil, errorAt(t, "expected , or ) in the list, got %s", t)
+		}
+	}
+}
+
+func (p *parser) parseValue() (Value, error) {
+	t := p.next()
+
+	switch t.kind {
+	case tokenString:
+		return Value{Raw: t.text, Kind: valueString, token: t}, nil
+
+	case tokenWord:
+		if isReserved(t.text) {
+			return Value{}, errorAt(t, "expected a value, got keyword %s", strings.ToUpper(t.text))
+		}
+
+		if p.peek().kind == tokenLParen {
+			p.next()
+			closing := p.next()
+			if closing.kind != tokenRParen {
+				return Value{}, errorAt(closing, "functions take no argument, expected )")
+			}
+
+			t.end = closing.end
+			return Value{Raw: t.text, Kind: valueFunc, token: t}, nil
+		}
+
+		return Value{Raw: t.text, Kind: valueWord, token: t}, nil
+	}
+
+	return Value{}, errorAt(t, "expected a value, got %s", t)
+}
+
+func (p *parser) parseOrder() ([]Order, error) {
+	orders := make([]Order, 0)
+	for {
+		t := p.next()
+		if t.kind != tokenWord {
+			return nil, errorAt(t, "expected a field to order by, got %s", t)
+		}
+
+		name := strings.ToLower(t.text)
+		if _, ok := orderColumns[name]; !ok {
+			return nil, errorAt(t, "unable to order by %q", t.text)
+		}
+
+		order := Order{Field: name}
+		if p.peek().is("ASC") {
+			p.next()
+		} else if p.peek().is("DESC") {
+			p.next()
+			order.Desc = true
+		}
+		orders = append(orders, order)
+
+		if p.peek().kind != tokenComma {
+			return orders, nil
+		}
+		p.next()
+	}
+}
+
+func isReserved(word string) bool {
+	switch strings.ToUpper(word) {
+	case "AND", "OR", "NOT", "IN", "IS", "ORDER", "BY", "EMPTY", "NULL":
+		return true
+	}
+	return false
+}
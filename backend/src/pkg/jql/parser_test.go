@@ -0,0 +1,156 @@
+package jql
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// render the tree in a compact form, the values of a clause listed within brackets
+func render(node Node) string {
+	switch n := node.(type) {
+	case nil:
+		return "<nil>"
+	case *Binary:
+		return "(" + render(n.Left) + " " + n.Op + " " + render(n.Right) + ")"
+	case *Not:
+		return "NOT " + render(n.Expr)
+	case *Text:
+		return fmt.Sprintf("text(%s)", n.Value)
+	case *Clause:
+		values := make([]string, len(n.Values))
+		for i, v := range n.Values {
+			values[i] = v.Raw
+			if v.Kind == valueFunc {
+				values[i] += "()"
+			}
+		}
+
+		if len(values) == 0 {
+			return n.Field + " " + n.Op
+		}
+		return n.Field + " " + n.Op + " [" + strings.Join(values, " ") + "]"
+	}
+
+	return fmt.Sprintf("%T", node)
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input string
+		where string
+		order []Order
+	}{
+		{input: "", where: "<nil>"},
+		{input: "status = done", where: "status = [done]"},
+		{input: "STATUS = Done", where: "status = [Done]"},
+		{
+			input: "status IN (todo, on_progress) AND assignee = currentUser() AND NOT label = blocked",
+			where: "((status IN [todo on_progress] AND assignee = [currentUser()]) AND NOT label = [blocked])",
+		},
+		{input: "a OR b c", where: "(text(a) OR (text(b) AND text(c)))"},
+		{input: "(a OR b) c", where: "((text(a) OR text(b)) AND text(c))"},
+		{input: "NOT NOT a", where: "NOT NOT text(a)"},
+		{input: "due = EMPTY", where: "due IS EMPTY"},
+		{input: "due != null", where: "due IS NOT EMPTY"},
+		{input: "assignee IS NOT EMPTY", where: "assignee IS NOT EMPTY"},
+		{input: "parent not in (x, y)", where: "parent NOT IN [x y]"},
+		{input: `title ~ "login error"`, where: "title ~ [login error]"},
+		{input: "cf.severity IN (high)", where: "cf.severity IN [high]"},
+		{
+			input: `"login error" ORDER BY priority DESC, created`,
+			where: "text(login error)",
+			order: []Order{{Field: "priority", Desc: true}, {Field: "created"}},
+		},
+		{input: "order by rank asc", where: "<nil>", order: []Order{{Field: "rank"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			query, err := Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse(%q) error: %v", tt.input, err)
+			}
+
+			if got := render(query.Where); got != tt.where {
+				t.Errorf("Parse(%q) where = %s, want %s", tt.input, got, tt.where)
+			}
+
+			if len(query.OrderBy) != 0 || len(tt.order) != 0 {
+				if !reflect.DeepEqual(query.OrderBy, tt.order) {
+					t.Errorf("Parse(%q) order = %+v, want %+v", tt.input, query.OrderBy, tt.order)
+				}
+			}
+		})
+	}
+}
+
+func TestParseError(t *testing.T) {
+	tests := []struct {
+		input   string
+		pos     int
+		end     int
+		message string
+	}{
+		{input: "status =", pos: 8, end: 8, message: "expected a value"},
+		{input: "foo = bar", pos: 0, end: 3, message: "unknown field"},
+		{input: "(status = done", pos: 0, end: 1, message: "missing closing parenthesis"},
+		{input: "status IN todo", pos: 10, end: 14, message: "expected ( after IN"},
+		{input: "status IN (todo done)", pos: 16, end: 20, message: "expected , or )"},
+		{input: "assignee IS foo", pos: 12, end: 15, message: "expected EMPTY"},
+		{input: "ORDER priority", pos: 6, end: 14, message: "expected BY"},
+		{input: "ORDER BY assignee", pos: 9, end: 17, message: "unable to order"},
+		{input: "AND status = done", pos: 0, end: 3, message: "unexpected keyword AND"},
+		{input: "status = done)", pos: 13, end: 14, message: "unexpected"},
+		{input: "assignee = me(x)", pos: 14, end: 15, message: "functions take no argument"},
+		{input: "status = IN", pos: 9, end: 11, message: "expected a value, got keyword IN"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := Parse(tt.input)
+
+			var jqlErr *Error
+			if !errors.As(err, &jqlErr) {
+				t.Fatalf("Parse(%q) error = %v, want *Error", tt.input, err)
+			}
+
+			if jqlErr.Pos != tt.pos || jqlErr.End != tt.end {
+				t.Errorf("Parse(%q) error at [%d, %d), want [%d, %d)", tt.input, jqlErr.Pos, jqlErr.End, tt.pos, tt.end)
+			}
+
+			if !strings.Contains(jqlErr.Message, tt.message) {
+				t.Errorf("Parse(%q) error = %q, want containing %q", tt.input, jqlErr.Message, tt.message)
+			}
+		})
+	}
+}
+
+func TestParseOrder(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []Order
+		err   bool
+	}{
+		{input: "", want: nil},
+		{input: "due", want: []Order{{Field: "due"}}},
+		{input: "Priority DESC, title", want: []Order{{Field: "priority", Desc: true}, {Field: "title"}}},
+		{input: "label", err: true},
+		{input: "due due", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseOrder(tt.input)
+			if (err != nil) != tt.err {
+				t.Fatalf("ParseOrder(%q) error = %v, want error %v", tt.input, err, tt.err)
+			}
+
+			if !tt.err && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseOrder(%q) = %+v, want %+v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package mention
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// pattern `@handle`, `@email` or `@"Full Name"`, the `@` not preceded by a word so the emails
+// written in the text are not taken as mentions
+var pattern = regexp.MustCompile(`(?:^|[^\p{L}\p{N}_.@])@(?:"([^"\n]{1,100})"|([\p{L}\p{N}_.+-]+(?:@[\p{L}\p{N}-]+(?:\.[\p{L}\p{N}-]+)+)?))`)
+
+// Parse the handles mentioned in the text, deduped case-insensitively in order of appearance
+func Parse(text string) []string {
+	handles := make([]string, 0)
+	seen := make(map[string]bool)
+
+	for _, match := range pattern.FindAllStringSubmatch(text, -1) {
+		handle := strings.TrimSpace(match[1])
+		if handle == "" {
+			// the trailing dot ending the sentence, e.g. "thanks @john."
+			handle = strings.TrimRight(match[2], ".")
+		}
+
+		key := strings.ToLower(handle)
+		if handle == "" || seen[key] {
+			continue
+		}
+
+		seen[key] = true
+		handles = append(handles, handle)
+	}
+
+	return handles
+}
+
+// Match whether the handle refer to the user, by the email or the name with or without its spaces
+func Match(handle, name, email string) bool {
+	if handle == "" {
+		return false
+	}
+
+	if strings.EqualFold(handle, email) || strings.EqualFold(handle, strings.TrimSpace(name)) {
+		return true
+	}
+
+	compact := strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, name)
+
+	return compact != "" && strings.EqualFold(handle, compact)
+}
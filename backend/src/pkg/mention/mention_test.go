@@ -0,0 +1,63 @@
+package mention
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{name: "none", text: "no mention here", want: []string{}},
+		{name: "handle", text: "@john please review", want: []string{"john"}},
+		{name: "several", text: "cc @john, @jane_doe and @bob.smith", want: []string{"john", "jane_doe", "bob.smith"}},
+		{name: "sentence end", text: "thanks @john.", want: []string{"john"}},
+		{name: "email", text: "ping @john.doe@example.co.id today", want: []string{"john.doe@example.co.id"}},
+		{name: "quoted name", text: `assign to @"Jane Doe" now`, want: []string{"Jane Doe"}},
+		{name: "quoted name trimmed", text: `@" Jane Doe "`, want: []string{"Jane Doe"}},
+		{name: "plain email ignored", text: "mail john@example.com", want: []string{}},
+		{name: "preceded by word ignored", text: "foo@bar and x.@y", want: []string{}},
+		{name: "after punctuation", text: "(@john) [@jane]", want: []string{"john", "jane"}},
+		{name: "deduped case-insensitively", text: "@John @john @JOHN", want: []string{"John"}},
+		{name: "unicode", text: "halo @Budi_Santoso dan @日本", want: []string{"Budi_Santoso", "日本"}},
+		{name: "newline", text: "first line\n@jane", want: []string{"jane"}},
+		{name: "unterminated quote", text: `@"Jane`, want: []string{}},
+		{name: "lone at", text: "meet @ noon", want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Parse(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		handle string
+		name   string
+		email  string
+		want   bool
+	}{
+		{handle: "jane@example.com", name: "Jane Doe", email: "jane@example.com", want: true},
+		{handle: "JANE@EXAMPLE.COM", name: "Jane Doe", email: "jane@example.com", want: true},
+		{handle: "Jane Doe", name: " Jane Doe ", email: "jd@example.com", want: true},
+		{handle: "janedoe", name: "Jane Doe", email: "jd@example.com", want: true},
+		{handle: "jane", name: "Jane Doe", email: "jd@example.com", want: false},
+		{handle: "jd", name: "Jane Doe", email: "jd@example.com", want: false},
+		{handle: "", name: "", email: "jd@example.com", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.handle, func(t *testing.T) {
+			if got := Match(tt.handle, tt.name, tt.email); got != tt.want {
+				t.Errorf("Match(%q, %q, %q) = %v, want %v", tt.handle, tt.name, tt.email, got, tt.want)
+			}
+		})
+	}
+}
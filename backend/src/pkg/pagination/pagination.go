@@ -0,0 +1,216 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	DefaultLimit = 50
+	MaxLimit     = 200
+)
+
+// Cursor the position of the row on the (created_at, id) ordering, or on the (order_index, id) one
+// when `Position` given. encoded opaque to the client
+type Cursor struct {
+	CreatedAt time.Time `json:"t,omitzero"`
+	Position  *int      `json:"p,omitempty"`
+	ID        string    `json:"id"`
+	Backward  bool      `json:"b,omitempty"` // fetching the page before the cursor
+}
+
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func Decode(value string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	var cursor Cursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID == "" ||
+		(cursor.CreatedAt.IsZero() && cursor.Position == nil) {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	return &cursor, nil
+}
+
+type Params struct {
+	Limit  int
+	Cursor *Cursor
+}
+
+// New clamp the limit and decode the cursor, empty cursor is the first page
+func New(limit int, cursor string) (Params, error) {
+	params := Params{Limit: limit}
+
+	if params.Limit <= 0 {
+		params.Limit = DefaultLimit
+	}
+
+	if params.Limit > MaxLimit {
+		params.Limit = MaxLimit
+	}
+
+	if cursor != "" {
+		c, err := Decode(cursor)
+		if err != nil {
+			return params, err
+		}
+		params.Cursor = c
+	}
+
+	return params, nil
+}
+
+// Scope apply the keyset condition, ordering and limit on `table`, `desc` for newest first.
+// one extra row is fetched to detect the next page, so the rows must be passed to `NewPage` afterward
+func (p Params) Scope(table string, desc bool) func(*gorm.DB) *gorm.DB {
+	// backward page walk the other direction then reversed by `NewPage`
+	if p.Cursor != nil && p.Cursor.Backward {
+		desc = !desc
+	}
+
+	return func(db *gorm.DB) *gorm.DB {
+
+		createdAt := table + ".created_at"
+		id := table + ".id"
+
+		if p.Cursor != nil {
+			if p.Cursor.Position != nil {
+				db.AddError(fmt.Errorf("invalid cursor"))
+				return db
+			}
+
+			op := ">"
+			if desc {
+				op = "<"
+			}
+			db = db.Where(fmt.Sprintf("(%s, %s) %s (?, ?)", createdAt, id, op), p.Cursor.CreatedAt, p.Cursor.ID)
+		}
+
+		direction := "ASC"
+		if desc {
+			direction = "DESC"
+		}
+
+		return db.
+			Order(createdAt + " " + direction).
+			Order(id + " " + direction).
+			Limit(p.Limit + 1)
+	}
+}
+
+// PositionScope the `Scope` keyed on (order_index, id) ascending, the pages following the drag ordering.
+// the rows must be passed to `NewPositionPage` afterward
+func (p Params) PositionScope(table string) func(*gorm.DB) *gorm.DB {
+	desc := p.Cursor != nil && p.Cursor.Backward
+
+	return func(db *gorm.DB) *gorm.DB {
+		position := table + ".order_index"
+		id := table + ".id"
+
+		if p.Cursor != nil {
+			if p.Cursor.Position == nil {
+				db.AddError(fmt.Errorf("invalid cursor"))
+				return db
+			}
+
+			op := ">"
+			if desc {
+				op = "<"
+			}
+			db = db.Where(fmt.Sprintf("(%s, %s) %s (?, ?)", position, id, op), *p.Cursor.Position, p.Cursor.ID)
+		}
+
+		direction := "ASC"
+		if desc {
+			direction = "DESC"
+		}
+
+		return db.
+			Order(position + " " + direction).
+			Order(id + " " + direction).
+			Limit(p.Limit + 1)
+	}
+}
+
+// Info the pagination part of the response envelope
+type Info struct {
+	Limit      int     `json:"limit"`
+	NextCursor *string `json:"nextCursor"`
+	PrevCursor *string `json:"prevCursor"`
+	HasMore    bool    `json:"hasMore"`
+}
+
+type Page[T any] struct {
+	Items []T
+	Info  Info
+}
+
+// NewPage trim the extra row fetched by `Scope` and build the cursors from the first & last item
+func NewPage[T any](items []T, p Params, key func(T) (time.Time, string)) Page[T] {
+	return newPage(items, p, func(item T) Cursor {
+		createdAt, id := key(item)
+		return Cursor{CreatedAt: createdAt, ID: id}
+	})
+}
+
+// NewPositionPage the `NewPage` of the rows fetched by `PositionScope`
+func NewPositionPage[T any](items []T, p Params, key func(T) (int, string)) Page[T] {
+	return newPage(items, p, func(item T) Cursor {
+		position, id := key(item)
+		return Cursor{Position: &position, ID: id}
+	})
+}
+
+func newPage[T any](items []T, p Params, key func(T) Cursor) Page[T] {
+	backward := p.Cursor != nil && p.Cursor.Backward
+
+	extra := len(items) > p.Limit
+	if extra {
+		items = items[:p.Limit]
+	}
+
+	if backward {
+		slices.Reverse(items)
+	}
+
+	if items == nil {
+		items = make([]T, 0)
+	}
+
+	page := Page[T]{Items: items, Info: Info{Limit: p.Limit}}
+	if len(items) == 0 {
+		return page
+	}
+
+	cursor := func(item T, backward bool) *string {
+		c := key(item)
+		c.Backward = backward
+		value := c.Encode()
+		return &value
+	}
+
+	// going forward, the extra row means a next page; going backward it means a previous page.
+	// coming from a cursor there is always a page on the other side
+	if (!backward && extra) || backward {
+		page.Info.NextCursor = cursor(items[len(items)-1], false)
+	}
+
+	if (backward && extra) || (!backward && p.Cursor != nil) {
+		page.Info.PrevCursor = cursor(items[0], true)
+	}
+
+	page.Info.HasMore = page.Info.NextCursor != nil
+	return page
+}
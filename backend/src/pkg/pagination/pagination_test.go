@@ -0,0 +1,205 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCursor(t *testing.T) {
+	at := time.Date(2026, 10, 17, 8, 30, 15, 123456789, time.UTC)
+
+	tests := []struct {
+		name   string
+		cursor Cursor
+	}{
+		{name: "forward", cursor: Cursor{CreatedAt: at, ID: "a1"}},
+		{name: "backward", cursor: Cursor{CreatedAt: at, ID: "a1", Backward: true}},
+		{name: "zoned", cursor: Cursor{CreatedAt: at.In(time.FixedZone("WIB", 7*3600)), ID: "b2"}},
+		{name: "position", cursor: Cursor{Position: ptr(3), ID: "c3"}},
+		{name: "first position", cursor: Cursor{Position: ptr(0), ID: "c3", Backward: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Decode(tt.cursor.Encode())
+			if err != nil {
+				t.Fatalf("Decode error: %v", err)
+			}
+
+			if !got.CreatedAt.Equal(tt.cursor.CreatedAt) || got.ID != tt.cursor.ID || got.Backward != tt.cursor.Backward ||
+				!reflect.DeepEqual(got.Position, tt.cursor.Position) {
+				t.Errorf("Decode(Encode(%+v)) = %+v", tt.cursor, *got)
+			}
+		})
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	encode := func(value string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(value))
+	}
+
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{name: "not base64", value: "%%%"},
+		{name: "padded base64", value: base64.URLEncoding.EncodeToString([]byte(`{"t":"2026-10-17T00:00:00Z","id":"a"}`))},
+		{name: "not json", value: encode("cursor")},
+		{name: "missing id", value: encode(`{"t":"2026-10-17T00:00:00Z"}`)},
+		{name: "missing time", value: encode(`{"id":"a"}`)},
+		{name: "invalid time", value: encode(`{"t":"yesterday","id":"a"}`)},
+		{name: "invalid position", value: encode(`{"p":"first","id":"a"}`)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Decode(tt.value); err == nil {
+				t.Errorf("Decode(%q) expected an error", tt.value)
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	cursor := Cursor{CreatedAt: time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC), ID: "a"}
+
+	tests := []struct {
+		name   string
+		limit  int
+		cursor string
+		want   int
+		err    bool
+	}{
+		{name: "default", limit: 0, want: DefaultLimit},
+		{name: "negative", limit: -5, want: DefaultLimit},
+		{name: "within", limit: 20, want: 20},
+		{name: "clamped", limit: MaxLimit + 1, want: MaxLimit},
+		{name: "with cursor", limit: 10, cursor: cursor.Encode(), want: 10},
+		{name: "invalid cursor", limit: 10, cursor: "x", want: 10, err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params, err := New(tt.limit, tt.cursor)
+			if (err != nil) != tt.err {
+				t.Fatalf("New error = %v, want error %v", err, tt.err)
+			}
+
+			if params.Limit != tt.want {
+				t.Errorf("New(%d) limit = %d, want %d", tt.limit, params.Limit, tt.want)
+			}
+
+			if !tt.err && (params.Cursor != nil) != (tt.cursor != "") {
+				t.Errorf("New cursor = %+v, want decoded %q", params.Cursor, tt.cursor)
+			}
+		})
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+type row struct {
+	at time.Time
+	id string
+}
+
+func TestNewPage(t *testing.T) {
+	base := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+	rows := func(ids ...string) []row {
+		result := make([]row, len(ids))
+		for i, id := range ids {
+			result[i] = row{at: base.Add(time.Duration(i) * time.Minute), id: id}
+		}
+		return result
+	}
+	key := func(r row) (time.Time, string) { return r.at, r.id }
+	forward := &Cursor{CreatedAt: base, ID: "z"}
+	backward := &Cursor{CreatedAt: base, ID: "z", Backward: true}
+
+	tests := []struct {
+		name    string
+		items   []row
+		cursor  *Cursor
+		want    []string
+		next    string // id the next cursor points at, empty for none
+		prev    string // id the prev cursor points at, empty for none
+		hasMore bool
+	}{
+		{name: "empty", items: nil, want: []string{}},
+		{name: "single page", items: rows("a", "b"), want: []string{"a", "b"}},
+		{name: "first page", items: rows("a", "b", "c"), want: []string{"a", "b"}, next: "b", hasMore: true},
+		{name: "middle page", items: rows("a", "b", "c"), cursor: forward, want: []string{"a", "b"}, next: "b", prev: "a", hasMore: true},
+		{name: "last page", items: rows("a"), cursor: forward, want: []string{"a"}, prev: "a"},
+		// fetched walking backward, the rows come reversed
+		{name: "backward page", items: rows("c", "b", "a"), cursor: backward, want: []string{"b", "c"}, next: "c", prev: "b", hasMore: true},
+		{name: "backward first page", items: rows("b", "a"), cursor: backward, want: []string{"a", "b"}, next: "b", hasMore: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page := NewPage(tt.items, Params{Limit: 2, Cursor: tt.cursor}, key)
+
+			ids := make([]string, len(page.Items))
+			for i, item := range page.Items {
+				ids[i] = item.id
+			}
+
+			if !reflect.DeepEqual(ids, tt.want) {
+				t.Errorf("items = %v, want %v", ids, tt.want)
+			}
+
+			check := func(name string, value *string, wantID string, wantBackward bool) {
+				if wantID == "" {
+					if value != nil {
+						t.Errorf("%s cursor = %q, want none", name, *value)
+					}
+					return
+				}
+
+				if value == nil {
+					t.Fatalf("%s cursor missing, want %q", name, wantID)
+				}
+
+				cursor, err := Decode(*value)
+				if err != nil {
+					t.Fatalf("%s cursor decode error: %v", name, err)
+				}
+
+				if cursor.ID != wantID || cursor.Backward != wantBackward {
+					t.Errorf("%s cursor = %+v, want id %q backward %v", name, *cursor, wantID, wantBackward)
+				}
+			}
+
+			check("next", page.Info.NextCursor, tt.next, false)
+			check("prev", page.Info.PrevCursor, tt.prev, true)
+
+			if page.Info.HasMore != tt.hasMore {
+				t.Errorf("hasMore = %v, want %v", page.Info.HasMore, tt.hasMore)
+			}
+		})
+	}
+}
+
+func TestNewPositionPage(t *testing.T) {
+	items := []row{{id: "a"}, {id: "b"}, {id: "c"}}
+	key := func(r row) (int, string) { return len(r.id) + 4, r.id }
+
+	page := NewPositionPage(items, Params{Limit: 2}, key)
+	if len(page.Items) != 2 || page.Info.NextCursor == nil || page.Info.PrevCursor != nil {
+		t.Fatalf("page = %+v", page)
+	}
+
+	cursor, err := Decode(*page.Info.NextCursor)
+	if err != nil {
+		t.Fatalf("next cursor decode error: %v", err)
+	}
+
+	if cursor.Position == nil || *cursor.Position != 5 || cursor.ID != "b" || !cursor.CreatedAt.IsZero() {
+		t.Errorf("next cursor = %+v, want position 5 id %q", *cursor, "b")
+	}
+}
@@ -0,0 +1,201 @@
+package replay
+
+import (
+	"slices"
+	"time"
+)
+
+// the activity keys holding the tracked fields
+const (
+	KeyStatus            = "status"
+	KeySprint            = "sprint"
+	KeyOriginalEstimate  = "original_estimate"
+	KeyRemainingEstimate = "remaining_estimate"
+	KeyParent            = "parents"
+)
+
+// State the tracked fields of an issue at a point in time, estimates in minutes.
+// `ParentID` the parent of a subtask, nil for the top-level issues
+type State struct {
+	IssueID           string
+	ParentID          *string
+	CreatedAt         time.Time
+	Status            string
+	SprintID          *string
+	OriginalEstimate  *int
+	RemainingEstimate *int
+}
+
+// Change a logged change of an issue, `Old` holding the values before it happened.
+// only the tracked keys present on `Old` are reverted
+type Change struct {
+	IssueID string
+	At      time.Time
+	Old     map[string]any
+}
+
+// Timeline reconstruct the issue states by reverting the changes newer than the requested time
+// from the current state, so issues logged before the activity history existed keep their current values.
+// a deleted issue take its activities along, only the existing issues are replayed
+type Timeline struct {
+	current []State
+	changes map[string][]Change // per issue, newest first
+}
+
+func New(current []State, changes []Change) *Timeline {
+	timeline := &Timeline{
+		current: current,
+		changes: make(map[string][]Change),
+	}
+
+	for _, change := range changes {
+		timeline.changes[change.IssueID] = append(timeline.changes[change.IssueID], change)
+	}
+
+	for _, list := range timeline.changes {
+		slices.SortStableFunc(list, func(a, b Change) int {
+			return b.At.Compare(a.At)
+		})
+	}
+
+	return timeline
+}
+
+// At the states of the issues created at or before `at`
+func (t *Timeline) At(at time.Time) []State {
+	states := make([]State, 0, len(t.current))
+
+	for _, current := range t.current {
+		if current.CreatedAt.After(at) {
+			continue
+		}
+
+		state := current
+		for _, change := range t.changes[current.IssueID] {
+			if !change.At.After(at) {
+				break
+			}
+			revert(&state, change.Old)
+		}
+
+		states = append(states, state)
+	}
+
+	return states
+}
+
+// Rollup fold the subtasks into their parent, the top-level states returned. the parent plan the work of its
+// subtasks on top of its own, and keep the remaining work of those not `done` yet. the subtasks without
+// their parent in the states left out
+func Rollup(states []State, done func(State) bool) []State {
+	planned := make(map[string]int)
+	remaining := make(map[string]int)
+	estimated := make(map[string]bool)
+
+	for _, state := range states {
+		if state.ParentID == nil {
+			continue
+		}
+
+		if value := coalesce(state.OriginalEstimate, state.RemainingEstimate); value != nil {
+			planned[*state.ParentID] += *value
+			estimated[*state.ParentID] = true
+
+			if !done(state) {
+				remaining[*state.ParentID] += *coalesce(state.RemainingEstimate, state.OriginalEstimate)
+			}
+		}
+	}
+
+	parents := make([]State, 0, len(states))
+	for _, state := range states {
+		if state.ParentID != nil {
+			continue
+		}
+
+		if estimated[state.IssueID] {
+			own, left := coalesce(state.OriginalEstimate, state.RemainingEstimate), coalesce(state.RemainingEstimate, state.OriginalEstimate)
+			state.OriginalEstimate = add(own, planned[state.IssueID])
+			state.RemainingEstimate = add(left, remaining[state.IssueID])
+		}
+
+		parents = append(parents, state)
+	}
+
+	return parents
+}
+
+func coalesce(values ...*int) *int {
+	for _, v := range values {
+		if v != nil {
+			return v
+		}
+	}
+	return nil
+}
+
+func add(value *int, amount int) *int {
+	result := amount
+	if value != nil {
+		result += *value
+	}
+	return &result
+}
+
+func revert(state *State, old map[string]any) {
+	if value, ok := old[KeyStatus]; ok {
+		// the approval request on creation log an empty previous status
+		if status, ok := value.(string); ok && status != "" {
+			state.Status = status
+		}
+	}
+
+	if value, ok := old[KeySprint]; ok {
+		state.SprintID = toString(value)
+	}
+
+	if value, ok := old[KeyOriginalEstimate]; ok {
+		state.OriginalEstimate = toInt(value)
+	}
+
+	if value, ok := old[KeyRemainingEstimate]; ok {
+		state.RemainingEstimate = toInt(value)
+	}
+
+	if value, ok := old[KeyParent]; ok {
+		state.ParentID = toString(value)
+	}
+}
+
+// the values come back from the jsonb column, numbers as float64
+func toString(value any) *string {
+	switch v := value.(type) {
+	case string:
+		if v != "" {
+			return &v
+		}
+	case *string:
+		if v != nil && *v != "" {
+			return v
+		}
+	}
+	return nil
+}
+
+func toInt(value any) *int {
+	var result int
+	switch v := value.(type) {
+	case float64:
+		result = int(v)
+	case int:
+		result = v
+	case *int:
+		if v == nil {
+			return nil
+		}
+		result = *v
+	default:
+		return nil
+	}
+	return &result
+}
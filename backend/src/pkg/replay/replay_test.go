@@ -0,0 +1,222 @@
+package replay
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestTimelineAt(t *testing.T) {
+	day := func(d int) time.Time {
+		return time.Date(2026, 10, d, 12, 0, 0, 0, time.UTC)
+	}
+
+	current := []State{
+		{IssueID: "a", CreatedAt: day(1), Status: "done", SprintID: ptr("s2"), OriginalEstimate: ptr(120), RemainingEstimate: ptr(0)},
+		{IssueID: "b", CreatedAt: day(5), Status: "todo"},
+		{IssueID: "c", CreatedAt: day(1), Status: "on_progress", SprintID: ptr("s1")},
+	}
+
+	// given unordered on purpose, the timeline sort them per issue
+	changes := []Change{
+		{IssueID: "a", At: day(4), Old: map[string]any{KeyStatus: "on_progress", KeyRemainingEstimate: float64(60)}},
+		{IssueID: "a", At: day(2), Old: map[string]any{KeyStatus: "todo", KeySprint: nil, KeyOriginalEstimate: nil}},
+		{IssueID: "a", At: day(3), Old: map[string]any{KeySprint: "s1", KeyRemainingEstimate: float64(120)}},
+		{IssueID: "b", At: day(6), Old: map[string]any{KeyStatus: ""}},
+		{IssueID: "c", At: day(3), Old: map[string]any{"title": "old title"}},
+		// a deleted issue take its activities along, the leftover ones never replayed
+		{IssueID: "d", At: day(3), Old: map[string]any{KeyStatus: "todo"}},
+	}
+
+	timeline := New(current, changes)
+
+	tests := []struct {
+		name string
+		at   time.Time
+		want []State
+	}{
+		{
+			name: "before any issue",
+			at:   day(1).Add(-time.Hour),
+			want: []State{},
+		},
+		{
+			name: "every change reverted",
+			at:   day(1),
+			want: []State{
+				{IssueID: "a", CreatedAt: day(1), Status: "todo", RemainingEstimate: ptr(120)},
+				{IssueID: "c", CreatedAt: day(1), Status: "on_progress", SprintID: ptr("s1")},
+			},
+		},
+		{
+			name: "change at the exact time kept",
+			at:   day(2),
+			want: []State{
+				{IssueID: "a", CreatedAt: day(1), Status: "on_progress", SprintID: ptr("s1"), OriginalEstimate: ptr(120), RemainingEstimate: ptr(120)},
+				{IssueID: "c", CreatedAt: day(1), Status: "on_progress", SprintID: ptr("s1")},
+			},
+		},
+		{
+			name: "in between",
+			at:   day(3).Add(time.Hour),
+			want: []State{
+				{IssueID: "a", CreatedAt: day(1), Status: "on_progress", SprintID: ptr("s2"), OriginalEstimate: ptr(120), RemainingEstimate: ptr(60)},
+				{IssueID: "c", CreatedAt: day(1), Status: "on_progress", SprintID: ptr("s1")},
+			},
+		},
+		{
+			name: "empty previous status ignored",
+			at:   day(5),
+			want: []State{
+				{IssueID: "a", CreatedAt: day(1), Status: "done", SprintID: ptr("s2"), OriginalEstimate: ptr(120), RemainingEstimate: ptr(0)},
+				{IssueID: "b", CreatedAt: day(5), Status: "todo"},
+				{IssueID: "c", CreatedAt: day(1), Status: "on_progress", SprintID: ptr("s1")},
+			},
+		},
+		{
+			name: "current",
+			at:   day(30),
+			want: current,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := timeline.At(tt.at); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("At(%s)\n got %+v\nwant %+v", tt.at, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRollup(t *testing.T) {
+	day := func(d int) time.Time {
+		return time.Date(2026, 10, d, 12, 0, 0, 0, time.UTC)
+	}
+
+	current := []State{
+		{IssueID: "p", CreatedAt: day(1), Status: "on_progress"},
+		{IssueID: "s1", ParentID: ptr("p"), CreatedAt: day(1), Status: "done", OriginalEstimate: ptr(60), RemainingEstimate: ptr(0)},
+		{IssueID: "s2", ParentID: ptr("p"), CreatedAt: day(2), Status: "todo", OriginalEstimate: ptr(30)},
+		{IssueID: "q", CreatedAt: day(1), Status: "todo", OriginalEstimate: ptr(10), RemainingEstimate: ptr(5)},
+		{IssueID: "s3", ParentID: ptr("q"), CreatedAt: day(1), Status: "todo", RemainingEstimate: ptr(20)},
+		// the subtask of a deleted parent
+		{IssueID: "s4", ParentID: ptr("gone"), CreatedAt: day(1), Status: "todo", OriginalEstimate: ptr(90)},
+	}
+
+	changes := []Change{
+		{IssueID: "s1", At: day(3), Old: map[string]any{KeyStatus: "on_progress", KeyRemainingEstimate: float64(60)}},
+		{IssueID: "s3", At: day(2), Old: map[string]any{KeyParent: "p"}},
+	}
+
+	timeline := New(current, changes)
+	done := func(state State) bool {
+		return state.Status == "done"
+	}
+
+	tests := []struct {
+		name string
+		at   time.Time
+		want []State
+	}{
+		{
+			name: "subtask under its former parent",
+			at:   day(1),
+			want: []State{
+				{IssueID: "p", CreatedAt: day(1), Status: "on_progress", OriginalEstimate: ptr(80), RemainingEstimate: ptr(80)},
+				{IssueID: "q", CreatedAt: day(1), Status: "todo", OriginalEstimate: ptr(10), RemainingEstimate: ptr(5)},
+			},
+		},
+		{
+			name: "subtask added later",
+			at:   day(2),
+			want: []State{
+				{IssueID: "p", CreatedAt: day(1), Status: "on_progress", OriginalEstimate: ptr(90), RemainingEstimate: ptr(90)},
+				{IssueID: "q", CreatedAt: day(1), Status: "todo", OriginalEstimate: ptr(30), RemainingEstimate: ptr(25)},
+			},
+		},
+		{
+			name: "done subtask leave the remaining work",
+			at:   day(3),
+			want: []State{
+				{IssueID: "p", CreatedAt: day(1), Status: "on_progress", OriginalEstimate: ptr(90), RemainingEstimate: ptr(30)},
+				{IssueID: "q", CreatedAt: day(1), Status: "todo", OriginalEstimate: ptr(30), RemainingEstimate: ptr(25)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Rollup(timeline.At(tt.at), done); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Rollup(At(%s))\n got %+v\nwant %+v", tt.at, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRevertValues(t *testing.T) {
+	tests := []struct {
+		name  string
+		start State
+		old   map[string]any
+		want  State
+	}{
+		{
+			name:  "string pointer sprint",
+			start: State{SprintID: ptr("s2")},
+			old:   map[string]any{KeySprint: ptr("s1")},
+			want:  State{SprintID: ptr("s1")},
+		},
+		{
+			name:  "empty sprint is the backlog",
+			start: State{SprintID: ptr("s2")},
+			old:   map[string]any{KeySprint: ""},
+			want:  State{},
+		},
+		{
+			name:  "int estimates",
+			start: State{OriginalEstimate: ptr(10), RemainingEstimate: ptr(5)},
+			old:   map[string]any{KeyOriginalEstimate: 30, KeyRemainingEstimate: ptr(15)},
+			want:  State{OriginalEstimate: ptr(30), RemainingEstimate: ptr(15)},
+		},
+		{
+			name:  "unknown estimate type cleared",
+			start: State{OriginalEstimate: ptr(10)},
+			old:   map[string]any{KeyOriginalEstimate: "30"},
+			want:  State{},
+		},
+		{
+			name:  "detached subtask",
+			start: State{},
+			old:   map[string]any{KeyParent: "p"},
+			want:  State{ParentID: ptr("p")},
+		},
+		{
+			name:  "top-level before the move",
+			start: State{ParentID: ptr("p")},
+			old:   map[string]any{KeyParent: nil},
+			want:  State{},
+		},
+		{
+			name:  "non string status kept",
+			start: State{Status: "done"},
+			old:   map[string]any{KeyStatus: 1},
+			want:  State{Status: "done"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := tt.start
+			revert(&state, tt.old)
+
+			if !reflect.DeepEqual(state, tt.want) {
+				t.Errorf("revert(%v) = %+v, want %+v", tt.old, state, tt.want)
+			}
+		})
+	}
+}
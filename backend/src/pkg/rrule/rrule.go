@@ -0,0 +1,252 @@
+package rrule
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+)
+
+// how far ahead `Next` look for an occurrence, e.g. BYMONTHDAY=31 skipping the shorter months
+const maxPeriods = 400
+
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// Rule a subset of the RFC 5545 RRULE, the occurrences are whole days counted from a start date:
+//
+//	FREQ=DAILY;INTERVAL=2
+//	FREQ=WEEKLY;BYDAY=MO,TH;UNTIL=20261231
+//	FREQ=MONTHLY;BYMONTHDAY=1,-1
+type Rule struct {
+	Freq       Frequency
+	Interval   int
+	ByDay      []time.Weekday // weekly only, the start weekday when empty
+	ByMonthDay []int          // monthly only, negative counted from the month end, the start day when empty
+	Until      *time.Time     // inclusive
+}
+
+func Parse(value string) (*Rule, error) {
+	rule := &Rule{Interval: 1}
+	value = strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(value)), "RRULE:")
+
+	for _, part := range strings.Split(value, ";") {
+		if part == "" {
+			continue
+		}
+
+		key, val, ok := strings.Cut(part, "=")
+		if !ok || val == "" {
+			return nil, fmt.Errorf("invalid rule: %q expected KEY=VALUE", part)
+		}
+
+		switch key {
+		case "FREQ":
+			rule.Freq = Frequency(val)
+			if rule.Freq != Daily && rule.Freq != Weekly && rule.Freq != Monthly {
+				return nil, fmt.Errorf("invalid rule: FREQ must be DAILY, WEEKLY or MONTHLY")
+			}
+
+		case "INTERVAL":
+			interval, err := strconv.Atoi(val)
+			if err != nil || interval < 1 || interval > 365 {
+				return nil, fmt.Errorf("invalid rule: INTERVAL must be between 1 and 365")
+			}
+			rule.Interval = interval
+
+		case "BYDAY":
+			for _, day := range strings.Split(val, ",") {
+				weekday, ok := weekdays[day]
+				if !ok {
+					return nil, fmt.Errorf("invalid rule: unknown BYDAY %q", day)
+				}
+
+				if !slices.Contains(rule.ByDay, weekday) {
+					rule.ByDay = append(rule.ByDay, weekday)
+				}
+			}
+
+		case "BYMONTHDAY":
+			for _, day := range strings.Split(val, ",") {
+				monthDay, err := strconv.Atoi(day)
+				if err != nil || monthDay == 0 || monthDay < -31 || monthDay > 31 {
+					return nil, fmt.Errorf("invalid rule: BYMONTHDAY %q must be 1..31 or -31..-1", day)
+				}
+
+				if !slices.Contains(rule.ByMonthDay, monthDay) {
+					rule.ByMonthDay = append(rule.ByMonthDay, monthDay)
+				}
+			}
+
+		case "UNTIL":
+			until, err := parseUntil(val)
+			if err != nil {
+				return nil, err
+			}
+			rule.Until = &until
+
+		default:
+			return nil, fmt.Errorf("invalid rule: %s is not supported", key)
+		}
+	}
+
+	if rule.Freq == "" {
+		return nil, fmt.Errorf("invalid rule: FREQ required")
+	}
+
+	if len(rule.ByDay) > 0 && rule.Freq != Weekly {
+		return nil, fmt.Errorf("invalid rule: BYDAY only supported by FREQ=WEEKLY")
+	}
+
+	if len(rule.ByMonthDay) > 0 && rule.Freq != Monthly {
+		return nil, fmt.Errorf("invalid rule: BYMONTHDAY only supported by FREQ=MONTHLY")
+	}
+
+	return rule, nil
+}
+
+func parseUntil(value string) (time.Time, error) {
+	for _, layout := range []string{"20060102", "20060102T150405Z", "2006-01-02"} {
+		if until, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return day(until), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid rule: UNTIL %q expected YYYYMMDD", value)
+}
+
+// String the normalized rule, stable for the same occurrences
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+
+	if len(r.ByDay) > 0 {
+		days := make([]string, 0, len(r.ByDay))
+		for _, name := range []string{"MO", "TU", "WE", "TH", "FR", "SA", "SU"} {
+			if slices.Contains(r.ByDay, weekdays[name]) {
+				days = append(days, name)
+			}
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+
+	if len(r.ByMonthDay) > 0 {
+		days := make([]string, len(r.ByMonthDay))
+		for i, d := range r.ByMonthDay {
+			days[i] = strconv.Itoa(d)
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.Format("20060102"))
+	}
+
+	return strings.Join(parts, ";")
+}
+
+// Next the first occurrence strictly after the `after` day, counted from the `start` day.
+// false once the rule ended
+func (r *Rule) Next(start, after time.Time) (time.Time, bool) {
+	start = day(start)
+	after = day(after.In(start.Location()))
+
+	// skip the periods entirely before `after`
+	first := 0
+	if after.After(start) {
+		switch r.Freq {
+		case Daily:
+			first = int(after.Sub(start).Hours()/24) / r.Interval
+		case Weekly:
+			first = int(after.Sub(weekStart(start)).Hours()/24/7) / r.Interval
+		case Monthly:
+			first = ((after.Year()-start.Year())*12 + int(after.Month()-start.Month())) / r.Interval
+		}
+	}
+
+	for period := first; period < first+maxPeriods; period++ {
+		for _, occurrence := range r.period(start, period) {
+			if occurrence.Before(start) || !occurrence.After(after) {
+				continue
+			}
+
+			if r.Until != nil && occurrence.After(*r.Until) {
+				return time.Time{}, false
+			}
+
+			return occurrence, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// period the sorted occurrences of the nth period since the start
+func (r *Rule) period(start time.Time, n int) []time.Time {
+	switch r.Freq {
+	case Weekly:
+		base := weekStart(start).AddDate(0, 0, 7*n*r.Interval)
+		days := r.ByDay
+		if len(days) == 0 {
+			days = []time.Weekday{start.Weekday()}
+		}
+
+		occurrences := make([]time.Time, 0, len(days))
+		for _, weekday := range days {
+			occurrences = append(occurrences, base.AddDate(0, 0, (int(weekday)+6)%7))
+		}
+		slices.SortFunc(occurrences, func(a, b time.Time) int { return a.Compare(b) })
+		return occurrences
+
+	case Monthly:
+		month := time.Date(start.Year(), start.Month()+time.Month(n*r.Interval), 1, 0, 0, 0, 0, start.Location())
+		length := month.AddDate(0, 1, -1).Day()
+		days := r.ByMonthDay
+		if len(days) == 0 {
+			days = []int{start.Day()}
+		}
+
+		occurrences := make([]time.Time, 0, len(days))
+		for _, d := range days {
+			if d < 0 {
+				d = length + d + 1
+			}
+
+			// the months without the day are skipped, as RFC 5545 does
+			if d >= 1 && d <= length {
+				occurrences = append(occurrences, month.AddDate(0, 0, d-1))
+			}
+		}
+		slices.SortFunc(occurrences, func(a, b time.Time) int { return a.Compare(b) })
+		return occurrences
+
+	default:
+		return []time.Time{start.AddDate(0, 0, n*r.Interval)}
+	}
+}
+
+func day(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// weekStart the monday of the week
+func weekStart(t time.Time) time.Time {
+	return t.AddDate(0, 0, -((int(t.Weekday()) + 6) % 7))
+}
@@ -0,0 +1,117 @@
+package rrule
+
+import (
+	"testing"
+	"time"
+)
+
+func date(year, month, day int) time.Time {
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.Local)
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string // normalized, empty when invalid
+	}{
+		{value: "FREQ=DAILY", want: "FREQ=DAILY"},
+		{value: " rrule:freq=daily;interval=1 ", want: "FREQ=DAILY"},
+		{value: "FREQ=WEEKLY;BYDAY=TH,MO,MO", want: "FREQ=WEEKLY;BYDAY=MO,TH"},
+		{
+			value: "FREQ=MONTHLY;INTERVAL=2;BYMONTHDAY=1,-1;UNTIL=2026-12-31",
+			want:  "FREQ=MONTHLY;INTERVAL=2;BYMONTHDAY=1,-1;UNTIL=20261231",
+		},
+		{value: "FREQ=DAILY;UNTIL=20261231T235959Z;", want: "FREQ=DAILY;UNTIL=20261231"},
+		{value: ""},
+		{value: "INTERVAL=2"},
+		{value: "FREQ"},
+		{value: "FREQ=YEARLY"},
+		{value: "FREQ=DAILY;INTERVAL=0"},
+		{value: "FREQ=DAILY;INTERVAL=366"},
+		{value: "FREQ=DAILY;BYDAY=MO"},
+		{value: "FREQ=WEEKLY;BYDAY=XX"},
+		{value: "FREQ=WEEKLY;BYMONTHDAY=1"},
+		{value: "FREQ=MONTHLY;BYMONTHDAY=0"},
+		{value: "FREQ=MONTHLY;BYMONTHDAY=-32"},
+		{value: "FREQ=DAILY;COUNT=3"},
+		{value: "FREQ=DAILY;UNTIL=tomorrow"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			rule, err := Parse(tt.value)
+			if tt.want == "" {
+				if err == nil {
+					t.Errorf("Parse(%q) = %s, want an error", tt.value, rule)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Parse(%q) error: %v", tt.value, err)
+			}
+
+			if got := rule.String(); got != tt.want {
+				t.Errorf("Parse(%q) = %s, want %s", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+// 2026-01-01 is a thursday
+func TestNext(t *testing.T) {
+	tests := []struct {
+		name  string
+		rule  string
+		start time.Time
+		after time.Time
+		want  time.Time // zero once the rule ended
+	}{
+		{name: "daily", rule: "FREQ=DAILY", start: date(2026, 1, 1), after: date(2026, 1, 1), want: date(2026, 1, 2)},
+		{name: "daily before start", rule: "FREQ=DAILY", start: date(2026, 1, 1), after: date(2025, 12, 31), want: date(2026, 1, 1)},
+		{name: "daily interval", rule: "FREQ=DAILY;INTERVAL=3", start: date(2026, 1, 1), after: date(2026, 1, 5), want: date(2026, 1, 7)},
+		{
+			name:  "time of day ignored",
+			rule:  "FREQ=DAILY",
+			start: date(2026, 1, 1).Add(9 * time.Hour),
+			after: date(2026, 1, 1).Add(23 * time.Hour),
+			want:  date(2026, 1, 2),
+		},
+		{name: "weekly start weekday", rule: "FREQ=WEEKLY", start: date(2026, 1, 1), after: date(2026, 1, 1), want: date(2026, 1, 8)},
+		{name: "weekly by day", rule: "FREQ=WEEKLY;BYDAY=MO,TH", start: date(2026, 1, 1), after: date(2026, 1, 1), want: date(2026, 1, 5)},
+		{name: "weekly same week", rule: "FREQ=WEEKLY;BYDAY=TH,SU", start: date(2026, 1, 1), after: date(2026, 1, 1), want: date(2026, 1, 4)},
+		{
+			name:  "weekly interval skip the day before start",
+			rule:  "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO",
+			start: date(2026, 1, 1),
+			after: date(2026, 1, 1),
+			want:  date(2026, 1, 12),
+		},
+		{name: "weekly far after", rule: "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO", start: date(2026, 1, 1), after: date(2026, 3, 10), want: date(2026, 3, 23)},
+		{name: "monthly start day", rule: "FREQ=MONTHLY", start: date(2026, 1, 15), after: date(2026, 1, 15), want: date(2026, 2, 15)},
+		{name: "monthly missing day skipped", rule: "FREQ=MONTHLY", start: date(2026, 1, 31), after: date(2026, 1, 31), want: date(2026, 3, 31)},
+		{name: "monthly last day", rule: "FREQ=MONTHLY;BYMONTHDAY=-1", start: date(2026, 1, 15), after: date(2026, 2, 1), want: date(2026, 2, 28)},
+		{name: "monthly several days", rule: "FREQ=MONTHLY;BYMONTHDAY=15,1", start: date(2026, 1, 1), after: date(2026, 1, 10), want: date(2026, 1, 15)},
+		{name: "leap day", rule: "FREQ=MONTHLY;INTERVAL=12", start: date(2024, 2, 29), after: date(2024, 2, 29), want: date(2028, 2, 29)},
+		{name: "until inclusive", rule: "FREQ=DAILY;UNTIL=20260103", start: date(2026, 1, 1), after: date(2026, 1, 2), want: date(2026, 1, 3)},
+		{name: "until passed", rule: "FREQ=DAILY;INTERVAL=7;UNTIL=20260110", start: date(2026, 1, 1), after: date(2026, 1, 8)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := Parse(tt.rule)
+			if err != nil {
+				t.Fatalf("Parse(%q) error: %v", tt.rule, err)
+			}
+
+			got, ok := rule.Next(tt.start, tt.after)
+			if ok != !tt.want.IsZero() {
+				t.Fatalf("Next = %s, %v, want %s", got, ok, tt.want)
+			}
+
+			if ok && !got.Equal(tt.want) {
+				t.Errorf("Next = %s, want %s", got.Format("2006-01-02"), tt.want.Format("2006-01-02"))
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+	c "webservices/src/pkg/common"
+)
+
+// Storage the blob storage backend of the uploaded files
+type Storage interface {
+	// Put store the blob under `key` and return its public url
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) (string, error)
+	// Delete remove the blob, a missing blob is not an error
+	Delete(ctx context.Context, key string) error
+	// Driver name saved along the stored file
+	Driver() string
+}
+
+type Config struct {
+	Driver string // local | s3
+
+	LocalPath string
+	LocalUrl  string
+
+	S3Endpoint  string
+	S3Region    string
+	S3Bucket    string
+	S3AccessKey string
+	S3SecretKey string
+	S3PathStyle bool
+	S3PublicUrl string
+}
+
+func ConfigFromEnv() Config {
+	pathStyle, err := strconv.ParseBool(c.Env("S3_PATH_STYLE", "true"))
+	if err != nil {
+		pathStyle = true
+	}
+
+	return Config{
+		Driver:      c.Env("STORAGE_DRIVER", "local"),
+		LocalPath:   c.Env("STORAGE_LOCAL_PATH", "storage/public/attachments"),
+		LocalUrl:    c.Env("STORAGE_LOCAL_URL", "/public/attachments"),
+		S3Endpoint:  c.Env("S3_ENDPOINT"),
+		S3Region:    c.Env("S3_REGION", "us-east-1"),
+		S3Bucket:    c.Env("S3_BUCKET"),
+		S3AccessKey: c.Env("S3_ACCESS_KEY"),
+		S3SecretKey: c.Env("S3_SECRET_KEY"),
+		S3PathStyle: pathStyle,
+		S3PublicUrl: c.Env("S3_PUBLIC_URL"),
+	}
+}
+
+func New(config Config) (Storage, error) {
+	switch config.Driver {
+	case "", "local":
+		return NewLocal(config.LocalPath, config.LocalUrl), nil
+	case "s3":
+		return NewS3(config)
+	default:
+		return nil, fmt.Errorf("unknown storage driver: %s", config.Driver)
+	}
+}
+
+// cleanKey reject the key escaping the storage root
+func cleanKey(key string) (string, error) {
+	key = path.Clean("/" + strings.ReplaceAll(key, "\\", "/"))[1:]
+	if key == "" || key == "." {
+		return "", fmt.Errorf("invalid storage key")
+	}
+	return key, nil
+}
@@ -0,0 +1,44 @@
+package storage
+
+import "testing"
+
+func TestCleanKey(t *testing.T) {
+	tests := []struct {
+		key  string
+		want string // empty when rejected
+	}{
+		{key: "attachments/a.png", want: "attachments/a.png"},
+		{key: "/attachments//a.png", want: "attachments/a.png"},
+		{key: "attachments/./b/../a.png", want: "attachments/a.png"},
+		{key: "../../etc/passwd", want: "etc/passwd"},
+		{key: "attachments/../../../etc/passwd", want: "etc/passwd"},
+		{key: `..\..\windows\win.ini`, want: "windows/win.ini"},
+		{key: "a/b/", want: "a/b"},
+		{key: ""},
+		{key: "/"},
+		{key: "."},
+		{key: ".."},
+		{key: "a/.."},
+		{key: `\`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			got, err := cleanKey(tt.key)
+			if tt.want == "" {
+				if err == nil {
+					t.Errorf("cleanKey(%q) = %q, want an error", tt.key, got)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("cleanKey(%q) error: %v", tt.key, err)
+			}
+
+			if got != tt.want {
+				t.Errorf("cleanKey(%q) = %q, want %q", tt.key, got, tt.want)
+			}
+		})
+	}
+}
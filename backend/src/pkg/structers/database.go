@@ -19,6 +19,7 @@ type DatabaseRegistry struct {
 	Extensions []string
 	Tables     []string
 	Factories  []func(*gorm.DB) error
+	Migrations []func(*gorm.DB) error // data migrations, run after the schema migrated
 }
 
 func (r *DatabaseRegistry) GetEnums() []Enum {
@@ -41,6 +42,10 @@ func (r *DatabaseRegistry) GetFactories() []func(*gorm.DB) error {
 	return r.Factories
 }
 
+func (r *DatabaseRegistry) GetMigrations() []func(*gorm.DB) error {
+	return r.Migrations
+}
+
 func (r *DatabaseRegistry) Migrate(db *gorm.DB, fresh bool) error {
 	log.Info("Starting database migration...")
 
@@ -81,6 +86,13 @@ func (r *DatabaseRegistry) Migrate(db *gorm.DB, fresh bool) error {
 		return err
 	}
 
+	for _, migrate := range r.Migrations {
+		if err := migrate(tx); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		return err
 	}
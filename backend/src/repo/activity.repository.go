@@ -2,7 +2,9 @@ package repo
 
 import (
 	"fmt"
+	"time"
 	"webservices/src/model"
+	"webservices/src/pkg/pagination"
 
 	"gorm.io/gorm"
 )
@@ -28,7 +30,7 @@ func (r *ActivityRepository) GetByIssueID(issueID string) ([]model.RecentActivit
 	return activities, nil
 }
 
-func (r *ActivityRepository) GetByIssueIncludeChilds(issueID string, childIDs []string) ([]model.RecentActivity, error) {
+func (r *ActivityRepository) GetByIssueIncludeChilds(issueID string, childIDs []string, params pagination.Params) (*pagination.Page[model.RecentActivity], error) {
 	var activities []model.RecentActivity
 
 	ids := make([]string, 0, len(childIDs)+1)
@@ -38,13 +40,17 @@ func (r *ActivityRepository) GetByIssueIncludeChilds(issueID string, childIDs []
 	}
 
 	if err := r.db.Joins("User").
-		Order("created_at DESC").
-		Find(&activities, "issue_id IN (?)", ids).
+		Scopes(params.Scope("recent_activities", true)).
+		Find(&activities, "recent_activities.issue_id IN (?)", ids).
 		Error; err != nil {
 		return nil, fmt.Errorf("failed to fetch activities: %w", err)
 	}
 
-	return activities, nil
+	page := pagination.NewPage(activities, params, func(a model.RecentActivity) (time.Time, string) {
+		return a.CreatedAt, a.ID
+	})
+
+	return &page, nil
 }
 
 func (r *ActivityRepository) Create(activity *model.RecentActivity) error {
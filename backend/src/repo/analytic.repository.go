@@ -0,0 +1,194 @@
+package repo
+
+import (
+	"fmt"
+	"time"
+	"webservices/src/model"
+	"webservices/src/types"
+	"webservices/src/types/schemas"
+
+	"gorm.io/gorm"
+)
+
+// AnalyticRepository the issue aggregates, every range is half-open [from, to)
+type AnalyticRepository struct {
+	*baseRepository
+}
+
+func NewAnalyticRepository(db *gorm.DB) *AnalyticRepository {
+	return &AnalyticRepository{
+		baseRepository: newBaseRepository(db),
+	}
+}
+
+// Fingerprint change whenever an issue of the project created, updated or deleted
+func (r *AnalyticRepository) Fingerprint(projectID string) (string, error) {
+	var result struct {
+		Count     int64
+		UpdatedAt *time.Time
+	}
+
+	if err := r.db.Model(&model.Issue{}).
+		Select("COUNT(*) AS count, MAX(updated_at) AS updated_at").
+		Where("project_id = ?", projectID).
+		Scan(&result).
+		Error; err != nil {
+		return "", fmt.Errorf("failed to fetch issue fingerprint: %w", err)
+	}
+
+	if result.UpdatedAt == nil {
+		return fmt.Sprintf("%d", result.Count), nil
+	}
+
+	return fmt.Sprintf("%d:%d", result.Count, result.UpdatedAt.UnixNano()), nil
+}
+
+// CountBy group the issues created within the range by `column`, never pass the user input as the column
+func (r *AnalyticRepository) CountBy(projectID, column string, from, to time.Time) ([]schemas.AnalyticCount, error) {
+	counts := make([]schemas.AnalyticCount, 0)
+
+	if err := r.db.Model(&model.Issue{}).
+		Select(fmt.Sprintf("%s::text AS key, COUNT(*) AS count", column)).
+		Where("project_id = ? AND created_at >= ? AND created_at < ?", projectID, from, to).
+		Group(column).
+		Order("count DESC").
+		Scan(&counts).
+		Error; err != nil {
+		return nil, fmt.Errorf("failed to count issues by %s: %w", column, err)
+	}
+
+	return counts, nil
+}
+
+// CountByAssignee the unassigned issues grouped under an empty key
+func (r *AnalyticRepository) CountByAssignee(projectID string, from, to time.Time) ([]schemas.AnalyticCount, error) {
+	counts := make([]schemas.AnalyticCount, 0)
+
+	if err := r.db.Table("issues").
+		Select("COALESCE(issues.assignee_id::text, '') AS key, users.name AS label, COUNT(*) AS count").
+		Joins("LEFT JOIN users ON users.id = issues.assignee_id").
+		Where("issues.project_id = ? AND issues.created_at >= ? AND issues.created_at < ?", projectID, from, to).
+		Group("issues.assignee_id, users.name").
+		Order("count DESC").
+		Scan(&counts).
+		Error; err != nil {
+		return nil, fmt.Errorf("failed to count issues by assignee: %w", err)
+	}
+
+	return counts, nil
+}
+
+// Daily the created & resolved issues per day, the days without any included
+func (r *AnalyticRepository) Daily(projectID string, from, to time.Time) ([]schemas.AnalyticDaily, error) {
+	daily := make([]schemas.AnalyticDaily, 0)
+
+	if err := r.db.Raw(`
+		SELECT days.day::date AS date,
+			COALESCE(created.count, 0) AS created,
+			COALESCE(resolved.count, 0) AS resolved
+		FROM generate_series(?::date, ?::date - INTERVAL '1 day', INTERVAL '1 day') AS days(day)
+		LEFT JOIN (
+			SELECT created_at::date AS day, COUNT(*) AS count
+			FROM issues
+			WHERE project_id = ? AND created_at >= ? AND created_at < ?
+			GROUP BY 1
+		) created ON created.day = days.day
+		LEFT JOIN (
+			SELECT done_date::date AS day, COUNT(*) AS count
+			FROM issues
+			WHERE project_id = ? AND status_category = ? AND done_date >= ? AND done_date < ?
+			GROUP BY 1
+		) resolved ON resolved.day = days.day
+		ORDER BY days.day ASC`,
+		from, to,
+		projectID, from, to,
+		projectID, types.CategoryDone, from, to,
+	).Scan(&daily).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch daily issues: %w", err)
+	}
+
+	return daily, nil
+}
+
+// Durations the average cycle time (start → done) and lead time (created → done) in hours
+// of the issues resolved within the range
+func (r *AnalyticRepository) Durations(projectID string, from, to time.Time) (resolved int64, cycle, lead *float64, err error) {
+	var result struct {
+		Resolved  int64
+		CycleTime *float64
+		LeadTime  *float64
+	}
+
+	if err := r.db.Model(&model.Issue{}).
+		Select(`COUNT(*) AS resolved,
+			AVG(EXTRACT(EPOCH FROM (done_date - start_date)) / 3600)
+				FILTER (WHERE start_date IS NOT NULL AND done_date >= start_date) AS cycle_time,
+			AVG(EXTRACT(EPOCH FROM (done_date - created_at)) / 3600) AS lead_time`).
+		Where("project_id = ? AND status_category = ? AND done_date >= ? AND done_date < ?",
+			projectID, types.CategoryDone, from, to).
+		Scan(&result).
+		Error; err != nil {
+		return 0, nil, nil, fmt.Errorf("failed to fetch issue durations: %w", err)
+	}
+
+	return result.Resolved, result.CycleTime, result.LeadTime, nil
+}
+
+// CountOverdue the open issues due within the range which already passed `now`
+func (r *AnalyticRepository) CountOverdue(projectID string, from, to, now time.Time) (int64, error) {
+	if now.Before(to) {
+		to = now
+	}
+
+	var count int64
+	if err := r.db.Model(&model.Issue{}).
+		Where("project_id = ? AND due_date >= ? AND due_date < ?", projectID, from, to).
+		Where("status != ? AND status_category != ?", types.IssueStatusDraft, types.CategoryDone).
+		Count(&count).
+		Error; err != nil {
+		return 0, fmt.Errorf("failed to count overdue issues: %w", err)
+	}
+
+	return count, nil
+}
+
+// GetReplayIssues the current tracked fields of the project issues along their parent, the subtasks included.
+// the deleted issues are gone with their activities, the replay can't count them on the past days
+func (r *AnalyticRepository) GetReplayIssues(projectID string) ([]model.Issue, error) {
+	var issues []model.Issue
+	if err := r.db.
+		Select("id, parents, created_at, status, sprint_id, original_estimate, remaining_estimate").
+		Where("project_id = ?", projectID).
+		Find(&issues).
+		Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch issues: %w", err)
+	}
+
+	return issues, nil
+}
+
+// GetChangesSince the activities able to change the status, sprint, estimates or parent of the issues after `since`, oldest first
+func (r *AnalyticRepository) GetChangesSince(projectID string, since time.Time) ([]model.RecentActivity, error) {
+	var activities []model.RecentActivity
+	if err := r.db.
+		Select("id, issue_id, activity_type, old_values, new_values, created_at").
+		Where("project_id = ? AND created_at > ?", projectID, since).
+		Where("activity_type IN ?", []types.ActivityType{
+			types.IssueUpdate,
+			types.StatusChange,
+			types.IssueApprove,
+			types.IssueReject,
+			types.WorklogCreate,
+			types.WorklogUpdate,
+			types.WorklogDelete,
+			types.SprintEnd,
+			types.IssueMove,
+		}).
+		Order("created_at ASC").
+		Find(&activities).
+		Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch activities: %w", err)
+	}
+
+	return activities, nil
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"time"
 	"webservices/src/model"
+	"webservices/src/pkg/pagination"
 
 	"gorm.io/gorm"
 )
@@ -18,15 +19,39 @@ func NewCommentRepository(db *gorm.DB) *CommentRepository {
 	}
 }
 
-func (r *CommentRepository) GetByIssueID(issueID string) ([]model.Comment, error) {
+// GetByIssueID the page of the top-level comments, the replies fetched by `GetByThreadIDs`
+func (r *CommentRepository) GetByIssueID(issueID string, params pagination.Params) (*pagination.Page[model.Comment], error) {
 	var comments []model.Comment
 	if err := r.db.Joins("User").
-		Order("created_at DESC").
-		Find(&comments, "issue_id = ?", issueID).
+		Scopes(params.Scope("comments", true)).
+		Find(&comments, "comments.issue_id = ? AND comments.parent_id IS NULL", issueID).
 		Error; err != nil {
 		return nil, fmt.Errorf("failed to fetch comment: %w", err)
 	}
-	return comments, nil
+
+	page := pagination.NewPage(comments, params, func(c model.Comment) (time.Time, string) {
+		return c.CreatedAt, c.ID
+	})
+
+	return &page, nil
+}
+
+// GetByThreadIDs every reply of the threads, oldest first
+func (r *CommentRepository) GetByThreadIDs(threadIDs []string) ([]model.Comment, error) {
+	replies := make([]model.Comment, 0)
+	if len(threadIDs) == 0 {
+		return replies, nil
+	}
+
+	if err := r.db.Joins("User").
+		Where("comments.thread_id IN ?", threadIDs).
+		Order("comments.created_at ASC").
+		Find(&replies).
+		Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch replies: %w", err)
+	}
+
+	return replies, nil
 }
 
 func (r *CommentRepository) GetByID(ID string) (*model.Comment, error) {
@@ -40,17 +65,27 @@ func (r *CommentRepository) GetByID(ID string) (*model.Comment, error) {
 	return &comment, nil
 }
 
-func (r *CommentRepository) GetUserIDs(issueID, userID string) ([]string, error) {
-	var ids []string
+// GetWatchedSince fetch other users comments since `since` on the issues the user watching
+func (r *CommentRepository) GetWatchedSince(projectID, userID string, since time.Time) ([]model.Comment, error) {
+	var comments []model.Comment
 
-	if err := r.db.Model(&model.Comment{}).
-		Where("issue_id = ? AND  user_id != ?", issueID, userID).
-		Distinct("user_id").
-		Pluck("user_id", &ids).Error; err != nil {
-		return nil, fmt.Errorf("failed to fetch user comments: %w", err)
+	watched := r.db.Model(&model.IssueWatcher{}).
+		Select("issue_id").
+		Where("user_id = ? AND watching = ?", userID, true)
+
+	if err := r.db.
+		Joins("User").
+		Joins("Issue").
+		Where(`"Issue".project_id = ? AND comments.user_id != ? AND comments.created_at >= ?`,
+			projectID, userID, since).
+		Where("comments.issue_id IN (?) AND comments.deleted_at IS NULL", watched).
+		Order("comments.created_at ASC").
+		Find(&comments).
+		Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch watched comments: %w", err)
 	}
 
-	return ids, nil
+	return comments, nil
 }
 
 func (r *CommentRepository) Create(comment *model.Comment) error {
@@ -81,3 +116,118 @@ func (r *CommentRepository) Delete(ID string) error {
 func (r *CommentRepository) DeleteTx(tx *gorm.DB, ID string) error {
 	return tx.Delete(&model.Comment{}, "id = ?", ID).Error
 }
+
+// SoftDeleteTx keep the row as the placeholder of the thread, restored by `RestoreTx`
+func (r *CommentRepository) SoftDeleteTx(tx *gorm.DB, comment *model.Comment, userID string) error {
+	now := time.Now()
+	if err := tx.Model(&model.Comment{}).
+		Where("id = ?", comment.ID).
+		Updates(map[string]any{"deleted_at": now, "deleted_by": userID}).
+		Error; err != nil {
+		return fmt.Errorf("failed to delete comment: %w", err)
+	}
+
+	comment.DeletedAt = &now
+	comment.DeletedBy = &userID
+	return nil
+}
+
+func (r *CommentRepository) RestoreTx(tx *gorm.DB, comment *model.Comment) error {
+	if err := tx.Model(&model.Comment{}).
+		Where("id = ?", comment.ID).
+		Updates(map[string]any{"deleted_at": nil, "deleted_by": nil}).
+		Error; err != nil {
+		return fmt.Errorf("failed to restore comment: %w", err)
+	}
+
+	comment.DeletedAt = nil
+	comment.DeletedBy = nil
+	return nil
+}
+
+// PurgeDeleted erase the message & the revisions of the comments deleted before `before`,
+// the rows kept as the placeholders of their thread
+func (r *CommentRepository) PurgeDeleted(before time.Time) (int64, error) {
+	var purged int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		expired := tx.Model(&model.Comment{}).
+			Select("id").
+			Where("deleted_at < ?", before)
+
+		if err := tx.Where("comment_id IN (?)", expired).
+			Delete(&model.CommentRevision{}).
+			Error; err != nil {
+			return fmt.Errorf("failed to purge comment revisions: %w", err)
+		}
+
+		result := tx.Model(&model.Comment{}).
+			Where("deleted_at < ? AND message != ''", before).
+			Update("message", "")
+		if result.Error != nil {
+			return fmt.Errorf("failed to purge comments: %w", result.Error)
+		}
+
+		purged = result.RowsAffected
+		return nil
+	})
+
+	return purged, err
+}
+
+// GetRevisions the replaced messages of the comment, newest first
+func (r *CommentRepository) GetRevisions(commentID string) ([]model.CommentRevision, error) {
+	revisions := make([]model.CommentRevision, 0)
+	if err := r.db.Joins("User").
+		Where("comment_revisions.comment_id = ?", commentID).
+		Order("comment_revisions.created_at DESC").
+		Find(&revisions).
+		Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch comment revisions: %w", err)
+	}
+
+	return revisions, nil
+}
+
+func (r *CommentRepository) CreateRevisionTx(tx *gorm.DB, revision *model.CommentRevision) error {
+	if err := tx.Omit("Comment", "User").Create(revision).Error; err != nil {
+		return fmt.Errorf("failed to create comment revision: %w", err)
+	}
+	return nil
+}
+
+// GetReactions the reactions of the comments, oldest first
+func (r *CommentRepository) GetReactions(commentIDs []string) ([]model.CommentReaction, error) {
+	reactions := make([]model.CommentReaction, 0)
+	if len(commentIDs) == 0 {
+		return reactions, nil
+	}
+
+	if err := r.db.
+		Where("comment_id IN ?", commentIDs).
+		Order("created_at ASC").
+		Find(&reactions).
+		Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch reactions: %w", err)
+	}
+
+	return reactions, nil
+}
+
+// ToggleReactionTx remove the user reaction when it exists or else add it, returning true once added
+func (r *CommentRepository) ToggleReactionTx(tx *gorm.DB, reaction *model.CommentReaction) (bool, error) {
+	result := tx.Delete(&model.CommentReaction{}, "comment_id = ? AND user_id = ? AND emoji = ?",
+		reaction.CommentID, reaction.UserID, reaction.Emoji)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to remove reaction: %w", result.Error)
+	}
+
+	if result.RowsAffected > 0 {
+		return false, nil
+	}
+
+	if err := tx.Omit("Comment", "User").Create(reaction).Error; err != nil {
+		return false, fmt.Errorf("failed to add reaction: %w", err)
+	}
+
+	return true, nil
+}
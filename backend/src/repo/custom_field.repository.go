@@ -0,0 +1,103 @@
+package repo
+
+import (
+	"fmt"
+	"webservices/src/model"
+	"webservices/src/types"
+
+	"gorm.io/gorm"
+)
+
+type CustomFieldRepository struct {
+	*baseRepository
+}
+
+func NewCustomFieldRepository(db *gorm.DB) *CustomFieldRepository {
+	return &CustomFieldRepository{
+		baseRepository: newBaseRepository(db),
+	}
+}
+
+func (r *CustomFieldRepository) GetByID(ID string) (*model.CustomField, error) {
+	var field model.CustomField
+	if err := r.db.First(&field, "id = ?", ID).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch custom field: %w", err)
+	}
+
+	return &field, nil
+}
+
+func (r *CustomFieldRepository) GetByProjectID(projectID string) ([]model.CustomField, error) {
+	fields := make([]model.CustomField, 0)
+	if err := r.db.
+		Where("project_id = ?", projectID).
+		Order("position ASC, created_at ASC").
+		Find(&fields).
+		Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch custom fields: %w", err)
+	}
+
+	return fields, nil
+}
+
+// CountOptionUsage the number of the project issues holding any of the `options` on the select field
+func (r *CustomFieldRepository) CountOptionUsage(field *model.CustomField, options []string) (int64, error) {
+	var count int64
+	if len(options) == 0 {
+		return count, nil
+	}
+
+	query := r.db.Model(&model.Issue{}).
+		Where("project_id = ?", field.ProjectID)
+
+	if field.Type == types.FieldMultiSelect {
+		query = query.Where("EXISTS (SELECT 1 FROM jsonb_array_elements_text(custom_fields->?::text) AS v(value) WHERE v.value IN ?)",
+			field.Key, options)
+	} else {
+		query = query.Where("custom_fields->>?::text IN ?", field.Key, options)
+	}
+
+	if err := query.Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count custom field usage: %w", err)
+	}
+
+	return count, nil
+}
+
+func (r *CustomFieldRepository) CreateTx(tx *gorm.DB, field *model.CustomField) error {
+	if err := tx.Omit("Project").Create(field).Error; err != nil {
+		return fmt.Errorf("failed to create custom field: %w", err)
+	}
+
+	return nil
+}
+
+func (r *CustomFieldRepository) UpdateTx(tx *gorm.DB, field *model.CustomField) error {
+	if err := tx.Model(field).
+		Updates(map[string]any{
+			"name":     field.Name,
+			"options":  field.Options,
+			"required": field.Required,
+			"position": field.Position,
+		}).Error; err != nil {
+		return fmt.Errorf("failed to update custom field: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteTx remove the definition & its values from the project issues
+func (r *CustomFieldRepository) DeleteTx(tx *gorm.DB, field *model.CustomField) error {
+	if err := tx.Model(&model.Issue{}).
+		Where("project_id = ?", field.ProjectID).
+		UpdateColumn("custom_fields", gorm.Expr("custom_fields - ?::text", field.Key)).
+		Error; err != nil {
+		return fmt.Errorf("failed to clear custom field values: %w", err)
+	}
+
+	if err := tx.Delete(&model.CustomField{}, "id = ?", field.ID).Error; err != nil {
+		return fmt.Errorf("failed to delete custom field: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,32 @@
+package repo
+
+import (
+	"fmt"
+	"webservices/src/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type DigestLogRepository struct {
+	*baseRepository
+}
+
+func NewDigestLogRepository(db *gorm.DB) *DigestLogRepository {
+	return &DigestLogRepository{
+		baseRepository: newBaseRepository(db),
+	}
+}
+
+// CreateTx return false when the digest already sent at the date
+func (r *DigestLogRepository) CreateTx(tx *gorm.DB, log *model.DigestLog) (bool, error) {
+	result := tx.
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(log)
+
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to create digest log: %w", result.Error)
+	}
+
+	return result.RowsAffected > 0, nil
+}
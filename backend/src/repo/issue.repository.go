@@ -6,7 +6,9 @@ import (
 	"strings"
 	"time"
 	"webservices/src/model"
+	"webservices/src/pkg/jql"
 	"webservices/src/pkg/logger"
+	"webservices/src/pkg/pagination"
 	"webservices/src/types"
 	"webservices/src/types/schemas"
 
@@ -26,13 +28,31 @@ func NewIssueRepository(db *gorm.DB) *IssueRepository {
 
 func (r *IssueRepository) GetByID(ID string) (*model.Issue, error) {
 	var issue model.Issue
-	if err := r.db.First(&issue, "id = ?", ID).Error; err != nil {
+	if err := r.db.Preload("Labels").First(&issue, "id = ?", ID).Error; err != nil {
 		return nil, fmt.Errorf("failed to fetch issue: %w", err)
 	}
 
 	return &issue, nil
 }
 
+// GetByIDs fetch the issues in any project, the unknown IDs are left out
+func (r *IssueRepository) GetByIDs(IDs []string) ([]model.Issue, error) {
+	issues := make([]model.Issue, 0, len(IDs))
+	if len(IDs) == 0 {
+		return issues, nil
+	}
+
+	if err := r.db.Preload("Labels").
+		Where("id IN ?", IDs).
+		Order("order_index ASC").
+		Find(&issues).
+		Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch issues: %w", err)
+	}
+
+	return issues, nil
+}
+
 func (r *IssueRepository) GetChildIDs(ID string) ([]string, error) {
 	var ids []string
 
@@ -47,11 +67,13 @@ func (r *IssueRepository) GetChildIDs(ID string) ([]string, error) {
 	return ids, nil
 }
 
-// `preload` showing all data include the `issue_child`, `preload` usage for analytic
+// `preload` showing all data include the `issue_child`, `preload` usage for analytic.
+// the whole list ordered by `order_index`, see `GetPageByProjectID` for the paginated one
 func (r *IssueRepository) GetByProjectID(preload bool, projectID string, parentID *string) ([]model.Issue, error) {
 	var issues []model.Issue
 
 	query := r.db.
+		Preload("Labels").
 		Where("project_id = ?", projectID).
 		Order("order_index ASC")
 
@@ -60,11 +82,7 @@ func (r *IssueRepository) GetByProjectID(preload bool, projectID string, parentI
 			return db.Joins("User")
 		})
 	} else {
-		if parentID != nil && *parentID != "" {
-			query = query.Where("parents = ?", parentID)
-		} else {
-			query = query.Where("parents IS NULL OR parents = ''")
-		}
+		query = query.Scopes(siblings(parentID))
 	}
 
 	if err := query.Find(&issues).Error; err != nil {
@@ -74,12 +92,62 @@ func (r *IssueRepository) GetByProjectID(preload bool, projectID string, parentI
 	return issues, nil
 }
 
-func (r *IssueRepository) GetWithFilter(projectID string, filter schemas.FilterIssue) ([]model.Issue, error) {
+// GetPageByProjectID the issues of the parent, or the top level ones, paginated on the drag ordering
+func (r *IssueRepository) GetPageByProjectID(projectID string, parentID *string, params pagination.Params) (*pagination.Page[model.Issue], error) {
 	var issues []model.Issue
 
-	query := r.db.
+	if err := r.db.
+		Preload("Labels").
 		Where("project_id = ?", projectID).
-		Order("updated_at DESC") // make sure orderBy is correct
+		Scopes(siblings(parentID), params.PositionScope("issues")).
+		Find(&issues).
+		Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch issues: %w", err)
+	}
+
+	page := pagination.NewPositionPage(issues, params, func(i model.Issue) (int, string) {
+		return i.Order, i.ID
+	})
+
+	return &page, nil
+}
+
+// GetWithFilter `compiled` is the parsed `filter.Query`, its ordering take precedence over the default one
+func (r *IssueRepository) GetWithFilter(projectID string, filter schemas.FilterIssue, compiled *jql.Compiled) ([]model.Issue, error) {
+	var issues []model.Issue
+
+	query := r.filtered(projectID, filter, compiled).
+		Order("issues.updated_at DESC")
+
+	if err := query.Find(&issues).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch issues: %w", err)
+	}
+
+	return issues, nil
+}
+
+// GetPageWithFilter the paginated `GetWithFilter` on the drag ordering, `compiled` holding no ordering
+func (r *IssueRepository) GetPageWithFilter(projectID string, filter schemas.FilterIssue, compiled *jql.Compiled, params pagination.Params) (*pagination.Page[model.Issue], error) {
+	var issues []model.Issue
+
+	if err := r.filtered(projectID, filter, compiled).
+		Scopes(params.PositionScope("issues")).
+		Find(&issues).
+		Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch issues: %w", err)
+	}
+
+	page := pagination.NewPositionPage(issues, params, func(i model.Issue) (int, string) {
+		return i.Order, i.ID
+	})
+
+	return &page, nil
+}
+
+func (r *IssueRepository) filtered(projectID string, filter schemas.FilterIssue, compiled *jql.Compiled) *gorm.DB {
+	query := r.db.
+		Preload("Labels").
+		Where("issues.project_id = ?", projectID)
 
 	if filter.Search != nil && *filter.Search != "" {
 		searchTerm := "%" + *filter.Search + "%"
@@ -90,14 +158,22 @@ func (r *IssueRepository) GetWithFilter(projectID string, filter schemas.FilterI
 	}
 
 	if filter.UserID != nil && *filter.UserID != "" {
-		query = query.Where("user_id LIKE %?%", filter.UserID)
+		query = query.Where("issues.assignee_id = ?", *filter.UserID)
 	}
 
-	if err := query.Find(&issues).Error; err != nil {
-		return nil, fmt.Errorf("failed to fetch issues: %w", err)
+	// issues having any of the labels
+	if len(filter.LabelIDs) > 0 {
+		query = query.Where("id IN (?)", r.db.
+			Table("issue_labels").
+			Select("issue_id").
+			Where("label_id IN ?", filter.LabelIDs))
 	}
 
-	return issues, nil
+	if compiled != nil {
+		query = compiled.Apply(query)
+	}
+
+	return query
 }
 
 func (r *IssueRepository) GetSequence(projectID string, parentID *string) (int, error) {
@@ -126,6 +202,60 @@ func (r *IssueRepository) GetSequence(projectID string, parentID *string) (int,
 	return last.Order + 1, nil
 }
 
+// GetDueBefore fetch the open issues having due date up to `until`, include the project settings
+func (r *IssueRepository) GetDueBefore(until time.Time) ([]model.Issue, error) {
+	var issues []model.Issue
+
+	if err := r.db.
+		Preload("Project.Setting").
+		Where("due_date IS NOT NULL AND due_date <= ?", until).
+		Where("status != ? AND status_category != ?", types.IssueStatusDraft, types.CategoryDone).
+		Order("due_date ASC").
+		Find(&issues).
+		Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch due issues: %w", err)
+	}
+
+	return issues, nil
+}
+
+// GetAssignedDue fetch the user open issues due before `to`, `from` nil including all the overdue ones
+func (r *IssueRepository) GetAssignedDue(projectID, userID string, from *time.Time, to time.Time) ([]model.Issue, error) {
+	var issues []model.Issue
+
+	query := r.db.
+		Where("project_id = ? AND assignee_id = ?", projectID, userID).
+		Where("status != ? AND status_category != ?", types.IssueStatusDraft, types.CategoryDone).
+		Where("due_date < ?", to).
+		Order("due_date ASC")
+
+	if from != nil {
+		query = query.Where("due_date >= ?", *from)
+	}
+
+	if err := query.Find(&issues).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch due issues: %w", err)
+	}
+
+	return issues, nil
+}
+
+func (r *IssueRepository) GetCompletedBetween(projectID string, from, to time.Time) ([]model.Issue, error) {
+	var issues []model.Issue
+
+	if err := r.db.
+		Preload("Assignee").
+		Where("project_id = ? AND status_category = ?", projectID, types.CategoryDone).
+		Where("done_date >= ? AND done_date < ?", from, to).
+		Order("done_date ASC").
+		Find(&issues).
+		Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch completed issues: %w", err)
+	}
+
+	return issues, nil
+}
+
 func (r *IssueRepository) CreateTx(tx *gorm.DB, issue *model.Issue) error {
 	if err := tx.Create(issue).Error; err != nil {
 		return fmt.Errorf("failed to create issue: %w", err)
@@ -135,7 +265,7 @@ func (r *IssueRepository) CreateTx(tx *gorm.DB, issue *model.Issue) error {
 }
 
 func (r *IssueRepository) UpdateTx(tx *gorm.DB, issue *model.Issue) error {
-	if issue.DoneDate != nil && issue.Status != types.IssueStatusDone {
+	if issue.DoneDate != nil && issue.StatusCategory != types.CategoryDone {
 		updates := map[string]any{
 			"done_date":  nil,
 			"updated_at": time.Now(),
@@ -148,20 +278,26 @@ func (r *IssueRepository) UpdateTx(tx *gorm.DB, issue *model.Issue) error {
 	}
 
 	updates := map[string]any{
-		"title":       issue.Title,
-		"description": issue.Description,
-		"priority":    issue.Priority,
-		"type":        issue.Type,
-		"status":      issue.Status,
-		"assignee_id": issue.AssigneeID,
-		"reporter_id": issue.ReporterID,
-		"label":       issue.Label,
-		"goal":        issue.Goal,
-		"parents":     issue.Parents,
-		"updated_at":  time.Now(),
-		"done_date":   issue.DoneDate,
-		"start_date":  issue.StartDate,
-		"due_date":    issue.DueDate,
+		"title":           issue.Title,
+		"description":     issue.Description,
+		"priority":        issue.Priority,
+		"type":            issue.Type,
+		"status":          issue.Status,
+		"status_category": issue.StatusCategory,
+		"assignee_id":     issue.AssigneeID,
+		"reporter_id":     issue.ReporterID,
+		"sprint_id":       issue.SprintID,
+		"goal":            issue.Goal,
+		"parents":         issue.Parents,
+		"updated_at":      time.Now(),
+		"done_date":       issue.DoneDate,
+		"start_date":      issue.StartDate,
+		"due_date":        issue.DueDate,
+	}
+
+	// nil leave the stored values untouched
+	if issue.CustomFields != nil {
+		updates["custom_fields"] = issue.CustomFields
 	}
 
 	if err := tx.Model(issue).Omit("Order").
@@ -173,6 +309,48 @@ func (r *IssueRepository) UpdateTx(tx *gorm.DB, issue *model.Issue) error {
 	return nil
 }
 
+func (r *IssueRepository) UpdateStatusTx(tx *gorm.DB, issue *model.Issue) error {
+	issue.UpdatedAt = time.Now()
+	if err := tx.Model(&model.Issue{}).
+		Where("id = ?", issue.ID).
+		Updates(map[string]any{
+			"status":          issue.Status,
+			"status_category": issue.StatusCategory,
+			"done_date":       issue.DoneDate,
+			"updated_at":      issue.UpdatedAt,
+		}).Error; err != nil {
+		return fmt.Errorf("failed to update issue status: %w", err)
+	}
+	return nil
+}
+
+// UpdateFieldsTx update only the given columns, usage for the bulk changes
+func (r *IssueRepository) UpdateFieldsTx(tx *gorm.DB, issue *model.Issue, updates map[string]any) error {
+	issue.UpdatedAt = time.Now()
+	updates["updated_at"] = issue.UpdatedAt
+
+	if err := tx.Model(&model.Issue{}).
+		Where("id = ?", issue.ID).
+		Updates(updates).Error; err != nil {
+		return fmt.Errorf("failed to update issue: %w", err)
+	}
+	return nil
+}
+
+func (r *IssueRepository) UpdateEstimateTx(tx *gorm.DB, issue *model.Issue) error {
+	issue.UpdatedAt = time.Now()
+	if err := tx.Model(&model.Issue{}).
+		Where("id = ?", issue.ID).
+		Updates(map[string]any{
+			"original_estimate":  issue.OriginalEstimate,
+			"remaining_estimate": issue.RemainingEstimate,
+			"updated_at":         issue.UpdatedAt,
+		}).Error; err != nil {
+		return fmt.Errorf("failed to update issue estimate: %w", err)
+	}
+	return nil
+}
+
 func (r *IssueRepository) UpdateWithOrderTx(tx *gorm.DB, issue *model.Issue) error {
 	issue.UpdatedAt = time.Now()
 	if err := tx.Save(issue).Error; err != nil {
@@ -181,10 +359,17 @@ func (r *IssueRepository) UpdateWithOrderTx(tx *gorm.DB, issue *model.Issue) err
 	return nil
 }
 
+// UpdateSequenceTx the siblings read through the transaction, the earlier changes of the batch counted
 func (r *IssueRepository) UpdateSequenceTx(tx *gorm.DB, issue *model.Issue, direction types.MoveDirection) ([]model.Issue, error) {
-	issues, err := r.GetByProjectID(false, issue.ProjectID, issue.Parents)
-	if err != nil {
-		return nil, err
+	var issues []model.Issue
+	if err := tx.
+		Preload("Labels").
+		Where("project_id = ?", issue.ProjectID).
+		Scopes(siblings(issue.Parents)).
+		Order("order_index ASC").
+		Find(&issues).
+		Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch issues: %w", err)
 	}
 
 	currentIndex := -1
@@ -236,7 +421,7 @@ func (r *IssueRepository) UpdateSequenceTx(tx *gorm.DB, issue *model.Issue, dire
 		ids[i] = issue.ID
 	}
 
-	err = tx.Exec(
+	err := tx.Exec(
 		`UPDATE issues SET order_index = CASE id `+strings.Join(updateCases, " ")+` END WHERE id IN ? `,
 		append(params, ids)...,
 	).Error
@@ -294,18 +479,26 @@ func (r *IssueRepository) Heartbeat(ID string) {
 	}
 }
 
-func (r *IssueRepository) CountByUser(userID string, status types.IssueStatus) (int64, error) {
-	var count int64
-	err := r.db.Model(&model.Issue{}).
-		Where("assignee_id = ? AND status != ?", userID, types.IssueStatusDone).
-		Count(&count).
-		Error
+// CountOpenByAssignees count the open issues (not draft/done) each user assigned on the project
+func (r *IssueRepository) CountOpenByAssignees(projectID string, userIDs []string) (map[string]int, error) {
+	var rows []schemas.IssueCount
 
-	if err != nil {
-		return 0, fmt.Errorf("failed to count issues for user %s: %w", userID, err)
+	if err := r.db.Model(&model.Issue{}).
+		Select("assignee_id AS user_id, COUNT(*) AS count").
+		Where("project_id = ? AND assignee_id IN ?", projectID, userIDs).
+		Where("status != ? AND status_category != ?", types.IssueStatusDraft, types.CategoryDone).
+		Group("assignee_id").
+		Scan(&rows).
+		Error; err != nil {
+		return nil, fmt.Errorf("failed to count open issues: %w", err)
 	}
 
-	return count, nil
+	counts := make(map[string]int, len(rows))
+	for _, row := range rows {
+		counts[row.UserID] = row.Count
+	}
+
+	return counts, nil
 }
 
 func (r *IssueRepository) DeleteByID(tx *gorm.DB, ID string) error {
@@ -323,3 +516,13 @@ func (r *IssueRepository) DeleteByParent(tx *gorm.DB, parentID string) error {
 	}
 	return nil
 }
+
+// siblings the subtasks of the parent, or the top level issues when none
+func siblings(parentID *string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if parentID != nil && *parentID != "" {
+			return db.Where("parents = ?", parentID)
+		}
+		return db.Where("parents IS NULL OR parents = ''")
+	}
+}
@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"time"
 	"webservices/src/model"
+	"webservices/src/types"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type IssueItemRepository struct {
@@ -40,6 +42,48 @@ func (r *IssueItemRepository) GetByID(ID string) (*model.IssueItem, error) {
 	return &item, nil
 }
 
+// GetLinksByIssueID fetch the issue links include the linked issue
+func (r *IssueItemRepository) GetLinksByIssueID(issueID string) ([]model.IssueItem, error) {
+	var items []model.IssueItem
+	if err := r.db.
+		Preload("LinkedIssue").
+		Where("issue_id = ? AND type = ? AND linked_issue_id IS NOT NULL", issueID, types.LinkWork).
+		Order("created_at ASC").
+		Find(&items).
+		Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch issue links: %w", err)
+	}
+
+	return items, nil
+}
+
+func (r *IssueItemRepository) CountLink(issueID, linkedIssueID string, linkType types.IssueLinkType) (int64, error) {
+	var count int64
+	if err := r.db.Model(&model.IssueItem{}).
+		Where("issue_id = ? AND linked_issue_id = ? AND link_type = ?", issueID, linkedIssueID, linkType).
+		Count(&count).
+		Error; err != nil {
+		return 0, fmt.Errorf("failed to count issue link: %w", err)
+	}
+
+	return count, nil
+}
+
+// GetOpenBlockers fetch the issues blocking `issueID` which not done yet
+func (r *IssueItemRepository) GetOpenBlockers(issueID string) ([]model.Issue, error) {
+	var issues []model.Issue
+	if err := r.db.
+		Joins("JOIN issue_items ON issue_items.linked_issue_id = issues.id").
+		Where("issue_items.issue_id = ? AND issue_items.link_type = ?", issueID, types.LinkBlockedBy).
+		Where("issues.status_category != ?", types.CategoryDone).
+		Find(&issues).
+		Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch blockers: %w", err)
+	}
+
+	return issues, nil
+}
+
 func (r *IssueItemRepository) Create(item *model.IssueItem) error {
 	return r.db.Create(item).Error
 }
@@ -65,3 +109,14 @@ func (r *IssueItemRepository) Delete(ID string) error {
 func (r *IssueItemRepository) DeleteTx(tx *gorm.DB, ID string) error {
 	return tx.Delete(&model.IssueItem{}, "id = ?", ID).Error
 }
+
+// DeleteLinkTx returning the deleted links
+func (r *IssueItemRepository) DeleteLinkTx(tx *gorm.DB, issueID, linkedIssueID string, linkType types.IssueLinkType) ([]model.IssueItem, error) {
+	var deleted []model.IssueItem
+	if err := tx.Clauses(clause.Returning{}).
+		Where("issue_id = ? AND linked_issue_id = ? AND link_type = ?", issueID, linkedIssueID, linkType).
+		Delete(&deleted).Error; err != nil {
+		return nil, fmt.Errorf("failed to delete issue link: %w", err)
+	}
+	return deleted, nil
+}
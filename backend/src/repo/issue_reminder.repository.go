@@ -0,0 +1,32 @@
+package repo
+
+import (
+	"fmt"
+	"webservices/src/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IssueReminderRepository struct {
+	*baseRepository
+}
+
+func NewIssueReminderRepository(db *gorm.DB) *IssueReminderRepository {
+	return &IssueReminderRepository{
+		baseRepository: newBaseRepository(db),
+	}
+}
+
+// CreateTx return false when the reminder already sent before
+func (r *IssueReminderRepository) CreateTx(tx *gorm.DB, reminder *model.IssueReminder) (bool, error) {
+	result := tx.
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(reminder)
+
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to create issue reminder: %w", result.Error)
+	}
+
+	return result.RowsAffected > 0, nil
+}
@@ -0,0 +1,91 @@
+package repo
+
+import (
+	"fmt"
+	"webservices/src/model"
+
+	"gorm.io/gorm"
+)
+
+type IssueTemplateRepository struct {
+	*baseRepository
+}
+
+func NewIssueTemplateRepository(db *gorm.DB) *IssueTemplateRepository {
+	return &IssueTemplateRepository{
+		baseRepository: newBaseRepository(db),
+	}
+}
+
+func (r *IssueTemplateRepository) GetByID(ID string) (*model.IssueTemplate, error) {
+	var template model.IssueTemplate
+	if err := r.db.First(&template, "id = ?", ID).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch issue template: %w", err)
+	}
+
+	return &template, nil
+}
+
+func (r *IssueTemplateRepository) GetByProjectID(projectID string) ([]model.IssueTemplate, error) {
+	templates := make([]model.IssueTemplate, 0)
+	if err := r.db.
+		Where("project_id = ?", projectID).
+		Order("name ASC").
+		Find(&templates).
+		Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch issue templates: %w", err)
+	}
+
+	return templates, nil
+}
+
+func (r *IssueTemplateRepository) ExistsByName(projectID, name, excludeID string) (bool, error) {
+	var count int64
+	query := r.db.Model(&model.IssueTemplate{}).
+		Where("project_id = ? AND LOWER(name) = LOWER(?)", projectID, name)
+
+	if excludeID != "" {
+		query = query.Where("id <> ?", excludeID)
+	}
+
+	if err := query.Count(&count).Error; err != nil {
+		return false, fmt.Errorf("failed to check issue template name: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+func (r *IssueTemplateRepository) CreateTx(tx *gorm.DB, template *model.IssueTemplate) error {
+	if err := tx.Omit("Project", "Assignee", "Creator").Create(template).Error; err != nil {
+		return fmt.Errorf("failed to create issue template: %w", err)
+	}
+
+	return nil
+}
+
+func (r *IssueTemplateRepository) UpdateTx(tx *gorm.DB, template *model.IssueTemplate) error {
+	if err := tx.Model(template).
+		Updates(map[string]any{
+			"name":          template.Name,
+			"title_pattern": template.TitlePattern,
+			"description":   template.Description,
+			"type":          template.Type,
+			"priority":      template.Priority,
+			"assignee_id":   template.AssigneeID,
+			"checklist":     template.Checklist,
+			"custom_fields": template.CustomFields,
+		}).Error; err != nil {
+		return fmt.Errorf("failed to update issue template: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteTx the template recurrences removed along by the foreign key
+func (r *IssueTemplateRepository) DeleteTx(tx *gorm.DB, ID string) error {
+	if err := tx.Delete(&model.IssueTemplate{}, "id = ?", ID).Error; err != nil {
+		return fmt.Errorf("failed to delete issue template: %w", err)
+	}
+
+	return nil
+}
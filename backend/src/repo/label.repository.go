@@ -0,0 +1,114 @@
+package repo
+
+import (
+	"fmt"
+	"webservices/src/model"
+
+	"gorm.io/gorm"
+)
+
+type LabelRepository struct {
+	*baseRepository
+}
+
+func NewLabelRepository(db *gorm.DB) *LabelRepository {
+	return &LabelRepository{
+		baseRepository: newBaseRepository(db),
+	}
+}
+
+func (r *LabelRepository) GetByID(ID string) (*model.Label, error) {
+	var label model.Label
+	if err := r.db.First(&label, "id = ?", ID).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch label: %w", err)
+	}
+
+	return &label, nil
+}
+
+func (r *LabelRepository) GetByProjectID(projectID string) ([]model.Label, error) {
+	var labels []model.Label
+	if err := r.db.
+		Where("project_id = ?", projectID).
+		Order("name ASC").
+		Find(&labels).
+		Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch labels: %w", err)
+	}
+
+	return labels, nil
+}
+
+// GetByIDs fetch the labels owned by the project, the unknown or foreign IDs are left out
+func (r *LabelRepository) GetByIDs(projectID string, IDs []string) ([]model.Label, error) {
+	labels := make([]model.Label, 0)
+	if len(IDs) == 0 {
+		return labels, nil
+	}
+
+	if err := r.db.
+		Where("project_id = ? AND id IN ?", projectID, IDs).
+		Find(&labels).
+		Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch labels: %w", err)
+	}
+
+	return labels, nil
+}
+
+// ExistsName check the label name case-insensitively, `excludeID` skip the label being renamed
+func (r *LabelRepository) ExistsName(projectID, name string, excludeID *string) (bool, error) {
+	var count int64
+
+	query := r.db.Model(&model.Label{}).
+		Where("project_id = ? AND LOWER(name) = LOWER(?)", projectID, name)
+
+	if excludeID != nil {
+		query = query.Where("id != ?", *excludeID)
+	}
+
+	if err := query.Count(&count).Error; err != nil {
+		return false, fmt.Errorf("failed to count label: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+func (r *LabelRepository) CreateTx(tx *gorm.DB, label *model.Label) error {
+	if err := tx.Create(label).Error; err != nil {
+		return fmt.Errorf("failed to create label: %w", err)
+	}
+
+	return nil
+}
+
+func (r *LabelRepository) UpdateTx(tx *gorm.DB, label *model.Label) error {
+	if err := tx.Model(label).
+		Updates(map[string]any{
+			"name":  label.Name,
+			"color": label.Color,
+		}).Error; err != nil {
+		return fmt.Errorf("failed to update label: %w", err)
+	}
+
+	return nil
+}
+
+func (r *LabelRepository) DeleteTx(tx *gorm.DB, ID string) error {
+	if err := tx.Delete(&model.Label{}, "id = ?", ID).Error; err != nil {
+		return fmt.Errorf("failed to delete label: %w", err)
+	}
+
+	return nil
+}
+
+// ReplaceIssueLabelsTx set the issue labels to exactly `labels`
+func (r *LabelRepository) ReplaceIssueLabelsTx(tx *gorm.DB, issue *model.Issue, labels []model.Label) error {
+	if err := tx.Model(issue).Omit("Labels.*").
+		Association("Labels").
+		Replace(labels); err != nil {
+		return fmt.Errorf("failed to update issue labels: %w", err)
+	}
+
+	return nil
+}
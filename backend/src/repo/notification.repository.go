@@ -2,7 +2,9 @@ package repo
 
 import (
 	"fmt"
+	"time"
 	"webservices/src/model"
+	"webservices/src/pkg/pagination"
 
 	"gorm.io/gorm"
 )
@@ -17,23 +19,34 @@ func NewNotificationRepository(db *gorm.DB) *NotificationRepository {
 	}
 }
 
-func (r *NotificationRepository) GetByUserID(userID string) ([]model.Notification, error) {
+func (r *NotificationRepository) GetByUserID(userID string, params pagination.Params) (*pagination.Page[model.Notification], error) {
 	var notifications []model.Notification
 
 	if err := r.db.
-		Order("created_at DESC").
+		Scopes(params.Scope("notifications", true)).
 		Find(&notifications, "user_id = ?",
 			userID).Error; err != nil {
 		return nil, fmt.Errorf("failed to fetch notification: %w", err)
 	}
 
-	return notifications, nil
+	page := pagination.NewPage(notifications, params, func(n model.Notification) (time.Time, string) {
+		return n.CreatedAt, n.ID
+	})
+
+	return &page, nil
 }
 
 func (r NotificationRepository) Create(notif *model.Notification) error {
 	return r.db.Create(notif).Error
 }
 
+func (r NotificationRepository) CreateTx(tx *gorm.DB, notif *model.Notification) error {
+	if err := tx.Create(notif).Error; err != nil {
+		return fmt.Errorf("failed to create notification: %w", err)
+	}
+	return nil
+}
+
 func (r NotificationRepository) Read(ID string) error {
 	if err := r.db.Table("notifications").
 		Where("id = ?", ID).
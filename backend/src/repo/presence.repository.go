@@ -0,0 +1,100 @@
+package repo
+
+import (
+	"errors"
+	"fmt"
+	"time"
+	"webservices/src/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PresenceRepository struct {
+	*baseRepository
+}
+
+func NewPresenceRepository(db *gorm.DB) *PresenceRepository {
+	return &PresenceRepository{
+		baseRepository: newBaseRepository(db),
+	}
+}
+
+func (r *PresenceRepository) GetByID(ID string) (*model.PresenceSession, error) {
+	var session model.PresenceSession
+	if err := r.db.First(&session, "id = ?", ID).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch presence session: %w", err)
+	}
+
+	return &session, nil
+}
+
+// GetByUserIDs the sessions of the users seen since `since`, the older ones waiting for the expiry
+func (r *PresenceRepository) GetByUserIDs(userIDs []string, since time.Time) ([]model.PresenceSession, error) {
+	sessions := make([]model.PresenceSession, 0)
+	if len(userIDs) == 0 {
+		return sessions, nil
+	}
+
+	if err := r.db.
+		Where("user_id IN ? AND seen_at >= ?", userIDs, since).
+		Order("created_at ASC").
+		Find(&sessions).
+		Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch presence sessions: %w", err)
+	}
+
+	return sessions, nil
+}
+
+// Save create the session or replace it, the socket id kept across the reconnections
+func (r *PresenceRepository) Save(session *model.PresenceSession) error {
+	if err := r.db.Omit("User", "Project", "Issue").
+		Clauses(clause.OnConflict{UpdateAll: true}).
+		Create(session).Error; err != nil {
+		return fmt.Errorf("failed to save presence session: %w", err)
+	}
+	return nil
+}
+
+// Update the session fields, false once the session already expired
+func (r *PresenceRepository) Update(ID string, values map[string]any) (bool, error) {
+	result := r.db.Model(&model.PresenceSession{}).
+		Where("id = ?", ID).
+		Updates(values)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to update presence session: %w", result.Error)
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// Delete returning the deleted session, nil when already expired
+func (r *PresenceRepository) Delete(ID string) (*model.PresenceSession, error) {
+	var session model.PresenceSession
+	result := r.db.Clauses(clause.Returning{}).
+		Where("id = ?", ID).
+		Delete(&session)
+	if result.Error != nil && !errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to delete presence session: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	return &session, nil
+}
+
+// DeleteStale remove the sessions not seen since `before`, returning them
+func (r *PresenceRepository) DeleteStale(before time.Time) ([]model.PresenceSession, error) {
+	var sessions []model.PresenceSession
+	if err := r.db.Clauses(clause.Returning{}).
+		Where("seen_at < ?", before).
+		Delete(&sessions).
+		Error; err != nil {
+		return nil, fmt.Errorf("failed to expire presence sessions: %w", err)
+	}
+
+	return sessions, nil
+}
@@ -64,6 +64,23 @@ func (r *ProjectRepository) GetIncludeDetailTx(tx *gorm.DB, ID string) (*model.P
 	return &project, nil
 }
 
+// GetWithDailyDigest fetch the active projects having `daily_digest` setting on, include the members
+func (r *ProjectRepository) GetWithDailyDigest() ([]model.Project, error) {
+	var projects []model.Project
+
+	if err := r.db.
+		Joins("Setting").
+		Preload("Users").
+		Where(`"Setting".daily_digest = ? AND projects.status = ?`, true, types.ProjectStatusActive).
+		Order("projects.created_at ASC").
+		Find(&projects).
+		Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch digest projects: %w", err)
+	}
+
+	return projects, nil
+}
+
 func (r *ProjectRepository) GetIncludeRole(userID, projectID string) (*model.Project, error) {
 	var project model.Project
 
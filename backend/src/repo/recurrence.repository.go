@@ -0,0 +1,139 @@
+package repo
+
+import (
+	"fmt"
+	"time"
+	"webservices/src/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RecurrenceRepository struct {
+	*baseRepository
+}
+
+func NewRecurrenceRepository(db *gorm.DB) *RecurrenceRepository {
+	return &RecurrenceRepository{
+		baseRepository: newBaseRepository(db),
+	}
+}
+
+func (r *RecurrenceRepository) GetByID(ID string) (*model.IssueRecurrence, error) {
+	var recurrence model.IssueRecurrence
+	if err := r.db.
+		Preload("Template").
+		First(&recurrence, "id = ?", ID).
+		Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch recurrence: %w", err)
+	}
+
+	return &recurrence, nil
+}
+
+func (r *RecurrenceRepository) GetByProjectID(projectID string) ([]model.IssueRecurrence, error) {
+	recurrences := make([]model.IssueRecurrence, 0)
+	if err := r.db.
+		Preload("Template").
+		Where("project_id = ?", projectID).
+		Order("created_at ASC").
+		Find(&recurrences).
+		Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch recurrences: %w", err)
+	}
+
+	return recurrences, nil
+}
+
+// GetDue the active recurrences having an occurrence on or before the `today`
+func (r *RecurrenceRepository) GetDue(today time.Time) ([]model.IssueRecurrence, error) {
+	recurrences := make([]model.IssueRecurrence, 0)
+	if err := r.db.
+		Preload("Template").
+		Where("active = ? AND next_run_at IS NOT NULL AND next_run_at <= ?", true, today).
+		Order("next_run_at ASC").
+		Find(&recurrences).
+		Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch due recurrences: %w", err)
+	}
+
+	return recurrences, nil
+}
+
+func (r *RecurrenceRepository) CreateTx(tx *gorm.DB, recurrence *model.IssueRecurrence) error {
+	if err := tx.Omit("Project", "Template", "Creator").Create(recurrence).Error; err != nil {
+		return fmt.Errorf("failed to create recurrence: %w", err)
+	}
+
+	return nil
+}
+
+func (r *RecurrenceRepository) UpdateTx(tx *gorm.DB, recurrence *model.IssueRecurrence) error {
+	if err := tx.Model(recurrence).
+		Updates(map[string]any{
+			"template_id": recurrence.TemplateID,
+			"rule":        recurrence.Rule,
+			"start_date":  recurrence.StartDate,
+			"next_run_at": recurrence.NextRunAt,
+			"active":      recurrence.Active,
+		}).Error; err != nil {
+		return fmt.Errorf("failed to update recurrence: %w", err)
+	}
+
+	return nil
+}
+
+// AdvanceTx move to the `next` occurrence, nil once the rule ended
+func (r *RecurrenceRepository) AdvanceTx(tx *gorm.DB, ID string, last time.Time, next *time.Time) error {
+	if err := tx.Model(&model.IssueRecurrence{}).
+		Where("id = ?", ID).
+		Updates(map[string]any{
+			"last_run_at": last,
+			"next_run_at": next,
+		}).Error; err != nil {
+		return fmt.Errorf("failed to advance recurrence: %w", err)
+	}
+
+	return nil
+}
+
+func (r *RecurrenceRepository) DeleteTx(tx *gorm.DB, ID string) error {
+	if err := tx.Delete(&model.IssueRecurrence{}, "id = ?", ID).Error; err != nil {
+		return fmt.Errorf("failed to delete recurrence: %w", err)
+	}
+
+	return nil
+}
+
+// ClaimTx return false when the occurrence already generated
+func (r *RecurrenceRepository) ClaimTx(tx *gorm.DB, run *model.RecurrenceRun) (bool, error) {
+	result := tx.
+		Omit("Recurrence", "Issue").
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(run)
+
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to create recurrence run: %w", result.Error)
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// ReleaseTx drop the claim of the failed occurrence, so it retried on the next run
+func (r *RecurrenceRepository) ReleaseTx(tx *gorm.DB, run *model.RecurrenceRun) error {
+	if err := tx.Delete(&model.RecurrenceRun{}, "id = ?", run.ID).Error; err != nil {
+		return fmt.Errorf("failed to release recurrence run: %w", err)
+	}
+
+	return nil
+}
+
+func (r *RecurrenceRepository) SetRunIssueTx(tx *gorm.DB, run *model.RecurrenceRun, issueID string) error {
+	if err := tx.Model(run).
+		Update("issue_id", issueID).
+		Error; err != nil {
+		return fmt.Errorf("failed to update recurrence run: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package repo
+
+import (
+	"fmt"
+	"webservices/src/model"
+	"webservices/src/types"
+
+	"gorm.io/gorm"
+)
+
+type SavedFilterRepository struct {
+	*baseRepository
+}
+
+func NewSavedFilterRepository(db *gorm.DB) *SavedFilterRepository {
+	return &SavedFilterRepository{
+		baseRepository: newBaseRepository(db),
+	}
+}
+
+func (r *SavedFilterRepository) GetByID(ID string) (*model.SavedFilter, error) {
+	var filter model.SavedFilter
+	if err := r.db.Preload("Owner").First(&filter, "id = ?", ID).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch filter: %w", err)
+	}
+
+	return &filter, nil
+}
+
+// GetVisible fetch the user own filters plus the ones shared to the project
+func (r *SavedFilterRepository) GetVisible(projectID, userID string) ([]model.SavedFilter, error) {
+	var filters []model.SavedFilter
+	if err := r.db.
+		Preload("Owner").
+		Where("project_id = ?", projectID).
+		Where("owner_id = ? OR visibility = ?", userID, types.FilterProject).
+		Order("name ASC").
+		Find(&filters).
+		Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch filters: %w", err)
+	}
+
+	return filters, nil
+}
+
+func (r *SavedFilterRepository) CreateTx(tx *gorm.DB, filter *model.SavedFilter) error {
+	if err := tx.Create(filter).Error; err != nil {
+		return fmt.Errorf("failed to create filter: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SavedFilterRepository) UpdateTx(tx *gorm.DB, filter *model.SavedFilter) error {
+	if err := tx.Model(filter).
+		Updates(map[string]any{
+			"name":       filter.Name,
+			"query":      filter.Query,
+			"sort":       filter.Sort,
+			"group_by":   filter.GroupBy,
+			"visibility": filter.Visibility,
+		}).Error; err != nil {
+		return fmt.Errorf("failed to update filter: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SavedFilterRepository) DeleteTx(tx *gorm.DB, ID string) error {
+	if err := tx.Delete(&model.SavedFilter{}, "id = ?", ID).Error; err != nil {
+		return fmt.Errorf("failed to delete filter: %w", err)
+	}
+
+	return nil
+}
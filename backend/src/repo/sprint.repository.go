@@ -0,0 +1,140 @@
+package repo
+
+import (
+	"errors"
+	"fmt"
+	"time"
+	"webservices/src/model"
+	"webservices/src/types"
+
+	"gorm.io/gorm"
+)
+
+type SprintRepository struct {
+	*baseRepository
+}
+
+func NewSprintRepository(db *gorm.DB) *SprintRepository {
+	return &SprintRepository{
+		baseRepository: newBaseRepository(db),
+	}
+}
+
+func (r *SprintRepository) GetByID(ID string) (*model.Sprint, error) {
+	var sprint model.Sprint
+	if err := r.db.First(&sprint, "id = ?", ID).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch sprint: %w", err)
+	}
+
+	return &sprint, nil
+}
+
+func (r *SprintRepository) GetIncludeIssues(ID string) (*model.Sprint, error) {
+	var sprint model.Sprint
+	if err := r.db.
+		Preload("Issues", func(db *gorm.DB) *gorm.DB {
+			return db.Order("order_index ASC")
+		}).
+		First(&sprint, "id = ?", ID).
+		Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch sprint: %w", err)
+	}
+
+	return &sprint, nil
+}
+
+func (r *SprintRepository) GetByProjectID(projectID string, state *types.SprintState) ([]model.Sprint, error) {
+	var sprints []model.Sprint
+
+	query := r.db.
+		Where("project_id = ?", projectID).
+		Order("created_at ASC")
+
+	if state != nil && *state != "" {
+		query = query.Where("state = ?", *state)
+	}
+
+	if err := query.Find(&sprints).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch sprints: %w", err)
+	}
+
+	return sprints, nil
+}
+
+func (r *SprintRepository) GetActive(projectID string) (*model.Sprint, error) {
+	var sprint model.Sprint
+	err := r.db.
+		Where("project_id = ? AND state = ?", projectID, types.SprintStateActive).
+		First(&sprint).
+		Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to fetch active sprint: %w", err)
+	}
+
+	return &sprint, nil
+}
+
+func (r *SprintRepository) GetUnfinishedIssues(sprintID string) ([]model.Issue, error) {
+	var issues []model.Issue
+	if err := r.db.
+		Preload("Labels").
+		Where("sprint_id = ? AND status_category != ?", sprintID, types.CategoryDone).
+		Order("order_index ASC").
+		Find(&issues).
+		Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch sprint issues: %w", err)
+	}
+
+	return issues, nil
+}
+
+func (r *SprintRepository) CreateTx(tx *gorm.DB, sprint *model.Sprint) error {
+	if err := tx.Create(sprint).Error; err != nil {
+		return fmt.Errorf("failed to create sprint: %w", err)
+	}
+	return nil
+}
+
+func (r *SprintRepository) UpdateTx(tx *gorm.DB, sprint *model.Sprint) error {
+	sprint.UpdatedAt = time.Now()
+	if err := tx.Omit("Project", "Issues").Save(sprint).Error; err != nil {
+		return fmt.Errorf("failed to update sprint: %w", err)
+	}
+	return nil
+}
+
+// `sprintID` nil moving the issues back to the backlog
+func (r *SprintRepository) MoveIssuesTx(tx *gorm.DB, issueIDs []string, sprintID *string) error {
+	if len(issueIDs) == 0 {
+		return nil
+	}
+
+	if err := tx.Model(&model.Issue{}).
+		Where("id IN ?", issueIDs).
+		Updates(map[string]any{
+			"sprint_id":  sprintID,
+			"updated_at": time.Now(),
+		}).Error; err != nil {
+		return fmt.Errorf("failed to move sprint issues: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SprintRepository) DeleteTx(tx *gorm.DB, ID string) error {
+	if err := tx.Model(&model.Issue{}).
+		Where("sprint_id = ?", ID).
+		Update("sprint_id", nil).Error; err != nil {
+		return fmt.Errorf("failed to release sprint issues: %w", err)
+	}
+
+	if err := tx.Delete(&model.Sprint{}, "id = ?", ID).Error; err != nil {
+		return fmt.Errorf("failed to delete sprint: %w", err)
+	}
+
+	return nil
+}
@@ -31,6 +31,23 @@ func (r *UserRepository) GetByID(ID string) (*model.User, error) {
 	return &user, nil
 }
 
+func (r *UserRepository) GetByIDs(IDs []string) ([]model.User, error) {
+	users := make([]model.User, 0, len(IDs))
+	if len(IDs) == 0 {
+		return users, nil
+	}
+
+	if err := r.db.
+		Where("id IN ?", IDs).
+		Order("name ASC").
+		Find(&users).
+		Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch users: %w", err)
+	}
+
+	return users, nil
+}
+
 func (r *UserRepository) GetDetail(ID string) (*model.User, error) {
 	var user model.User
 
@@ -3,6 +3,7 @@ package repo
 import (
 	"fmt"
 	"webservices/src/model"
+	"webservices/src/types"
 
 	"gorm.io/gorm"
 )
@@ -62,6 +63,35 @@ func (r *UserProjectRepository) GetUserIDs(projectID string) ([]string, error) {
 	return ids, nil
 }
 
+// GetProjectIDs the projects the user is a member of
+func (r *UserProjectRepository) GetProjectIDs(userID string) ([]string, error) {
+	var ids []string
+
+	if err := r.db.
+		Model(&model.UserProject{}).
+		Where("user_id = ?", userID).
+		Distinct("project_id").
+		Pluck("project_id", &ids).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch user project IDs: %w", err)
+	}
+
+	return ids, nil
+}
+
+func (r *UserProjectRepository) GetUserIDsByRole(projectID string, roles ...types.UserProjectRole) ([]string, error) {
+	var ids []string
+
+	if err := r.db.
+		Model(&model.UserProject{}).
+		Where("project_id = ? AND role IN ?", projectID, roles).
+		Distinct("user_id").
+		Pluck("user_id", &ids).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch user project IDs: %w", err)
+	}
+
+	return ids, nil
+}
+
 func (r *UserProjectRepository) UpdateTx(tx *gorm.DB, userProject *model.UserProject) error {
 	if err := tx.Model(userProject).
 		Updates(userProject).Error; err != nil {
@@ -0,0 +1,147 @@
+package repo
+
+import (
+	"fmt"
+	"webservices/src/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WatcherRepository struct {
+	*baseRepository
+}
+
+func NewWatcherRepository(db *gorm.DB) *WatcherRepository {
+	return &WatcherRepository{
+		baseRepository: newBaseRepository(db),
+	}
+}
+
+func (r *WatcherRepository) GetByIssueID(issueID string) ([]model.IssueWatcher, error) {
+	watchers := make([]model.IssueWatcher, 0)
+	if err := r.db.
+		Joins("User").
+		Where("issue_watchers.issue_id = ? AND issue_watchers.watching = ?", issueID, true).
+		Order("issue_watchers.created_at ASC").
+		Find(&watchers).
+		Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch watchers: %w", err)
+	}
+
+	return watchers, nil
+}
+
+// GetUserIDs the users watching the issue, the `excludeID` (the actor) left out when given.
+// the watchers no longer member of the issue project left out too
+func (r *WatcherRepository) GetUserIDs(issueID, excludeID string) ([]string, error) {
+	ids := make([]string, 0)
+	query := r.db.Model(&model.IssueWatcher{}).
+		Scopes(projectMembers).
+		Where("issue_id = ? AND watching = ?", issueID, true)
+
+	if excludeID != "" {
+		query = query.Where("user_id != ?", excludeID)
+	}
+
+	if err := query.
+		Pluck("user_id", &ids).
+		Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch watchers: %w", err)
+	}
+
+	return ids, nil
+}
+
+// GetUserIDsByIssueIDs the watching users by issue, the ones no longer project member left out
+func (r *WatcherRepository) GetUserIDsByIssueIDs(issueIDs []string) (map[string][]string, error) {
+	var watchers []model.IssueWatcher
+	if err := r.db.
+		Scopes(projectMembers).
+		Select("issue_id", "user_id").
+		Where("issue_id IN ? AND watching = ?", issueIDs, true).
+		Find(&watchers).
+		Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch watchers: %w", err)
+	}
+
+	result := make(map[string][]string, len(issueIDs))
+	for _, w := range watchers {
+		result[w.IssueID] = append(result[w.IssueID], w.UserID)
+	}
+
+	return result, nil
+}
+
+func (r *WatcherRepository) IsWatching(issueID, userID string) (bool, error) {
+	var count int64
+	if err := r.db.Model(&model.IssueWatcher{}).
+		Where("issue_id = ? AND user_id = ? AND watching = ?", issueID, userID, true).
+		Count(&count).
+		Error; err != nil {
+		return false, fmt.Errorf("failed to check watcher: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+// AddTx subscribe the users not yet decided, the one who unwatched the issue stay unsubscribed
+func (r *WatcherRepository) AddTx(tx *gorm.DB, issueID string, userIDs ...*string) error {
+	watchers := make([]model.IssueWatcher, 0, len(userIDs))
+	seen := make(map[string]bool, len(userIDs))
+	for _, ID := range userIDs {
+		if ID != nil && *ID != "" && !seen[*ID] {
+			seen[*ID] = true
+			watchers = append(watchers, model.IssueWatcher{
+				IssueID:  issueID,
+				UserID:   *ID,
+				Watching: true,
+			})
+		}
+	}
+
+	if len(watchers) == 0 {
+		return nil
+	}
+
+	if err := tx.
+		Omit("Issue", "User").
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&watchers).
+		Error; err != nil {
+		return fmt.Errorf("failed to add watchers: %w", err)
+	}
+
+	return nil
+}
+
+// SetTx the explicit watch / unwatch of the user, overriding the auto subscription
+func (r *WatcherRepository) SetTx(tx *gorm.DB, issueID, userID string, watching bool) error {
+	watcher := model.IssueWatcher{
+		IssueID:  issueID,
+		UserID:   userID,
+		Watching: watching,
+	}
+
+	if err := tx.
+		Omit("Issue", "User").
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "issue_id"}, {Name: "user_id"}},
+			DoUpdates: clause.Assignments(map[string]any{"watching": watching, "updated_at": gorm.Expr("now()")}),
+		}).
+		Create(&watcher).
+		Error; err != nil {
+		return fmt.Errorf("failed to update watcher: %w", err)
+	}
+
+	return nil
+}
+
+// projectMembers keep the watchers still member of the issue project
+func projectMembers(db *gorm.DB) *gorm.DB {
+	return db.Where(`EXISTS (
+		SELECT 1 FROM user_projects
+		JOIN issues ON issues.project_id = user_projects.project_id
+		WHERE issues.id = issue_watchers.issue_id AND user_projects.user_id = issue_watchers.user_id
+	)`)
+}
@@ -0,0 +1,126 @@
+package repo
+
+import (
+	"fmt"
+	"webservices/src/model"
+	"webservices/src/types"
+
+	"gorm.io/gorm"
+)
+
+type WorkflowRepository struct {
+	*baseRepository
+}
+
+func NewWorkflowRepository(db *gorm.DB) *WorkflowRepository {
+	return &WorkflowRepository{
+		baseRepository: newBaseRepository(db),
+	}
+}
+
+// GetByProjectID the project workflow, the default one when the project never defined it.
+// the default one hold the `in_review` status while the project approval workflow enabled
+func (r *WorkflowRepository) GetByProjectID(projectID string) (*model.Workflow, error) {
+	var statuses []model.WorkflowStatus
+	if err := r.db.
+		Where("project_id = ?", projectID).
+		Order("position ASC").
+		Find(&statuses).
+		Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch workflow: %w", err)
+	}
+
+	if len(statuses) == 0 {
+		var approval bool
+		if err := r.db.Model(&model.ProjectSetting{}).
+			Select("enable_approval_workflow").
+			Where("project_id = ?", projectID).
+			Scan(&approval).
+			Error; err != nil {
+			return nil, fmt.Errorf("failed to fetch workflow: %w", err)
+		}
+
+		return model.DefaultWorkflow(projectID, approval), nil
+	}
+
+	transitions := make([]model.WorkflowTransition, 0)
+	if err := r.db.
+		Where("project_id = ?", projectID).
+		Order("created_at ASC").
+		Find(&transitions).
+		Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch workflow: %w", err)
+	}
+
+	return &model.Workflow{
+		ProjectID:   projectID,
+		Custom:      true,
+		Statuses:    statuses,
+		Transitions: transitions,
+	}, nil
+}
+
+// CountIssuesByStatus the number of the project issues on each of the statuses
+func (r *WorkflowRepository) CountIssuesByStatus(projectID string, keys []types.IssueStatus) (map[types.IssueStatus]int64, error) {
+	counts := make(map[types.IssueStatus]int64)
+	if len(keys) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		Status types.IssueStatus
+		Count  int64
+	}
+
+	if err := r.db.Model(&model.Issue{}).
+		Select("status, COUNT(*) AS count").
+		Where("project_id = ? AND status IN ?", projectID, keys).
+		Group("status").
+		Scan(&rows).
+		Error; err != nil {
+		return nil, fmt.Errorf("failed to count issues by status: %w", err)
+	}
+
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+
+	return counts, nil
+}
+
+// ReplaceTx swap the project statuses & transitions, then sync the category of its issues
+func (r *WorkflowRepository) ReplaceTx(tx *gorm.DB, workflow *model.Workflow) error {
+	if err := tx.Delete(&model.WorkflowTransition{},
+		"project_id = ?", workflow.ProjectID).Error; err != nil {
+		return fmt.Errorf("failed to replace workflow: %w", err)
+	}
+
+	if err := tx.Delete(&model.WorkflowStatus{},
+		"project_id = ?", workflow.ProjectID).Error; err != nil {
+		return fmt.Errorf("failed to replace workflow: %w", err)
+	}
+
+	if err := tx.Omit("Project").Create(&workflow.Statuses).Error; err != nil {
+		return fmt.Errorf("failed to create workflow statuses: %w", err)
+	}
+
+	if len(workflow.Transitions) > 0 {
+		if err := tx.Omit("Project").Create(&workflow.Transitions).Error; err != nil {
+			return fmt.Errorf("failed to create workflow transitions: %w", err)
+		}
+	}
+
+	if err := tx.Exec(`
+		UPDATE issues SET status_category = workflow_statuses.category
+		FROM workflow_statuses
+		WHERE workflow_statuses.project_id = issues.project_id
+			AND workflow_statuses.key = issues.status
+			AND issues.project_id = ?
+			AND issues.status_category != workflow_statuses.category`,
+		workflow.ProjectID,
+	).Error; err != nil {
+		return fmt.Errorf("failed to sync issue categories: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package repo
+
+import (
+	"fmt"
+	"time"
+	"webservices/src/model"
+
+	"gorm.io/gorm"
+)
+
+type WorklogRepository struct {
+	*baseRepository
+}
+
+func NewWorklogRepository(db *gorm.DB) *WorklogRepository {
+	return &WorklogRepository{
+		baseRepository: newBaseRepository(db),
+	}
+}
+
+func (r *WorklogRepository) GetByIssueID(issueID string) ([]model.Worklog, error) {
+	var worklogs []model.Worklog
+	if err := r.db.Joins("User").
+		Order("worklogs.date DESC, worklogs.created_at DESC").
+		Find(&worklogs, "issue_id = ?", issueID).
+		Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch worklogs: %w", err)
+	}
+	return worklogs, nil
+}
+
+func (r *WorklogRepository) GetByID(ID string) (*model.Worklog, error) {
+	var worklog model.Worklog
+	if err := r.db.Joins("Issue").
+		First(&worklog, "worklogs.id = ?", ID).
+		Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch worklog: %w", err)
+	}
+	return &worklog, nil
+}
+
+// SumByIssueIDs total logged minutes of the issues
+func (r *WorklogRepository) SumByIssueIDs(issueIDs []string) (int, error) {
+	if len(issueIDs) == 0 {
+		return 0, nil
+	}
+
+	var total int
+	if err := r.db.Model(&model.Worklog{}).
+		Select("COALESCE(SUM(minutes), 0)").
+		Where("issue_id IN ?", issueIDs).
+		Scan(&total).Error; err != nil {
+		return 0, fmt.Errorf("failed to sum worklogs: %w", err)
+	}
+
+	return total, nil
+}
+
+func (r *WorklogRepository) CreateTx(tx *gorm.DB, worklog *model.Worklog) error {
+	if err := tx.Create(worklog).Error; err != nil {
+		return fmt.Errorf("failed to create worklog: %w", err)
+	}
+	return nil
+}
+
+func (r *WorklogRepository) UpdateTx(tx *gorm.DB, worklog *model.Worklog) error {
+	worklog.UpdatedAt = time.Now()
+	if err := tx.Model(&model.Worklog{}).
+		Where("id = ?", worklog.ID).
+		Updates(map[string]any{
+			"minutes":    worklog.Minutes,
+			"date":       worklog.Date,
+			"note":       worklog.Note,
+			"updated_at": worklog.UpdatedAt,
+		}).Error; err != nil {
+		return fmt.Errorf("failed to update worklog: %w", err)
+	}
+	return nil
+}
+
+func (r *WorklogRepository) DeleteTx(tx *gorm.DB, ID string) error {
+	if err := tx.Delete(&model.Worklog{}, "id = ?", ID).Error; err != nil {
+		return fmt.Errorf("failed to delete worklog: %w", err)
+	}
+	return nil
+}
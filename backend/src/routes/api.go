@@ -11,7 +11,8 @@ import (
 )
 
 func Api(io *socket.Server, db *gorm.DB, r *gin.RouterGroup) {
-	// attachment := middleware.NewUploadFile("attachment", 1, 10, false, nil)
+	// hard cap of the upload, the project `MaxAttachmentSize` checked on the service
+	attachment := middleware.NewUploadFile("attachment", 1, 100, true, nil)
 	rateLimit := middleware.RateLimiter(5, 1*time.Hour)
 	repos := registry.NewRepositories(db)
 	services := registry.NewServices(repos, io)
@@ -42,6 +43,31 @@ func Api(io *socket.Server, db *gorm.DB, r *gin.RouterGroup) {
 			project.POST("/invite", rateLimit, ctrl.Notif.InviteProject)
 			project.POST("/:id/teams/access", ctrl.Project.ChangeAccess)
 			project.DELETE("/:id/teams", ctrl.Project.RemoveTeam)
+			project.GET("/:id/labels", ctrl.Label.GetLabels)
+			project.POST("/:id/labels", ctrl.Label.Create)
+			project.POST("/:id/labels/:label_id", ctrl.Label.Update)
+			project.DELETE("/:id/labels/:label_id", ctrl.Label.Delete)
+			project.GET("/:id/filters", ctrl.Filter.GetFilters)
+			project.POST("/:id/filters", ctrl.Filter.Create)
+			project.GET("/:id/filters/:filter_id", ctrl.Filter.GetFilterByID)
+			project.POST("/:id/filters/:filter_id", ctrl.Filter.Update)
+			project.DELETE("/:id/filters/:filter_id", ctrl.Filter.Delete)
+			project.GET("/:id/workflow", ctrl.Workflow.GetWorkflow)
+			project.POST("/:id/workflow", ctrl.Workflow.Update)
+			project.GET("/:id/fields", ctrl.Field.GetFields)
+			project.POST("/:id/fields", ctrl.Field.Create)
+			project.POST("/:id/fields/:field_id", ctrl.Field.Update)
+			project.DELETE("/:id/fields/:field_id", ctrl.Field.Delete)
+			project.GET("/:id/templates", ctrl.Template.GetTemplates)
+			project.POST("/:id/templates", ctrl.Template.CreateTemplate)
+			project.POST("/:id/templates/:template_id", ctrl.Template.UpdateTemplate)
+			project.DELETE("/:id/templates/:template_id", ctrl.Template.DeleteTemplate)
+			project.POST("/:id/templates/:template_id/apply", ctrl.Template.Apply)
+			project.GET("/:id/recurrences", ctrl.Template.GetRecurrences)
+			project.POST("/:id/recurrences", ctrl.Template.CreateRecurrence)
+			project.POST("/:id/recurrences/:recurrence_id", ctrl.Template.UpdateRecurrence)
+			project.DELETE("/:id/recurrences/:recurrence_id", ctrl.Template.DeleteRecurrence)
+			project.GET("/:id/presence", ctrl.Presence.GetByProject)
 		}
 
 		issue := auth.Group("/issue")
@@ -54,8 +80,14 @@ func Api(io *socket.Server, db *gorm.DB, r *gin.RouterGroup) {
 			issue.POST("", ctrl.Issue.Upsert)
 			issue.POST("/order", ctrl.Issue.UpdateOrder)
 			issue.POST("/move", ctrl.Issue.MoveParent)
+			issue.POST("/bulk", ctrl.Issue.Bulk)
+			issue.POST("/:id/approve", ctrl.Issue.Approve)
+			issue.POST("/:id/reject", ctrl.Issue.Reject)
 			issue.DELETE("/parent/:id", ctrl.Issue.RemoveParent)
 			issue.DELETE("/:id", ctrl.Issue.Delete)
+			issue.GET("/:id/watchers", ctrl.Watcher.GetWatchers)
+			issue.POST("/:id/watch", ctrl.Watcher.Watch)
+			issue.DELETE("/:id/watch", ctrl.Watcher.Unwatch)
 
 			comment := issue.Group("/:id/comment")
 			{
@@ -63,15 +95,46 @@ func Api(io *socket.Server, db *gorm.DB, r *gin.RouterGroup) {
 				comment.POST("/create", ctrl.Comment.Create)
 				comment.POST("/:comment_id", ctrl.Comment.Update)
 				comment.DELETE("/:comment_id", ctrl.Comment.Delete)
+				comment.POST("/:comment_id/reactions", ctrl.Comment.React)
+				comment.GET("/:comment_id/history", ctrl.Comment.GetHistory)
+				comment.POST("/:comment_id/restore", ctrl.Comment.Restore)
 			}
 
 			item := issue.Group("/:id/item")
 			{
 				item.GET("", ctrl.Item.GetItems)
 				item.POST("/create", ctrl.Item.Create)
+				item.POST("/upload", attachment.Handler, ctrl.Item.Upload)
 				item.POST("/:item_id", ctrl.Item.Update)
 				item.DELETE("/:item_id", ctrl.Item.Delete)
 			}
+
+			worklog := issue.Group("/:id/worklog")
+			{
+				worklog.GET("", ctrl.Worklog.GetWorklogs)
+				worklog.POST("", ctrl.Worklog.Create)
+				worklog.POST("/estimate", ctrl.Worklog.UpdateEstimate)
+				worklog.POST("/:worklog_id", ctrl.Worklog.Update)
+				worklog.DELETE("/:worklog_id", ctrl.Worklog.Delete)
+			}
+		}
+
+		sprint := auth.Group("/sprint")
+		{
+			sprint.GET("", ctrl.Sprint.GetSprints)
+			sprint.POST("", ctrl.Sprint.Upsert)
+			sprint.POST("/backlog", ctrl.Sprint.MoveIssues)
+			sprint.GET("/:id", ctrl.Sprint.GetSprintByID)
+			sprint.POST("/:id/start", ctrl.Sprint.Start)
+			sprint.POST("/:id/close", ctrl.Sprint.Close)
+			sprint.POST("/:id/issues", ctrl.Sprint.MoveIssues)
+			sprint.DELETE("/:id", ctrl.Sprint.Delete)
+		}
+
+		analytics := auth.Group("/analytics")
+		{
+			analytics.GET("/burndown", ctrl.Analytic.Burndown)
+			analytics.GET("/velocity", ctrl.Analytic.Velocity)
 		}
 
 		report := auth.Group("/report")
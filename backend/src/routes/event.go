@@ -37,9 +37,18 @@ func Event(io *s.Server, db *gorm.DB) {
 
 		socket.On("notification:get", events.Notif.GetByUser)
 		socket.On("notification:read", events.Notif.Read)
+		socket.On("project:join", events.Project.Join)
+		socket.On("project:leave", events.Project.Leave)
+		socket.On("issue:join", events.Issue.Join)
+		socket.On("issue:leave", events.Issue.Leave)
+		socket.On("presence:ping", events.Presence.Ping)
+		socket.On("presence:status", events.Presence.Status)
+		socket.On("presence:focus", events.Presence.Focus)
+		events.Presence.Connect()
 
 		socket.On("disconnect", func(a ...any) {
 			logger.Info("disconnected", socket.Id())
+			events.Presence.Disconnect()
 		})
 	})
 }
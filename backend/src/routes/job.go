@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"context"
+	"time"
+	"webservices/registry"
+	"webservices/src/jobs"
+
+	s "github.com/zishang520/socket.io/v2/socket"
+	"gorm.io/gorm"
+)
+
+func Job(ctx context.Context, io *s.Server, db *gorm.DB) *jobs.Scheduler {
+	repos := registry.NewRepositories(db)
+	services := registry.NewServices(repos, io)
+	job := registry.NewJobs(services)
+
+	scheduler := jobs.NewScheduler()
+	scheduler.Every(5*time.Minute, "issue:reminder", job.Reminder.Run)
+	scheduler.Every(time.Hour, "digest:send", job.Digest.Run)
+	scheduler.Every(15*time.Minute, "issue:recurrence", job.Recurrence.Run)
+	scheduler.Every(24*time.Hour, "comment:purge", job.Comment.Purge)
+	scheduler.Every(time.Minute, "presence:expire", job.Presence.Run)
+
+	scheduler.Start(ctx)
+	return scheduler
+}
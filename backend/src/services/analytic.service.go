@@ -0,0 +1,583 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"time"
+	"webservices/src/model"
+	"webservices/src/pkg/cache"
+	"webservices/src/pkg/common"
+	"webservices/src/pkg/replay"
+	"webservices/src/repo"
+	"webservices/src/types"
+	"webservices/src/types/schemas"
+)
+
+const (
+	analyticDefaultDays = 30
+	analyticMaxDays     = 366
+)
+
+type analyticEntry struct {
+	fingerprint string
+	analytics   *schemas.IssueAnalytics
+}
+
+type AnalyticService struct {
+	analyticRepo *repo.AnalyticRepository
+	sprintRepo   *repo.SprintRepository
+	settingRepo  *repo.ProjectSettingRepository
+	workflowRepo *repo.WorkflowRepository
+	userRepo     *repo.UserRepository
+	cache        *cache.Cache[analyticEntry]
+}
+
+func NewAnalyticService(
+	analyticRepo *repo.AnalyticRepository,
+	sprintRepo *repo.SprintRepository,
+	settingRepo *repo.ProjectSettingRepository,
+	workflowRepo *repo.WorkflowRepository,
+	userRepo *repo.UserRepository,
+) *AnalyticService {
+	return &AnalyticService{
+		analyticRepo: analyticRepo,
+		sprintRepo:   sprintRepo,
+		settingRepo:  settingRepo,
+		workflowRepo: workflowRepo,
+		userRepo:     userRepo,
+		// the issue changes invalidate through the fingerprint, the ttl only evict the idle ranges
+		cache: cache.New[analyticEntry](10*time.Minute, 1000),
+	}
+}
+
+// GetIssueAnalytics aggregate the project issues within the inclusive date range, cached per project.
+// only a change on the issue rows invalidate the cache, the overdue count moving with the time alone
+// is computed on every call
+func (s *AnalyticService) GetIssueAnalytics(userID, projectID string, query schemas.AnalyticQuery) (*schemas.IssueAnalytics, error) {
+	if err := s.userRepo.ValidatePermission(userID,
+		projectID, types.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	from, to, err := dateRange(query.From, query.To, now)
+	if err != nil {
+		return nil, err
+	}
+
+	fingerprint, err := s.analyticRepo.Fingerprint(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	var analytics schemas.IssueAnalytics
+
+	key := fmt.Sprintf("%s:%s:%s", projectID, from.Format(time.DateOnly), to.Format(time.DateOnly))
+	if entry, ok := s.cache.Get(key); ok && entry.fingerprint == fingerprint {
+		analytics = *entry.analytics
+	} else {
+		aggregated, err := s.aggregate(projectID, from, to, now)
+		if err != nil {
+			return nil, err
+		}
+
+		s.cache.Set(key, analyticEntry{fingerprint: fingerprint, analytics: aggregated})
+		analytics = *aggregated
+	}
+
+	if analytics.Overdue, err = s.analyticRepo.CountOverdue(projectID, from, to, now); err != nil {
+		return nil, err
+	}
+
+	return &analytics, nil
+}
+
+func (s *AnalyticService) aggregate(projectID string, from, to, now time.Time) (*schemas.IssueAnalytics, error) {
+	analytics := &schemas.IssueAnalytics{
+		ProjectID: projectID,
+		From:      from,
+		To:        to.AddDate(0, 0, -1),
+		CachedAt:  now,
+	}
+
+	var err error
+	if analytics.ByStatus, err = s.analyticRepo.CountBy(projectID, "status", from, to); err != nil {
+		return nil, err
+	}
+
+	if analytics.ByPriority, err = s.analyticRepo.CountBy(projectID, "priority", from, to); err != nil {
+		return nil, err
+	}
+
+	if analytics.ByType, err = s.analyticRepo.CountBy(projectID, "type", from, to); err != nil {
+		return nil, err
+	}
+
+	if analytics.ByAssignee, err = s.analyticRepo.CountByAssignee(projectID, from, to); err != nil {
+		return nil, err
+	}
+
+	if analytics.Daily, err = s.analyticRepo.Daily(projectID, from, to); err != nil {
+		return nil, err
+	}
+
+	analytics.Resolved, analytics.CycleTime, analytics.LeadTime, err = s.analyticRepo.Durations(projectID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, count := range analytics.ByStatus {
+		analytics.Total += count.Count
+	}
+
+	return analytics, nil
+}
+
+const (
+	measureCount    = "count"
+	measureEstimate = "estimate"
+
+	velocityDefaultSprints = 6
+)
+
+// GetBurndown the remaining work at every day boundary of the sprint or the date range, rebuilt from
+// the activity log so the issues added, removed or reopened along the way are reflected
+func (s *AnalyticService) GetBurndown(userID, projectID string, query schemas.BurndownQuery) (*schemas.Burndown, error) {
+	if err := s.userRepo.ValidatePermission(userID,
+		projectID, types.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	cutoff := now
+
+	var (
+		from, to time.Time
+		sprintID *string
+		err      error
+	)
+
+	if query.SprintID != nil && *query.SprintID != "" {
+		sprint, err := s.sprintRepo.GetByID(*query.SprintID)
+		if err != nil {
+			return nil, err
+		}
+
+		if sprint.ProjectID != projectID {
+			return nil, fmt.Errorf("failed to fetch sprint: record not found")
+		}
+
+		if sprint.StartDate == nil {
+			return nil, fmt.Errorf("invalid date range: sprint '%s' has no start date", sprint.Name)
+		}
+
+		end := now
+		if sprint.EndDate != nil {
+			end = *sprint.EndDate
+		}
+
+		// closing moves the unfinished issues out of the sprint, the chart stop right there
+		if sprint.ClosedAt != nil {
+			cutoff = *sprint.ClosedAt
+			if sprint.EndDate == nil || sprint.ClosedAt.After(end) {
+				end = *sprint.ClosedAt
+			}
+		}
+
+		if from, to, err = dateRange(sprint.StartDate, &end, now); err != nil {
+			return nil, err
+		}
+		sprintID = &sprint.ID
+	} else if from, to, err = dateRange(query.From, query.To, now); err != nil {
+		return nil, err
+	}
+
+	measure, err := s.workMeasure(projectID, query.Measure)
+	if err != nil {
+		return nil, err
+	}
+
+	timeline, workflow, err := s.timeline(projectID, from)
+	if err != nil {
+		return nil, err
+	}
+
+	burndown := &schemas.Burndown{
+		ProjectID: projectID,
+		SprintID:  sprintID,
+		From:      from,
+		To:        to.AddDate(0, 0, -1),
+		Measure:   measure.name,
+		Unit:      measure.unit,
+		Points:    make([]schemas.BurndownPoint, 0),
+	}
+
+	reached := false
+	for at := from; !at.After(to); at = at.AddDate(0, 0, 1) {
+		point := schemas.BurndownPoint{Date: at}
+
+		// the first boundary past the cutoff carry the latest state, the next ones are left empty
+		moment := at
+		if at.After(cutoff) {
+			if reached {
+				burndown.Points = append(burndown.Points, point)
+				continue
+			}
+			reached = true
+			moment = cutoff
+		}
+
+		var remaining, completed int
+		for _, state := range statesAt(timeline, workflow, moment) {
+			if state.Status == string(types.IssueStatusDraft) || !inSprint(state, sprintID) {
+				continue
+			}
+
+			if isDone(workflow, state) {
+				completed += measure.amount(plannedMinutes(state))
+			} else {
+				remaining += measure.amount(common.Coalesce(state.RemainingEstimate, state.OriginalEstimate))
+			}
+		}
+
+		point.Remaining = common.Ptr(measure.value(remaining))
+		point.Scope = common.Ptr(measure.value(remaining + completed))
+		burndown.Points = append(burndown.Points, point)
+	}
+
+	// straight line from the starting remaining work down to zero on the last boundary
+	if len(burndown.Points) > 1 && burndown.Points[0].Remaining != nil {
+		start := *burndown.Points[0].Remaining
+		last := float64(len(burndown.Points) - 1)
+		for i := range burndown.Points {
+			burndown.Points[i].Ideal = math.Round(start*(1-float64(i)/last)*100) / 100
+		}
+	}
+
+	return burndown, nil
+}
+
+// GetVelocity the work committed & completed on the last closed sprints, or the work completed
+// per week of the date range when given
+func (s *AnalyticService) GetVelocity(userID, projectID string, query schemas.VelocityQuery) (*schemas.Velocity, error) {
+	if err := s.userRepo.ValidatePermission(userID,
+		projectID, types.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	measure, err := s.workMeasure(projectID, query.Measure)
+	if err != nil {
+		return nil, err
+	}
+
+	var periods []schemas.VelocityPeriod
+	if query.From != nil || query.To != nil {
+		periods, err = s.weeklyVelocity(projectID, query, measure)
+	} else {
+		periods, err = s.sprintVelocity(projectID, query, measure)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	velocity := &schemas.Velocity{
+		ProjectID: projectID,
+		Measure:   measure.name,
+		Unit:      measure.unit,
+		Periods:   periods,
+	}
+
+	if len(periods) > 0 {
+		var total float64
+		for _, period := range periods {
+			total += period.Completed
+		}
+		velocity.Average = math.Round(total/float64(len(periods))*100) / 100
+	}
+
+	return velocity, nil
+}
+
+func (s *AnalyticService) sprintVelocity(projectID string, query schemas.VelocityQuery, measure *workMeasure) ([]schemas.VelocityPeriod, error) {
+	closed := types.SprintStateClosed
+	sprints, err := s.sprintRepo.GetByProjectID(projectID, &closed)
+	if err != nil {
+		return nil, err
+	}
+
+	periods := make([]schemas.VelocityPeriod, 0)
+	if len(sprints) == 0 {
+		return periods, nil
+	}
+
+	slices.SortStableFunc(sprints, func(a, b model.Sprint) int {
+		return common.Coalesce(a.ClosedAt, &a.UpdatedAt).Compare(*common.Coalesce(b.ClosedAt, &b.UpdatedAt))
+	})
+
+	limit := query.Sprints
+	if limit <= 0 {
+		limit = velocityDefaultSprints
+	}
+
+	if len(sprints) > limit {
+		sprints = sprints[len(sprints)-limit:]
+	}
+
+	since := time.Now()
+	for _, sprint := range sprints {
+		if start := *common.Coalesce(sprint.StartDate, &sprint.CreatedAt); start.Before(since) {
+			since = start
+		}
+	}
+
+	timeline, workflow, err := s.timeline(projectID, since)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, sprint := range sprints {
+		start := *common.Coalesce(sprint.StartDate, &sprint.CreatedAt)
+		end := *common.Coalesce(sprint.ClosedAt, sprint.EndDate, &sprint.UpdatedAt)
+
+		var committed, completed int
+		for _, state := range statesAt(timeline, workflow, start) {
+			if state.Status != string(types.IssueStatusDraft) && inSprint(state, &sprint.ID) {
+				committed += measure.amount(plannedMinutes(state))
+			}
+		}
+
+		for _, state := range statesAt(timeline, workflow, end) {
+			if isDone(workflow, state) && inSprint(state, &sprint.ID) {
+				completed += measure.amount(plannedMinutes(state))
+			}
+		}
+
+		periods = append(periods, schemas.VelocityPeriod{
+			SprintID:  &sprint.ID,
+			Name:      sprint.Name,
+			From:      start,
+			To:        end,
+			Committed: common.Ptr(measure.value(committed)),
+			Completed: measure.value(completed),
+		})
+	}
+
+	return periods, nil
+}
+
+// weeklyVelocity the issues done at the end of the week which were not done at its start
+func (s *AnalyticService) weeklyVelocity(projectID string, query schemas.VelocityQuery, measure *workMeasure) ([]schemas.VelocityPeriod, error) {
+	now := time.Now()
+	from, to, err := dateRange(query.From, query.To, now)
+	if err != nil {
+		return nil, err
+	}
+
+	timeline, workflow, err := s.timeline(projectID, from)
+	if err != nil {
+		return nil, err
+	}
+
+	periods := make([]schemas.VelocityPeriod, 0)
+	for start := from; start.Before(to); start = start.AddDate(0, 0, 7) {
+		end := start.AddDate(0, 0, 7)
+		if end.After(to) {
+			end = to
+		}
+
+		done := make(map[string]bool)
+		for _, state := range statesAt(timeline, workflow, start) {
+			done[state.IssueID] = isDone(workflow, state)
+		}
+
+		moment := end
+		if now.Before(moment) {
+			moment = now
+		}
+
+		var completed int
+		for _, state := range statesAt(timeline, workflow, moment) {
+			if isDone(workflow, state) && !done[state.IssueID] {
+				completed += measure.amount(plannedMinutes(state))
+			}
+		}
+
+		last := end.AddDate(0, 0, -1)
+		periods = append(periods, schemas.VelocityPeriod{
+			Name:      fmt.Sprintf("%s - %s", start.Format(time.DateOnly), last.Format(time.DateOnly)),
+			From:      start,
+			To:        last,
+			Completed: measure.value(completed),
+		})
+	}
+
+	return periods, nil
+}
+
+// timeline the replay of the project issues, able to reconstruct their state at any time after `since`.
+// the workflow resolve the category of the replayed statuses
+func (s *AnalyticService) timeline(projectID string, since time.Time) (*replay.Timeline, *model.Workflow, error) {
+	workflow, err := s.workflowRepo.GetByProjectID(projectID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	issues, err := s.analyticRepo.GetReplayIssues(projectID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	activities, err := s.analyticRepo.GetChangesSince(projectID, since)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	states := make([]replay.State, len(issues))
+	for i, issue := range issues {
+		states[i] = replay.State{
+			IssueID:           issue.ID,
+			CreatedAt:         issue.CreatedAt,
+			Status:            string(issue.Status),
+			SprintID:          issue.SprintID,
+			OriginalEstimate:  issue.OriginalEstimate,
+			RemainingEstimate: issue.RemainingEstimate,
+		}
+
+		if issue.Parents != nil && *issue.Parents != "" {
+			states[i].ParentID = issue.Parents
+		}
+	}
+
+	changes := make([]replay.Change, 0, len(activities))
+	for _, activity := range activities {
+		// closing a sprint moves its unfinished issues at once, logged on the project only
+		if activity.ActivityType == types.SprintEnd {
+			if activity.NewValues == nil {
+				continue
+			}
+
+			values := *activity.NewValues
+			unfinished, _ := values["unfinished"].([]any)
+			for _, ID := range unfinished {
+				if issueID, ok := ID.(string); ok {
+					changes = append(changes, replay.Change{
+						IssueID: issueID,
+						At:      activity.CreatedAt,
+						Old:     map[string]any{replay.KeySprint: values["sprint_id"]},
+					})
+				}
+			}
+			continue
+		}
+
+		if activity.IssueID == nil || activity.OldValues == nil {
+			continue
+		}
+
+		// detaching a subtask is logged on its former parent, the subtask held by `issue_id`
+		issueID := *activity.IssueID
+		if ID, ok := (*activity.OldValues)["issue_id"].(string); ok && activity.ActivityType == types.IssueMove {
+			issueID = ID
+		}
+
+		changes = append(changes, replay.Change{
+			IssueID: issueID,
+			At:      activity.CreatedAt,
+			Old:     *activity.OldValues,
+		})
+	}
+
+	return replay.New(states, changes), workflow, nil
+}
+
+// statesAt the top-level issue states at the time, the subtasks rolled into their parent
+func statesAt(timeline *replay.Timeline, workflow *model.Workflow, at time.Time) []replay.State {
+	return replay.Rollup(timeline.At(at), func(state replay.State) bool {
+		return isDone(workflow, state)
+	})
+}
+
+// workMeasure the amount of work of an issue, 1 when counting the issues or its estimate on the project time unit
+type workMeasure struct {
+	name string
+	unit *types.TimeUnit
+}
+
+func (s *AnalyticService) workMeasure(projectID, name string) (*workMeasure, error) {
+	if name != measureEstimate {
+		return &workMeasure{name: measureCount}, nil
+	}
+
+	setting, err := s.settingRepo.GetByProjectID(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	unit := types.TimeUnitHours
+	if setting.TimeTrackingUnit != nil && *setting.TimeTrackingUnit != "" {
+		unit = *setting.TimeTrackingUnit
+	}
+
+	return &workMeasure{name: measureEstimate, unit: &unit}, nil
+}
+
+func (m *workMeasure) amount(minutes *int) int {
+	if m.unit == nil {
+		return 1
+	}
+
+	if minutes == nil {
+		return 0
+	}
+
+	return *minutes
+}
+
+func (m *workMeasure) value(amount int) float64 {
+	if m.unit == nil {
+		return float64(amount)
+	}
+
+	return m.unit.FromMinutes(amount)
+}
+
+func isDone(workflow *model.Workflow, state replay.State) bool {
+	return workflow.IsDone(types.IssueStatus(state.Status))
+}
+
+func inSprint(state replay.State, sprintID *string) bool {
+	return sprintID == nil || (state.SprintID != nil && *state.SprintID == *sprintID)
+}
+
+// plannedMinutes the estimated work of the issue, the remaining one when it never had an original estimate
+func plannedMinutes(state replay.State) *int {
+	return common.Coalesce(state.OriginalEstimate, state.RemainingEstimate)
+}
+
+// dateRange the [from, to) days of the inclusive dates, default the last `analyticDefaultDays` days
+func dateRange(fromDate, toDate *time.Time, now time.Time) (time.Time, time.Time, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	to := today
+	if toDate != nil {
+		to = time.Date(toDate.Year(), toDate.Month(), toDate.Day(), 0, 0, 0, 0, now.Location())
+	}
+	to = to.AddDate(0, 0, 1) // exclusive
+
+	from := to.AddDate(0, 0, -analyticDefaultDays)
+	if fromDate != nil {
+		from = time.Date(fromDate.Year(), fromDate.Month(), fromDate.Day(), 0, 0, 0, 0, now.Location())
+	}
+
+	if !from.Before(to) {
+		return from, to, fmt.Errorf("invalid date range: from must be before to")
+	}
+
+	if to.Sub(from) > analyticMaxDays*24*time.Hour {
+		return from, to, fmt.Errorf("invalid date range: at most %d days", analyticMaxDays)
+	}
+
+	return from, to, nil
+}
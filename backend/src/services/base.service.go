@@ -1,6 +1,12 @@
 package services
 
-import "github.com/zishang520/socket.io/v2/socket"
+import (
+	"time"
+	"webservices/src/model"
+	"webservices/src/types"
+
+	"github.com/zishang520/socket.io/v2/socket"
+)
 
 type baseService struct {
 	io *socket.Server
@@ -17,3 +23,57 @@ func (s *baseService) emit(room, event string, args any) {
 func (s *baseService) broadcast(event string, args ...any) {
 	s.io.Sockets().Emit(event, args)
 }
+
+// Delta the realtime change of a record, the clients apply it by `ID` without refetching
+type Delta struct {
+	Type      types.SyncEvent `json:"type"`
+	ProjectID string          `json:"projectId"`
+	IssueID   string          `json:"issueId"`
+	ID        string          `json:"id" comment:"the changed record, the issue itself on the issue events"`
+	UserID    string          `json:"userId" comment:"the actor, its own clients already applied the change"`
+	Data      any             `json:"data,omitempty" comment:"the record after the change, empty on delete"`
+	At        time.Time       `json:"at"`
+}
+
+// publish push the delta to the project board & the issue viewers, once per socket
+func (s *baseService) publish(delta Delta) {
+	if s.io == nil || delta.ProjectID == "" {
+		return
+	}
+
+	rooms := []socket.Room{socket.Room(ProjectRoom(delta.ProjectID))}
+	if delta.IssueID != "" {
+		rooms = append(rooms, socket.Room(IssueRoom(delta.IssueID)))
+	}
+
+	delta.At = time.Now()
+	s.io.To(rooms...).Emit(delta.Type.String(), delta)
+}
+
+// publishIssue push the issue change to its board & viewers. The subtasks of a deleted
+// top-level issue are removed along, the clients drop them without their own delta
+func (s *baseService) publishIssue(event types.SyncEvent, userID string, issue *model.Issue) {
+	delta := Delta{
+		Type:      event,
+		ProjectID: issue.ProjectID,
+		IssueID:   issue.ID,
+		ID:        issue.ID,
+		UserID:    userID,
+	}
+
+	if event != types.SyncIssueDelete {
+		delta.Data = issue
+	}
+
+	s.publish(delta)
+}
+
+// ProjectRoom the socket room of the members viewing the project board
+func ProjectRoom(projectID string) string {
+	return "project:" + projectID
+}
+
+// IssueRoom the socket room of the users viewing the issue
+func IssueRoom(issueID string) string {
+	return "issue:" + issueID
+}
@@ -2,49 +2,141 @@ package services
 
 import (
 	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
 	"webservices/src/model"
+	"webservices/src/pkg/common"
+	"webservices/src/pkg/mention"
+	"webservices/src/pkg/pagination"
 	"webservices/src/repo"
 	"webservices/src/types"
 
+	"github.com/zishang520/socket.io/v2/socket"
 	"gorm.io/datatypes"
 	"gorm.io/gorm"
 )
 
+const (
+	// maxReactionEmojis the distinct emojis a comment holds
+	maxReactionEmojis = 20
+	// commentRetention how long a deleted comment can be restored, its message purged after
+	commentRetention = 30 * 24 * time.Hour
+)
+
 type CommentService struct {
-	userRepo     *repo.UserRepository
-	commentRepo  *repo.CommentRepository
-	issueRepo    *repo.IssueRepository
-	activityRepo *repo.ActivityRepository
+	*baseService
+	userRepo        *repo.UserRepository
+	commentRepo     *repo.CommentRepository
+	issueRepo       *repo.IssueRepository
+	activityRepo    *repo.ActivityRepository
+	watcherRepo     *repo.WatcherRepository
+	userProjectRepo *repo.UserProjectRepository
 }
 
 func NewCommentService(
+	io *socket.Server,
 	userRepo *repo.UserRepository,
 	commentRepo *repo.CommentRepository,
 	issueRepo *repo.IssueRepository,
 	activityRepo *repo.ActivityRepository,
+	watcherRepo *repo.WatcherRepository,
+	userProjectRepo *repo.UserProjectRepository,
 ) *CommentService {
 	return &CommentService{
-		userRepo:     userRepo,
-		commentRepo:  commentRepo,
-		issueRepo:    issueRepo,
-		activityRepo: activityRepo,
+		baseService:     newBaseService(io),
+		userRepo:        userRepo,
+		commentRepo:     commentRepo,
+		issueRepo:       issueRepo,
+		activityRepo:    activityRepo,
+		watcherRepo:     watcherRepo,
+		userProjectRepo: userProjectRepo,
 	}
 }
 
-func (s *CommentService) GetByIssue(issueID string) ([]model.Comment, error) {
-	return s.commentRepo.GetByIssueID(issueID)
+// GetByIssue the page of the top-level comments, each holding its replies tree & the reaction counts
+func (s *CommentService) GetByIssue(issueID string, params pagination.Params) (*pagination.Page[model.Comment], error) {
+	page, err := s.commentRepo.GetByIssueID(issueID, params)
+	if err != nil {
+		return nil, err
+	}
+
+	IDs := common.Map(page.Items, func(c model.Comment) string { return c.ID })
+	replies, err := s.commentRepo.GetByThreadIDs(IDs)
+	if err != nil {
+		return nil, err
+	}
+
+	children := make(map[string][]model.Comment)
+	for _, reply := range replies {
+		IDs = append(IDs, reply.ID)
+		children[*reply.ParentID] = append(children[*reply.ParentID], reply)
+	}
+
+	reactions, err := s.commentRepo.GetReactions(IDs)
+	if err != nil {
+		return nil, err
+	}
+	summaries := summarizeReactions(reactions)
+
+	var attach func(comment *model.Comment)
+	attach = func(comment *model.Comment) {
+		comment.Reactions = summaries[comment.ID]
+		comment.Replies = children[comment.ID]
+		comment.Redact()
+		for i := range comment.Replies {
+			attach(&comment.Replies[i])
+		}
+	}
+
+	for i := range page.Items {
+		attach(&page.Items[i])
+	}
+
+	return page, nil
 }
 
-func (s *CommentService) Create(userID, issueID, message string) (*model.Comment, error) {
+// Create the `parentID` reply to the comment of the same issue
+func (s *CommentService) Create(userID, issueID, message string, parentID *string) (*model.Comment, error) {
 	issue, err := s.issueRepo.GetByID(issueID)
 	if err != nil {
 		return nil, err
 	}
 
+	var threadID *string
+	if parentID != nil && *parentID != "" {
+		parent, err := s.commentRepo.GetByID(*parentID)
+		if err != nil {
+			return nil, err
+		}
+
+		if parent.IssueID != issue.ID {
+			return nil, fmt.Errorf("failed to fetch comment: record not found")
+		}
+
+		if parent.DeletedAt != nil {
+			return nil, fmt.Errorf("cannot reply to a deleted comment")
+		}
+
+		threadID = common.Coalesce(parent.ThreadID, &parent.ID)
+	} else {
+		parentID = nil
+	}
+
+	mentions, err := resolveMentions(s.userProjectRepo, s.userRepo, issue.ProjectID, userID, message, "")
+	if err != nil {
+		return nil, err
+	}
+
 	comment := model.Comment{
-		UserID:  userID,
-		IssueID: issue.ID,
-		Message: message,
+		UserID:   userID,
+		IssueID:  issue.ID,
+		ParentID: parentID,
+		ThreadID: threadID,
+		Message:  message,
+		Mentions: mentions,
 	}
 
 	err = s.commentRepo.DB().Transaction(func(tx *gorm.DB) error {
@@ -58,6 +150,10 @@ func (s *CommentService) Create(userID, issueID, message string) (*model.Comment
 		}
 		comment.User = *user
 
+		if err := s.watcherRepo.AddTx(tx, issue.ID, &userID); err != nil {
+			return err
+		}
+
 		activity := model.RecentActivity{
 			UserID:       userID,
 			ProjectID:    &issue.ProjectID,
@@ -68,6 +164,7 @@ func (s *CommentService) Create(userID, issueID, message string) (*model.Comment
 				"message":     comment.Message,
 				"author_id":   userID,
 				"issue_title": issue.Title,
+				"parent_id":   comment.ParentID,
 			},
 		}
 
@@ -79,6 +176,7 @@ func (s *CommentService) Create(userID, issueID, message string) (*model.Comment
 	}
 
 	s.issueRepo.Heartbeat(issue.ID)
+	s.publishComment(types.SyncCommentCreate, userID, issue.ProjectID, &comment)
 
 	return &comment, nil
 }
@@ -89,11 +187,35 @@ func (s *CommentService) Update(ID, userID, message string) (*model.Comment, err
 		return nil, err
 	}
 
+	if comment.DeletedAt != nil {
+		return nil, fmt.Errorf("failed to fetch comment: record not found")
+	}
+
 	if comment.UserID != userID {
 		return nil, fmt.Errorf("you can only update your own comments")
 	}
 
+	prev := comment.Message
+	if message == prev {
+		return comment, nil
+	}
+
+	// only the newly mentioned users notified on edit
+	comment.Mentions, err = resolveMentions(s.userProjectRepo, s.userRepo, comment.Issue.ProjectID, userID, message, prev)
+	if err != nil {
+		return nil, err
+	}
+
 	err = s.commentRepo.DB().Transaction(func(tx *gorm.DB) error {
+		revision := model.CommentRevision{
+			CommentID: comment.ID,
+			UserID:    userID,
+			Message:   prev,
+		}
+		if err := s.commentRepo.CreateRevisionTx(tx, &revision); err != nil {
+			return err
+		}
+
 		comment.Message = message
 		if err := s.commentRepo.UpdateTx(tx, comment); err != nil {
 			return err
@@ -105,7 +227,7 @@ func (s *CommentService) Update(ID, userID, message string) (*model.Comment, err
 			IssueID:      &comment.Issue.ID,
 			CommentID:    &comment.ID,
 			ActivityType: types.CommentUpdate,
-			OldValues:    &datatypes.JSONMap{"message": comment.Message},
+			OldValues:    &datatypes.JSONMap{"message": prev},
 			NewValues:    &datatypes.JSONMap{"message": message},
 		}
 
@@ -116,15 +238,22 @@ func (s *CommentService) Update(ID, userID, message string) (*model.Comment, err
 		return nil, err
 	}
 
+	s.publishComment(types.SyncCommentUpdate, userID, comment.Issue.ProjectID, comment)
+
 	return comment, nil
 }
 
+// Delete leave the "comment deleted" placeholder, the admins can restore it within the `commentRetention`
 func (s *CommentService) Delete(ID, userID string) (*model.Comment, error) {
 	comment, err := s.commentRepo.GetByID(ID)
 	if err != nil {
 		return nil, err
 	}
 
+	if comment.DeletedAt != nil {
+		return nil, fmt.Errorf("failed to fetch comment: record not found")
+	}
+
 	if comment.UserID != userID {
 		return nil, fmt.Errorf("you can only delete your own comments")
 	}
@@ -148,12 +277,275 @@ func (s *CommentService) Delete(ID, userID string) (*model.Comment, error) {
 			return err
 		}
 
-		return s.commentRepo.DeleteTx(tx, comment.ID)
+		return s.commentRepo.SoftDeleteTx(tx, comment, userID)
 	})
 
 	if err != nil {
 		return nil, err
 	}
 
+	comment.Redact()
+	s.publishComment(types.SyncCommentDelete, userID, comment.Issue.ProjectID, comment)
+
 	return comment, nil
 }
+
+// Restore the deleted comment, admin only
+func (s *CommentService) Restore(userID, issueID, ID string) (*model.Comment, error) {
+	comment, err := s.commentRepo.GetByID(ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if comment.IssueID != issueID {
+		return nil, fmt.Errorf("failed to fetch comment: record not found")
+	}
+
+	if err := s.userRepo.ValidatePermission(userID,
+		comment.Issue.ProjectID, types.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	if comment.DeletedAt == nil {
+		return nil, fmt.Errorf("comment is not deleted")
+	}
+
+	if time.Since(*comment.DeletedAt) > commentRetention {
+		return nil, fmt.Errorf("comment can no longer be restored, deleted over %d days ago",
+			int(commentRetention.Hours()/24))
+	}
+
+	err = s.commentRepo.DB().Transaction(func(tx *gorm.DB) error {
+		if err := s.commentRepo.RestoreTx(tx, comment); err != nil {
+			return err
+		}
+
+		activity := model.RecentActivity{
+			UserID:       userID,
+			ProjectID:    &comment.Issue.ProjectID,
+			IssueID:      &comment.Issue.ID,
+			CommentID:    &comment.ID,
+			ActivityType: types.CommentRestore,
+			NewValues: &datatypes.JSONMap{
+				"message":     comment.Message,
+				"issue_title": comment.Issue.Title,
+				"author_id":   comment.UserID,
+			},
+		}
+
+		return s.activityRepo.CreateTx(tx, &activity)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.publishComment(types.SyncCommentUpdate, userID, comment.Issue.ProjectID, comment)
+
+	return comment, nil
+}
+
+// GetHistory the messages replaced by the edits, newest first.
+// The history of a deleted comment kept to the admins
+func (s *CommentService) GetHistory(userID, issueID, ID string) ([]model.CommentRevision, error) {
+	comment, err := s.commentRepo.GetByID(ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if comment.IssueID != issueID {
+		return nil, fmt.Errorf("failed to fetch comment: record not found")
+	}
+
+	role := types.RoleViewer
+	if comment.DeletedAt != nil {
+		role = types.RoleAdmin
+	}
+
+	if err := s.userRepo.ValidatePermission(userID, comment.Issue.ProjectID, role); err != nil {
+		return nil, err
+	}
+
+	return s.commentRepo.GetRevisions(comment.ID)
+}
+
+// Purge erase the comments deleted past the `commentRetention`
+func (s *CommentService) Purge(now time.Time) (int64, error) {
+	return s.commentRepo.PurgeDeleted(now.Add(-commentRetention))
+}
+
+// React toggle the user emoji on the comment, the new counts pushed to the users viewing the issue
+func (s *CommentService) React(userID, issueID, ID, emoji string) ([]model.ReactionSummary, error) {
+	comment, err := s.commentRepo.GetByID(ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if comment.IssueID != issueID || comment.DeletedAt != nil {
+		return nil, fmt.Errorf("failed to fetch comment: record not found")
+	}
+
+	if err := s.userRepo.ValidatePermission(userID,
+		comment.Issue.ProjectID, types.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	if !validEmoji(emoji) {
+		return nil, fmt.Errorf("invalid reaction: %q is not an emoji", emoji)
+	}
+
+	current, err := s.commentRepo.GetReactions([]string{comment.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := summarizeReactions(current)[comment.ID]
+	if len(summaries) >= maxReactionEmojis &&
+		!slices.ContainsFunc(summaries, func(r model.ReactionSummary) bool { return r.Emoji == emoji }) {
+		return nil, fmt.Errorf("invalid reaction: a comment holds up to %d emojis", maxReactionEmojis)
+	}
+
+	var added bool
+	if err := s.commentRepo.DB().Transaction(func(tx *gorm.DB) (err error) {
+		added, err = s.commentRepo.ToggleReactionTx(tx, &model.CommentReaction{
+			CommentID: comment.ID,
+			UserID:    userID,
+			Emoji:     emoji,
+		})
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	reactions, err := s.commentRepo.GetReactions([]string{comment.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	summaries = summarizeReactions(reactions)[comment.ID]
+	if summaries == nil {
+		summaries = make([]model.ReactionSummary, 0)
+	}
+
+	s.emit(IssueRoom(issueID), "comment:reaction", map[string]any{
+		"issueId":   issueID,
+		"commentId": comment.ID,
+		"userId":    userID,
+		"emoji":     emoji,
+		"added":     added,
+		"reactions": summaries,
+	})
+
+	return summaries, nil
+}
+
+// publishComment push the comment change to the board & the issue viewers,
+// a deleted comment sent as its placeholder
+func (s *CommentService) publishComment(event types.SyncEvent, userID, projectID string, comment *model.Comment) {
+	s.publish(Delta{
+		Type:      event,
+		ProjectID: projectID,
+		IssueID:   comment.IssueID,
+		ID:        comment.ID,
+		UserID:    userID,
+		Data:      comment,
+	})
+}
+
+// summarizeReactions the reactions grouped by comment then emoji, in the order first reacted
+func summarizeReactions(reactions []model.CommentReaction) map[string][]model.ReactionSummary {
+	result := make(map[string][]model.ReactionSummary)
+	for _, reaction := range reactions {
+		summaries := result[reaction.CommentID]
+		i := slices.IndexFunc(summaries, func(r model.ReactionSummary) bool { return r.Emoji == reaction.Emoji })
+		if i < 0 {
+			summaries = append(summaries, model.ReactionSummary{Emoji: reaction.Emoji})
+			i = len(summaries) - 1
+		}
+
+		summaries[i].Count++
+		summaries[i].UserIDs = append(summaries[i].UserIDs, reaction.UserID)
+		result[reaction.CommentID] = summaries
+	}
+
+	return result
+}
+
+// validEmoji a single emoji, possibly a sequence joined by ZWJ with its modifiers & variation selectors
+func validEmoji(value string) bool {
+	if value == "" || utf8.RuneCountInString(value) > 10 {
+		return false
+	}
+
+	symbol := false
+	for _, r := range value {
+		switch {
+		case unicode.Is(unicode.So, r):
+			symbol = true
+		case r == 0x200D, // zero width joiner
+			r >= 0xFE00 && r <= 0xFE0F,   // variation selectors
+			r >= 0x1F3FB && r <= 0x1F3FF, // skin tones
+			r >= 0xE0020 && r <= 0xE007F, // tag sequences, e.g. the subdivision flags
+			r == 0x20E3:                  // keycap
+		default:
+			return false
+		}
+	}
+
+	return symbol
+}
+
+// resolveMentions the members mentioned in the text (see mention.Parse), the author left out.
+// the handles already in `prev` are skipped so an edit only reach the newly mentioned users.
+// a handle matching no member, or several, is refused
+func resolveMentions(
+	userProjectRepo *repo.UserProjectRepository,
+	userRepo *repo.UserRepository,
+	projectID, authorID, text, prev string,
+) ([]string, error) {
+	mentioned := make([]string, 0)
+	known := mention.Parse(prev)
+
+	handles := make([]string, 0)
+	for _, handle := range mention.Parse(text) {
+		if !slices.ContainsFunc(known, func(k string) bool { return strings.EqualFold(k, handle) }) {
+			handles = append(handles, handle)
+		}
+	}
+
+	if len(handles) == 0 {
+		return mentioned, nil
+	}
+
+	IDs, err := userProjectRepo.GetUserIDs(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := userRepo.GetByIDs(IDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, handle := range handles {
+		matches := make([]string, 0, 1)
+		for _, member := range members {
+			if mention.Match(handle, member.Name, member.Email) {
+				matches = append(matches, member.ID)
+			}
+		}
+
+		switch {
+		case len(matches) == 0:
+			return nil, fmt.Errorf("invalid mention: @%s is not a project member", handle)
+		case len(matches) > 1:
+			return nil, fmt.Errorf("invalid mention: @%s matches several members, mention the email instead", handle)
+		}
+
+		if matches[0] != authorID && !slices.Contains(mentioned, matches[0]) {
+			mentioned = append(mentioned, matches[0])
+		}
+	}
+
+	return mentioned, nil
+}
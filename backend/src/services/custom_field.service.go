@@ -0,0 +1,280 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+	"webservices/src/model"
+	"webservices/src/repo"
+	"webservices/src/types"
+	"webservices/src/types/schemas"
+
+	"gorm.io/gorm"
+)
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+const (
+	maxCustomFields    = 50
+	maxCustomFieldText = 2000
+)
+
+type CustomFieldService struct {
+	fieldRepo *repo.CustomFieldRepository
+	userRepo  *repo.UserRepository
+}
+
+func NewCustomFieldService(
+	fieldRepo *repo.CustomFieldRepository,
+	userRepo *repo.UserRepository,
+) *CustomFieldService {
+	return &CustomFieldService{
+		fieldRepo: fieldRepo,
+		userRepo:  userRepo,
+	}
+}
+
+func (s *CustomFieldService) GetByProject(userID, projectID string) ([]model.CustomField, error) {
+	if err := s.userRepo.ValidatePermission(userID,
+		projectID, types.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	return s.fieldRepo.GetByProjectID(projectID)
+}
+
+func (s *CustomFieldService) Create(userID, projectID string, value schemas.CreateCustomField) (*model.CustomField, error) {
+	if err := s.userRepo.ValidatePermission(userID,
+		projectID, types.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	fields, err := s.fieldRepo.GetByProjectID(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(fields) >= maxCustomFields {
+		return nil, fmt.Errorf("invalid custom field: a project holds up to %d fields", maxCustomFields)
+	}
+
+	field := model.CustomField{
+		ProjectID: projectID,
+		Key:       strings.ToLower(strings.TrimSpace(value.Key)),
+		Name:      strings.TrimSpace(value.Name),
+		Type:      value.Type,
+		Required:  value.Required,
+		Position:  len(fields),
+	}
+
+	if !statusKeyPattern.MatchString(field.Key) {
+		return nil, fmt.Errorf("invalid custom field: key %q must be lowercase letters, digits or underscores", value.Key)
+	}
+
+	for _, f := range fields {
+		if f.Key == field.Key {
+			return nil, fmt.Errorf("custom field %q already exists", field.Key)
+		}
+	}
+
+	if field.Options, err = fieldOptions(field.Type, value.Options); err != nil {
+		return nil, err
+	}
+
+	if err := s.fieldRepo.DB().Transaction(func(tx *gorm.DB) error {
+		return s.fieldRepo.CreateTx(tx, &field)
+	}); err != nil {
+		return nil, err
+	}
+
+	return &field, nil
+}
+
+// Update the options still picked by some issues can't be removed
+func (s *CustomFieldService) Update(userID, projectID, ID string, value schemas.UpdateCustomField) (*model.CustomField, error) {
+	field, err := s.get(projectID, ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.ValidatePermission(userID,
+		field.ProjectID, types.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	options, err := fieldOptions(field.Type, value.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	removed := make([]string, 0)
+	for _, option := range field.Options {
+		if !slices.Contains(options, option) {
+			removed = append(removed, option)
+		}
+	}
+
+	count, err := s.fieldRepo.CountOptionUsage(field, removed)
+	if err != nil {
+		return nil, err
+	}
+
+	if count > 0 {
+		return nil, fmt.Errorf("invalid custom field: %d issues still use the removed options", count)
+	}
+
+	field.Name = strings.TrimSpace(value.Name)
+	field.Options = options
+	field.Required = value.Required
+	if value.Position != nil {
+		field.Position = *value.Position
+	}
+
+	if err := s.fieldRepo.DB().Transaction(func(tx *gorm.DB) error {
+		return s.fieldRepo.UpdateTx(tx, field)
+	}); err != nil {
+		return nil, err
+	}
+
+	return field, nil
+}
+
+// Delete remove the field & the values stored on the issues
+func (s *CustomFieldService) Delete(userID, projectID, ID string) error {
+	field, err := s.get(projectID, ID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.userRepo.ValidatePermission(userID,
+		field.ProjectID, types.RoleAdmin); err != nil {
+		return err
+	}
+
+	return s.fieldRepo.DB().Transaction(func(tx *gorm.DB) error {
+		return s.fieldRepo.DeleteTx(tx, field)
+	})
+}
+
+func (s *CustomFieldService) get(projectID, ID string) (*model.CustomField, error) {
+	field, err := s.fieldRepo.GetByID(ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if field.ProjectID != projectID {
+		return nil, fmt.Errorf("failed to fetch custom field: record not found")
+	}
+
+	return field, nil
+}
+
+// fieldOptions trim & dedupe the options, required by the select types and rejected by the others
+func fieldOptions(fieldType types.CustomFieldType, values []string) ([]string, error) {
+	options := make([]string, 0, len(values))
+	if !fieldType.HasOptions() {
+		if len(values) > 0 {
+			return nil, fmt.Errorf("invalid custom field: %s field has no options", fieldType)
+		}
+		return options, nil
+	}
+
+	seen := make(map[string]bool)
+	for _, value := range values {
+		option := strings.TrimSpace(value)
+		if option != "" && !seen[option] {
+			seen[option] = true
+			options = append(options, option)
+		}
+	}
+
+	if len(options) == 0 {
+		return nil, fmt.Errorf("invalid custom field: %s field requires options", fieldType)
+	}
+
+	return options, nil
+}
+
+// fieldValue validate the value against the field type, nil returned for the empty value clearing the field.
+// the user fields only checked being an uuid here, the membership left to the caller
+func fieldValue(field *model.CustomField, value any) (any, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	invalid := func(expected string) error {
+		return fmt.Errorf("invalid custom field %q: expected %s", field.Key, expected)
+	}
+
+	switch field.Type {
+	case types.FieldNumber:
+		number, ok := value.(float64)
+		if !ok {
+			return nil, invalid("a number")
+		}
+		return number, nil
+
+	case types.FieldMultiSelect:
+		items, ok := value.([]any)
+		if !ok {
+			return nil, invalid("a list of options")
+		}
+
+		picked := make([]string, 0, len(items))
+		for _, item := range items {
+			option, ok := item.(string)
+			if !ok || !field.HasOption(strings.TrimSpace(option)) {
+				return nil, invalid("one of: " + strings.Join(field.Options, ", "))
+			}
+
+			if option = strings.TrimSpace(option); !slices.Contains(picked, option) {
+				picked = append(picked, option)
+			}
+		}
+
+		if len(picked) == 0 {
+			return nil, nil
+		}
+		return picked, nil
+	}
+
+	text, ok := value.(string)
+	if !ok {
+		return nil, invalid("a text")
+	}
+
+	if text = strings.TrimSpace(text); text == "" {
+		return nil, nil
+	}
+
+	switch field.Type {
+	case types.FieldSelect:
+		if !field.HasOption(text) {
+			return nil, invalid("one of: " + strings.Join(field.Options, ", "))
+		}
+
+	case types.FieldDate:
+		date, err := time.Parse("2006-01-02", text)
+		if err != nil {
+			if date, err = time.Parse(time.RFC3339, text); err != nil {
+				return nil, invalid("a date YYYY-MM-DD")
+			}
+		}
+		return date.Format("2006-01-02"), nil
+
+	case types.FieldUser:
+		if !uuidPattern.MatchString(text) {
+			return nil, invalid("a user id")
+		}
+
+	default:
+		if utf8.RuneCountInString(text) > maxCustomFieldText {
+			return nil, invalid(fmt.Sprintf("at most %d characters", maxCustomFieldText))
+		}
+	}
+
+	return text, nil
+}
@@ -0,0 +1,228 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+	"time"
+	"webservices/src/model"
+	c "webservices/src/pkg/common"
+	"webservices/src/pkg/logger"
+	"webservices/src/repo"
+
+	"gorm.io/gorm"
+)
+
+//go:embed templates/digest.html templates/digest.txt
+var digestTemplates embed.FS
+
+// Digest the per-user daily summary of a project
+type Digest struct {
+	Project   model.Project
+	User      model.User
+	Date      time.Time
+	DueToday  []model.Issue
+	Overdue   []model.Issue
+	Completed []model.Issue // yesterday
+	Comments  []model.Comment
+}
+
+func (d *Digest) IsEmpty() bool {
+	return len(d.DueToday) == 0 &&
+		len(d.Overdue) == 0 &&
+		len(d.Completed) == 0 &&
+		len(d.Comments) == 0
+}
+
+type DigestService struct {
+	projectRepo *repo.ProjectRepository
+	issueRepo   *repo.IssueRepository
+	commentRepo *repo.CommentRepository
+	digestRepo  *repo.DigestLogRepository
+	mail        *MailService
+	html        *htmltemplate.Template
+	text        *texttemplate.Template
+}
+
+func NewDigestService(
+	projectRepo *repo.ProjectRepository,
+	issueRepo *repo.IssueRepository,
+	commentRepo *repo.CommentRepository,
+	digestRepo *repo.DigestLogRepository,
+	mail *MailService,
+) *DigestService {
+	s := &DigestService{
+		projectRepo: projectRepo,
+		issueRepo:   issueRepo,
+		commentRepo: commentRepo,
+		digestRepo:  digestRepo,
+		mail:        mail,
+	}
+
+	funcs := map[string]any{
+		"date":     s.formatDate,
+		"link":     s.link,
+		"truncate": func(v string) string { return c.Truncate(v, 120) },
+		"year":     func() int { return time.Now().Year() },
+		"app":      func() string { return s.mail.AppName },
+	}
+
+	s.html = htmltemplate.Must(htmltemplate.New("digest.html").
+		Funcs(funcs).
+		ParseFS(digestTemplates, "templates/digest.html"))
+
+	s.text = texttemplate.Must(texttemplate.New("digest.txt").
+		Funcs(funcs).
+		ParseFS(digestTemplates, "templates/digest.txt"))
+
+	return s
+}
+
+// Build collect the digest of every member on the projects having `DailyDigest` on,
+// the empty digest are skipped
+func (s *DigestService) Build(now time.Time) ([]Digest, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	tomorrow := today.AddDate(0, 0, 1)
+	yesterday := today.AddDate(0, 0, -1)
+
+	projects, err := s.projectRepo.GetWithDailyDigest()
+	if err != nil {
+		return nil, err
+	}
+
+	digests := make([]Digest, 0)
+	for _, project := range projects {
+		completed, err := s.issueRepo.GetCompletedBetween(project.ID, yesterday, today)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, user := range project.Users {
+			digest := Digest{
+				Project:   project,
+				User:      user,
+				Date:      today,
+				Completed: completed,
+			}
+
+			digest.DueToday, err = s.issueRepo.GetAssignedDue(project.ID, user.ID, &today, tomorrow)
+			if err != nil {
+				return nil, err
+			}
+
+			digest.Overdue, err = s.issueRepo.GetAssignedDue(project.ID, user.ID, nil, today)
+			if err != nil {
+				return nil, err
+			}
+
+			digest.Comments, err = s.commentRepo.GetWatchedSince(project.ID, user.ID, now.Add(-24*time.Hour))
+			if err != nil {
+				return nil, err
+			}
+
+			if digest.IsEmpty() {
+				continue
+			}
+
+			digests = append(digests, digest)
+		}
+	}
+
+	return digests, nil
+}
+
+// Render the digest into the mail with html & plain-text bodies
+func (s *DigestService) Render(digest *Digest) (*MailOptions, error) {
+	var html, text bytes.Buffer
+
+	if err := s.html.Execute(&html, digest); err != nil {
+		return nil, fmt.Errorf("failed to execute html template: %w", err)
+	}
+
+	if err := s.text.Execute(&text, digest); err != nil {
+		return nil, fmt.Errorf("failed to execute plain text template: %w", err)
+	}
+
+	from := fmt.Sprintf(
+		"%s Notifications <noreply@%s>", s.mail.AppName,
+		strings.ToLower(strings.ReplaceAll(s.mail.AppName, " ", "")),
+	)
+
+	return &MailOptions{
+		From:     from,
+		To:       []string{digest.User.Email},
+		Subject:  fmt.Sprintf("📰 Daily digest: %s [%s]", digest.Project.Name, s.formatDate(digest.Date)),
+		Body:     strings.TrimSpace(text.String()),
+		HTMLBody: html.String(),
+	}, nil
+}
+
+// Send deliver today digests, the already sent one on the day is skipped so it safe to call repeatedly.
+// returning the number of mail sent
+func (s *DigestService) Send(ctx context.Context, now time.Time) (int, error) {
+	digests, err := s.Build(now)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for i := range digests {
+		if err := ctx.Err(); err != nil {
+			return sent, err
+		}
+
+		digest := &digests[i]
+		options, err := s.Render(digest)
+		if err != nil {
+			return sent, err
+		}
+
+		delivered := false
+		// mail sent in the transaction, so the failed one retried on the next run
+		err = s.digestRepo.DB().Transaction(func(tx *gorm.DB) error {
+			created, err := s.digestRepo.CreateTx(tx, &model.DigestLog{
+				UserID:    digest.User.ID,
+				ProjectID: digest.Project.ID,
+				Date:      digest.Date,
+			})
+			if err != nil || !created {
+				return err
+			}
+
+			delivered = true
+			return s.mail.send(*options)
+		})
+
+		if err != nil {
+			logger.Errorf("failed to send digest user_id=%s project_id=%s: %s",
+				digest.User.ID, digest.Project.ID, err)
+			continue
+		}
+
+		if delivered {
+			sent++
+		}
+	}
+
+	return sent, nil
+}
+
+func (s *DigestService) link(issueID string) string {
+	return fmt.Sprintf("%s/issue/%s", s.mail.BaseUrl, issueID)
+}
+
+func (s *DigestService) formatDate(value any) string {
+	switch v := value.(type) {
+	case time.Time:
+		return v.Format("January 2, 2006")
+	case *time.Time:
+		if v != nil {
+			return v.Format("January 2, 2006")
+		}
+	}
+	return ""
+}
@@ -2,61 +2,219 @@ package services
 
 import (
 	"fmt"
+	"maps"
 	"math/rand"
+	"slices"
+	"strings"
 	"time"
 	"webservices/src/model"
 	"webservices/src/pkg/common"
+	"webservices/src/pkg/jql"
+	"webservices/src/pkg/logger"
+	"webservices/src/pkg/pagination"
 	"webservices/src/repo"
 	"webservices/src/types"
 	"webservices/src/types/schemas"
 
+	"github.com/zishang520/socket.io/v2/socket"
 	"gorm.io/datatypes"
 	"gorm.io/gorm"
 )
 
 type IssueService struct {
-	issueRepo    *repo.IssueRepository
-	userRepo     *repo.UserRepository
-	projectRepo  *repo.ProjectRepository
-	activityRepo *repo.ActivityRepository
+	*baseService
+	issueRepo       *repo.IssueRepository
+	userRepo        *repo.UserRepository
+	projectRepo     *repo.ProjectRepository
+	activityRepo    *repo.ActivityRepository
+	sprintRepo      *repo.SprintRepository
+	itemRepo        *repo.IssueItemRepository
+	labelRepo       *repo.LabelRepository
+	workflowRepo    *repo.WorkflowRepository
+	fieldRepo       *repo.CustomFieldRepository
+	watcherRepo     *repo.WatcherRepository
+	userProjectRepo *repo.UserProjectRepository
 }
 
 func NewIssueService(
+	io *socket.Server,
 	issueRepo *repo.IssueRepository,
 	userRepo *repo.UserRepository,
 	projectRepo *repo.ProjectRepository,
 	activityRepo *repo.ActivityRepository,
+	sprintRepo *repo.SprintRepository,
+	itemRepo *repo.IssueItemRepository,
+	labelRepo *repo.LabelRepository,
+	workflowRepo *repo.WorkflowRepository,
+	fieldRepo *repo.CustomFieldRepository,
+	watcherRepo *repo.WatcherRepository,
+	userProjectRepo *repo.UserProjectRepository,
 ) *IssueService {
 	return &IssueService{
-		issueRepo:    issueRepo,
-		userRepo:     userRepo,
-		projectRepo:  projectRepo,
-		activityRepo: activityRepo,
+		baseService:     newBaseService(io),
+		issueRepo:       issueRepo,
+		userRepo:        userRepo,
+		projectRepo:     projectRepo,
+		activityRepo:    activityRepo,
+		sprintRepo:      sprintRepo,
+		itemRepo:        itemRepo,
+		labelRepo:       labelRepo,
+		workflowRepo:    workflowRepo,
+		fieldRepo:       fieldRepo,
+		watcherRepo:     watcherRepo,
+		userProjectRepo: userProjectRepo,
 	}
 }
 
 func (s *IssueService) GetIssue(ID string) (*model.Issue, error) {
-	return s.issueRepo.GetByID(ID)
+	issue, err := s.issueRepo.GetByID(ID)
+	if err != nil {
+		return nil, err
+	}
+
+	issue.Links, err = s.itemRepo.GetLinksByIssueID(issue.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return issue, nil
+}
+
+// ValidateAccess the user allowed to view the issue
+func (s *IssueService) ValidateAccess(userID, ID string) error {
+	issue, err := s.issueRepo.GetByID(ID)
+	if err != nil {
+		return err
+	}
+
+	return s.userRepo.ValidatePermission(userID, issue.ProjectID, types.RoleViewer)
+}
+
+// GetWithParents the subtasks of the parent, or the top level issues when empty, paginated on the drag ordering
+func (s *IssueService) GetWithParents(projectID, parentID string, params pagination.Params) (*pagination.Page[model.Issue], error) {
+	return s.issueRepo.GetPageByProjectID(projectID, &parentID, params)
+}
+
+// GetWithFilter the `filter.Query` errors are returned as *jql.Error holding the position.
+// `saved` filter narrowed further by the `filter.Query`, which ordering take precedence
+func (s *IssueService) GetWithFilter(userID, projectID string, filter schemas.FilterIssue, saved *model.SavedFilter) ([]model.Issue, error) {
+	query, err := filterQuery(filter, saved)
+	if err != nil {
+		return nil, err
+	}
+
+	compiled, err := s.compile(userID, projectID, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.issueRepo.GetWithFilter(projectID, filter, compiled)
+}
+
+// GetPageWithFilter the paginated `GetWithFilter`, the pages following the drag ordering so
+// the query & the saved filter can't order the issues
+func (s *IssueService) GetPageWithFilter(userID, projectID string, filter schemas.FilterIssue, saved *model.SavedFilter, params pagination.Params) (*pagination.Page[model.Issue], error) {
+	query, err := filterQuery(filter, saved)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(query.OrderBy) > 0 {
+		return nil, fmt.Errorf("the paginated board is ordered by the issue order, ORDER BY not allowed")
+	}
+
+	compiled, err := s.compile(userID, projectID, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.issueRepo.GetPageWithFilter(projectID, filter, compiled, params)
 }
 
-func (s *IssueService) GetByProject(projectID string) ([]model.Issue, error) {
-	return s.issueRepo.GetByProjectID(true, projectID, nil)
+// filterQuery the `saved` filter query narrowed by the `filter.Query`
+func filterQuery(filter schemas.FilterIssue, saved *model.SavedFilter) (*jql.Query, error) {
+	query := &jql.Query{}
+
+	if saved != nil {
+		savedQuery, err := jql.Parse(saved.Query)
+		if err == nil && saved.Sort != "" {
+			savedQuery.OrderBy, err = jql.ParseOrder(saved.Sort)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("saved filter %q is invalid: %s", saved.Name, err)
+		}
+
+		query.Where = savedQuery.Where
+		query.OrderBy = savedQuery.OrderBy
+	}
+
+	if filter.Query != nil && strings.TrimSpace(*filter.Query) != "" {
+		adhoc, err := jql.Parse(*filter.Query)
+		if err != nil {
+			return nil, err
+		}
+
+		query.Where = jql.And(query.Where, adhoc.Where)
+		if len(adhoc.OrderBy) > 0 {
+			query.OrderBy = adhoc.OrderBy
+		}
+	}
+
+	return query, nil
 }
 
-func (s *IssueService) GetWithParents(projectID, parentID string) ([]model.Issue, error) {
-	return s.issueRepo.GetByProjectID(false, projectID, &parentID)
+// compile the query on the project, nil when there's nothing to apply
+func (s *IssueService) compile(userID, projectID string, query *jql.Query) (*jql.Compiled, error) {
+	if query.Where == nil && len(query.OrderBy) == 0 {
+		return nil, nil
+	}
+
+	ctx, err := queryContext(s.workflowRepo, s.fieldRepo, userID, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	return jql.Compile(query, ctx)
 }
 
-func (s *IssueService) GetWithFilter(projectID string, filter schemas.FilterIssue) ([]model.Issue, error) {
-	return s.issueRepo.GetWithFilter(projectID, filter)
+// queryContext the jql context resolving the project workflow statuses & custom fields
+func queryContext(workflowRepo *repo.WorkflowRepository, fieldRepo *repo.CustomFieldRepository, userID, projectID string) (jql.Context, error) {
+	workflow, err := workflowRepo.GetByProjectID(projectID)
+	if err != nil {
+		return jql.Context{}, err
+	}
+
+	fields, err := fieldRepo.GetByProjectID(projectID)
+	if err != nil {
+		return jql.Context{}, err
+	}
+
+	ctx := jql.Context{
+		UserID:   userID,
+		Now:      time.Now(),
+		Statuses: workflow.Keys(),
+		Fields:   make([]jql.CustomField, len(fields)),
+	}
+
+	for i, field := range fields {
+		ctx.Fields[i] = jql.CustomField{Key: field.Key, Type: field.Type, Options: field.Options}
+	}
+
+	return ctx, nil
 }
 
-func (s *IssueService) GetActivities(ID string) ([]model.RecentActivity, error) {
+func (s *IssueService) GetActivities(ID string, params pagination.Params) (*pagination.Page[model.RecentActivity], error) {
 	childs, err := s.issueRepo.GetChildIDs(ID)
 	if err != nil {
 		return nil, err
 	}
-	return s.activityRepo.GetByIssueIncludeChilds(ID, childs)
+	return s.activityRepo.GetByIssueIncludeChilds(ID, childs, params)
+}
+
+// GetDueBefore fetch the open issues due up to `until` across all projects, usage for the reminder job
+func (s *IssueService) GetDueBefore(until time.Time) ([]model.Issue, error) {
+	return s.issueRepo.GetDueBefore(until)
 }
 
 func (s *IssueService) Create(userID string, value schemas.CreateIssue) (*model.Issue, error) {
@@ -67,9 +225,9 @@ func (s *IssueService) Create(userID string, value schemas.CreateIssue) (*model.
 		Type:        value.Type,
 		Status:      value.Status,
 		AssigneeID:  value.AssigneeID,
+		SprintID:    value.SprintID.Value,
 		ReporterID:  &userID,
 		CreatorID:   &userID,
-		Label:       value.Label,
 		Description: value.Description,
 		Parents:     value.Parents,
 	}
@@ -82,20 +240,74 @@ func (s *IssueService) Create(userID string, value schemas.CreateIssue) (*model.
 		issue.DueDate = &value.DueDate.Time
 	}
 
-	if issue.Status == types.IssueStatusOnProgress ||
-		issue.Status == types.IssueStatusDone {
-		issue.StartDate = common.Ptr(time.Now())
+	project, err := s.prepare(userID, &issue, true)
+	if err != nil {
+		return nil, err
+	}
+
+	workflow, err := s.checkStatus(nil, &issue)
+	if err != nil {
+		return nil, err
+	}
+
+	if value.AssigneeID != nil && *value.AssigneeID != "" {
+		if err := s.checkTaskLimit(userID, project, &issue, value.OverrideTaskLimit); err != nil {
+			return nil, err
+		}
+	}
+
+	reviewRequested, err := s.requestApproval(project, workflow, "", &issue)
+	if err != nil {
+		return nil, err
+	}
+
+	// the start date given by the caller kept, only the missing one autofilled
+	fillDates(&model.Issue{}, types.CategoryTodo, &issue)
+
+	if err := checkDueDate(&issue); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkSprint(&issue); err != nil {
+		return nil, err
+	}
+
+	labels, err := s.checkLabels(issue.ProjectID, value.LabelIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	issue.CustomFields, err = s.checkCustomFields(issue.ProjectID, nil, value.CustomFields, true)
+	if err != nil {
+		return nil, err
 	}
 
-	if err := s.prepare(userID, &issue, true); err != nil {
+	issue.Mentions, err = resolveMentions(s.userProjectRepo, s.userRepo, issue.ProjectID, userID, textOf(issue.Description), "")
+	if err != nil {
 		return nil, err
 	}
 
-	err := s.issueRepo.DB().Transaction(func(tx *gorm.DB) error {
+	err = s.issueRepo.DB().Transaction(func(tx *gorm.DB) error {
 		if err := s.issueRepo.CreateTx(tx, &issue); err != nil {
 			return err
 		}
 
+		if len(labels) > 0 {
+			if err := s.labelRepo.ReplaceIssueLabelsTx(tx, &issue, labels); err != nil {
+				return err
+			}
+		}
+
+		if err := s.watcherRepo.AddTx(tx, issue.ID, issue.ReporterID, issue.AssigneeID); err != nil {
+			return err
+		}
+
+		if reviewRequested {
+			if err := s.recordApprovalRequest(tx, userID, "", &issue); err != nil {
+				return err
+			}
+		}
+
 		activity := model.RecentActivity{
 			UserID:       userID,
 			ProjectID:    &issue.ProjectID,
@@ -111,8 +323,11 @@ func (s *IssueService) Create(userID string, value schemas.CreateIssue) (*model.
 				"reporter":    issue.ReporterID,
 				"creator":     issue.CreatorID,
 				"parents":     issue.Parents,
+				"sprint":      issue.SprintID,
 				"start_date":  issue.StartDate,
 				"due_date":    issue.DueDate,
+				"labels":      labelIDs(labels),
+				"fields":      issue.CustomFields,
 			},
 		}
 
@@ -123,10 +338,14 @@ func (s *IssueService) Create(userID string, value schemas.CreateIssue) (*model.
 		return nil, err
 	}
 
+	issue.Labels = labels
+
 	if issue.Parents != nil && *issue.Parents != "" {
 		s.issueRepo.Heartbeat(*issue.Parents)
 	}
 
+	s.publishIssue(types.SyncIssueCreate, userID, &issue)
+
 	return &issue, nil
 }
 
@@ -143,8 +362,8 @@ func (s *IssueService) Update(userID string, value schemas.CreateIssue) (*model.
 		Type:        value.Type,
 		Status:      value.Status,
 		AssigneeID:  value.AssigneeID,
+		SprintID:    value.SprintID.Value,
 		ReporterID:  &userID,
-		Label:       value.Label,
 		Description: value.Description,
 		Goal:        value.Goal,
 		Parents:     value.Parents,
@@ -163,25 +382,70 @@ func (s *IssueService) Update(userID string, value schemas.CreateIssue) (*model.
 		return nil, err
 	}
 
-	// autofill start date (optional add project_setting statement: enable_autofill_date)
-	if prev.StartDate == nil && issue.StartDate == nil &&
-		prev.Status == types.IssueStatusTodo &&
-		issue.Status != types.IssueStatusTodo {
-		issue.StartDate = common.Ptr(time.Now())
+	project, err := s.prepare(userID, &issue, false)
+	if err != nil {
+		return nil, err
 	}
 
-	if prev.Status != types.IssueStatusDone &&
-		issue.Status == types.IssueStatusDone {
-		issue.DoneDate = common.Ptr(time.Now())
+	workflow, err := s.checkStatus(prev, &issue)
+	if err != nil {
+		return nil, err
+	}
+	prevCategory := workflow.Category(prev.Status)
+
+	// the issue only counted once it (re)assigned or reopened
+	if prev.AssigneeID == nil || issue.AssigneeID == nil ||
+		*prev.AssigneeID != *issue.AssigneeID ||
+		prev.Status == types.IssueStatusDraft ||
+		prevCategory == types.CategoryDone {
+		if err := s.checkTaskLimit(userID, project, &issue, value.OverrideTaskLimit); err != nil {
+			return nil, err
+		}
 	}
 
-	if prev.Status == types.IssueStatusDone &&
-		issue.Status != types.IssueStatusDone &&
-		prev.DoneDate != nil {
-		issue.DoneDate = prev.DoneDate
+	reviewRequested, err := s.requestApproval(project, workflow, prev.Status, &issue)
+	if err != nil {
+		return nil, err
+	}
+
+	fillDates(prev, prevCategory, &issue)
+
+	if err := checkDueDate(&issue); err != nil {
+		return nil, err
+	}
+
+	if !value.SprintID.Set {
+		issue.SprintID = prev.SprintID
+	}
+
+	// issues on a closed sprint keep their reference, only validate when it moves
+	if prev.SprintID == nil || issue.SprintID == nil || *prev.SprintID != *issue.SprintID {
+		if err := s.checkSprint(&issue); err != nil {
+			return nil, err
+		}
+	}
+
+	// omitted `labelIds` keep the current labels
+	labels := prev.Labels
+	if value.LabelIDs != nil {
+		labels, err = s.checkLabels(issue.ProjectID, value.LabelIDs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// omitted `customFields` keep the current values, the given ones merged into them
+	issue.CustomFields = prev.CustomFields
+	if value.CustomFields != nil {
+		issue.CustomFields, err = s.checkCustomFields(issue.ProjectID, prev.CustomFields, value.CustomFields, false)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	if err := s.prepare(userID, &issue, false); err != nil {
+	issue.Mentions, err = resolveMentions(s.userProjectRepo, s.userRepo, issue.ProjectID, userID,
+		textOf(issue.Description), textOf(prev.Description))
+	if err != nil {
 		return nil, err
 	}
 
@@ -190,6 +454,22 @@ func (s *IssueService) Update(userID string, value schemas.CreateIssue) (*model.
 			return err
 		}
 
+		if value.LabelIDs != nil {
+			if err := s.labelRepo.ReplaceIssueLabelsTx(tx, &issue, labels); err != nil {
+				return err
+			}
+		}
+
+		if err := s.watcherRepo.AddTx(tx, issue.ID, issue.AssigneeID); err != nil {
+			return err
+		}
+
+		if reviewRequested {
+			if err := s.recordApprovalRequest(tx, userID, prev.Status, &issue); err != nil {
+				return err
+			}
+		}
+
 		activity := model.RecentActivity{
 			UserID:       userID,
 			ProjectID:    &issue.ProjectID,
@@ -205,8 +485,11 @@ func (s *IssueService) Update(userID string, value schemas.CreateIssue) (*model.
 				"reporter":    prev.ReporterID,
 				"creator":     prev.CreatorID,
 				"parents":     prev.Parents,
+				"sprint":      prev.SprintID,
 				"start_date":  prev.StartDate,
 				"due_date":    prev.DueDate,
+				"labels":      labelIDs(prev.Labels),
+				"fields":      prev.CustomFields,
 			},
 			NewValues: &datatypes.JSONMap{
 				"title":       issue.Title,
@@ -218,8 +501,11 @@ func (s *IssueService) Update(userID string, value schemas.CreateIssue) (*model.
 				"reporter":    issue.ReporterID,
 				"creator":     issue.CreatorID,
 				"parents":     issue.Parents,
+				"sprint":      issue.SprintID,
 				"start_date":  issue.StartDate,
 				"due_date":    issue.DueDate,
+				"labels":      labelIDs(labels),
+				"fields":      issue.CustomFields,
 			},
 		}
 
@@ -230,13 +516,32 @@ func (s *IssueService) Update(userID string, value schemas.CreateIssue) (*model.
 		return nil, err
 	}
 
+	issue.Labels = labels
+
 	if issue.Parents != nil && *issue.Parents != "" {
 		s.issueRepo.Heartbeat(*issue.Parents)
 	}
 
+	if prevCategory != types.CategoryDone && prev.Status != types.IssueStatusInReview {
+		s.warnBlocked(&issue)
+	}
+
+	s.publishIssue(types.SyncIssueUpdate, userID, &issue)
+
 	return &issue, nil
 }
 
+func (s *IssueService) Approve(userID, issueID, reason string) (*model.Issue, error) {
+	return s.review(userID, issueID, true, reason)
+}
+
+func (s *IssueService) Reject(userID, issueID, reason string) (*model.Issue, error) {
+	if strings.TrimSpace(reason) == "" {
+		return nil, fmt.Errorf("failed to reject: reason required")
+	}
+	return s.review(userID, issueID, false, reason)
+}
+
 func (s *IssueService) UpdateSequence(userID, issueID string, direction types.MoveDirection) ([]model.Issue, error) {
 	issue, err := s.issueRepo.GetByID(issueID)
 	if err != nil {
@@ -279,6 +584,10 @@ func (s *IssueService) UpdateSequence(userID, issueID string, direction types.Mo
 		return nil, err
 	}
 
+	for _, v := range issues {
+		s.publishIssue(types.SyncIssueOrder, userID, &v)
+	}
+
 	return issues, nil
 }
 
@@ -361,6 +670,8 @@ func (s *IssueService) UpdateParent(userID, issueID, parentID string) (*model.Is
 		return nil, err
 	}
 
+	s.publishIssue(types.SyncIssueMove, userID, child)
+
 	return child, nil
 }
 
@@ -373,13 +684,23 @@ func (s *IssueService) GetAssigment(issue *model.Issue, project *model.Project)
 		return nil, nil
 	}
 
+	users, err := s.availableUsers(project)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(project.Users) > 0 && len(users) == 0 {
+		return nil, fmt.Errorf("task limit reached: every member already has %d open issues",
+			project.Setting.TaskLimitPerUser)
+	}
+
 	switch project.Setting.AssignmentMethod {
 	case types.AssignmentMethodRandom:
-		return s.random(project.Users), nil
+		return s.random(users), nil
 	case types.AssignmentMethodRoundRobin:
-		return s.raoundRobin(project, project.Users)
+		return s.raoundRobin(project, users)
 	case types.AssignmentMethodLeastBusy:
-		return s.leastBusy(project.Users)
+		return s.leastBusy(project, users)
 	default:
 		return nil, fmt.Errorf("unknown assignment method: %s", project.Setting.AssignmentMethod)
 	}
@@ -431,6 +752,8 @@ func (s *IssueService) RemoveParent(userID, issueID string) (*model.Issue, error
 		return nil, err
 	}
 
+	s.publishIssue(types.SyncIssueMove, userID, issue)
+
 	return issue, nil
 }
 
@@ -440,9 +763,9 @@ func (s *IssueService) Delete(userID, issueID string) error {
 		return err
 	}
 
-	if issue.Status != types.IssueStatusTodo {
+	if issue.StatusCategory != types.CategoryTodo {
 		message := "in progress"
-		if issue.Status == types.IssueStatusDone {
+		if issue.StatusCategory == types.CategoryDone {
 			message = "completed"
 		}
 		return fmt.Errorf("can't delete %s issue", message)
@@ -500,60 +823,1007 @@ func (s *IssueService) Delete(userID, issueID string) error {
 		return err
 	}
 
+	s.publishIssue(types.SyncIssueDelete, userID, issue)
+
 	return nil
 }
 
-func (s *IssueService) prepare(userID string, issue *model.Issue, isCreate bool) error {
-	project, err := s.projectRepo.GetIncludeDetail(issue.ProjectID)
+// BulkResult the outcome of `IssueService.Bulk`
+type BulkResult struct {
+	Action types.BulkAction `json:"action"`
+	Issues []model.Issue    `json:"issues" comment:"the changed issues, the deleted ones as they were"`
+
+	// Recipients the changed issue IDs by the watching project member, the actor left out
+	Recipients map[string][]string `json:"-"`
+
+	// Review the issues parked `in_review` by the approval workflow, the approvers notified of
+	Review []model.Issue `json:"-"`
+}
+
+// bulkChange the pending change of one issue, applied inside the batch transaction
+type bulkChange struct {
+	issue    *model.Issue
+	activity types.ActivityType
+	old      datatypes.JSONMap
+	new      datatypes.JSONMap
+	updates  map[string]any
+	labels   []model.Label // nil keep the current labels
+	watch    []*string     // the users subscribed to the issue along the change
+	review   bool          // approval requested instead of closing the issue
+}
+
+// Bulk apply one change on every issue at once. the whole batch validated first then run in a single
+// transaction, a failing issue leave the others untouched. the issues already matching are skipped
+func (s *IssueService) Bulk(userID string, value schemas.BulkIssue) (*BulkResult, error) {
+	IDs := common.SliceUnique(value.IDs)
+	issues, err := s.issueRepo.GetByIDs(IDs)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	if len(issues) != len(IDs) {
+		return nil, fmt.Errorf("failed to fetch issue: record not found")
 	}
 
 	allowed := types.RoleEditor
-	if isCreate {
+	if value.Action == types.BulkDelete {
 		allowed = types.RoleAdmin
 	}
 
-	if err := s.userRepo.ValidatePermission(userID, project.ID, allowed); err != nil {
-		return err
+	for _, issue := range issues {
+		if err := s.userRepo.ValidatePermission(userID,
+			issue.ProjectID, allowed); err != nil {
+			return nil, err
+		}
 	}
 
-	if isCreate {
-		if issue.Parents == nil &&
-			project.Setting.RequireDescription &&
-			issue.Description == nil {
-			return fmt.Errorf("description required")
+	var changes []bulkChange
+	switch value.Action {
+	case types.BulkStatus:
+		changes, err = s.bulkStatus(issues, value.Status)
+	case types.BulkPriority:
+		changes = s.bulkPriority(issues, value.Priority)
+	case types.BulkAssignee:
+		changes, err = s.bulkAssignee(issues, value.AssigneeID)
+	case types.BulkLabel, types.BulkUnlabel:
+		changes, err = s.bulkLabels(issues, value.LabelIDs, value.Action == types.BulkUnlabel)
+	case types.BulkParent:
+		changes, err = s.bulkParent(issues, value.ParentID)
+	case types.BulkSprint:
+		changes, err = s.bulkSprint(issues, value.SprintID)
+	case types.BulkDelete:
+		changes, err = s.bulkDelete(issues)
+	default:
+		err = fmt.Errorf("unknown bulk action: %s", value.Action)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	// the watchers of the deleted issues removed along, fetched ahead
+	var watchers map[string][]string
+	if value.Action == types.BulkDelete {
+		if watchers, err = s.watcherRepo.GetUserIDsByIssueIDs(IDs); err != nil {
+			return nil, err
 		}
+	}
 
-		if issue.AssigneeID == nil {
-			assignID, err := s.GetAssigment(issue, project)
-			if err != nil {
+	deleted := make(map[string]bool)
+	err = s.issueRepo.DB().Transaction(func(tx *gorm.DB) error {
+		for _, change := range changes {
+			activity := model.RecentActivity{
+				UserID:       userID,
+				ProjectID:    &change.issue.ProjectID,
+				IssueID:      &change.issue.ID,
+				ActivityType: change.activity,
+				OldValues:    &change.old,
+			}
+
+			if change.new != nil {
+				activity.NewValues = &change.new
+			}
+
+			if err := s.activityRepo.CreateTx(tx, &activity); err != nil {
 				return err
 			}
-			issue.AssigneeID = assignID
+
+			if value.Action == types.BulkDelete {
+				if change.issue.Parents == nil {
+					if err := s.issueRepo.DeleteByParent(tx, change.issue.ID); err != nil {
+						return err
+					}
+					deleted[change.issue.ID] = true
+				}
+
+				// the subtasks of a parent deleted earlier in the batch already gone along
+				if change.issue.Parents == nil || !deleted[*change.issue.Parents] {
+					_, err := s.issueRepo.UpdateSequenceTx(tx, change.issue, types.DirectionBottom)
+					if err != nil {
+						return err
+					}
+				}
+
+				if err := s.issueRepo.DeleteByID(tx, change.issue.ID); err != nil {
+					return err
+				}
+				continue
+			}
+
+			if len(change.updates) > 0 {
+				if err := s.issueRepo.UpdateFieldsTx(tx, change.issue, change.updates); err != nil {
+					return err
+				}
+			}
+
+			if err := s.watcherRepo.AddTx(tx, change.issue.ID, change.watch...); err != nil {
+				return err
+			}
+
+			if change.labels != nil {
+				if err := s.labelRepo.ReplaceIssueLabelsTx(tx, change.issue, change.labels); err != nil {
+					return err
+				}
+				change.issue.Labels = change.labels
+			}
 		}
 
-		order, err := s.issueRepo.GetSequence(project.ID, issue.Parents)
-		if err != nil {
-			return err
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if watchers == nil {
+		if watchers, err = s.watcherRepo.GetUserIDsByIssueIDs(IDs); err != nil {
+			return nil, err
 		}
-		issue.Order = order
+	}
 
-		if issue.Type == "" && issue.Parents != nil {
-			issue.Type = types.IssueTypeSubtask
+	result := &BulkResult{
+		Action:     value.Action,
+		Issues:     make([]model.Issue, 0, len(changes)),
+		Recipients: make(map[string][]string),
+	}
+
+	parents := make([]string, 0)
+	for _, change := range changes {
+		result.Issues = append(result.Issues, *change.issue)
+
+		if change.review {
+			result.Review = append(result.Review, *change.issue)
 		}
 
-		if issue.Priority == "" {
-			issue.Priority = project.Setting.DefaultIssuePriority
+		for _, recipient := range watchers[change.issue.ID] {
+			if recipient != userID {
+				result.Recipients[recipient] = append(result.Recipients[recipient], change.issue.ID)
+			}
 		}
 
-		if issue.Status == "" {
-			issue.Status = project.Setting.DefaultIssueStatus
+		if change.issue.Parents != nil && *change.issue.Parents != "" && value.Action != types.BulkDelete {
+			parents = append(parents, *change.issue.Parents)
 		}
 	}
 
-	return nil
-}
+	for _, parentID := range common.SliceUnique(parents) {
+		s.issueRepo.Heartbeat(parentID)
+	}
+
+	event := types.SyncIssueUpdate
+	switch value.Action {
+	case types.BulkDelete:
+		event = types.SyncIssueDelete
+	case types.BulkParent:
+		event = types.SyncIssueMove
+	}
+
+	for _, change := range changes {
+		s.publishIssue(event, userID, change.issue)
+	}
+
+	return result, nil
+}
+
+// bulkStatus follow the same workflow rules, dates autofill & approval as `Update`
+func (s *IssueService) bulkStatus(issues []model.Issue, status types.IssueStatus) ([]bulkChange, error) {
+	projects := make(map[string]*model.Project)
+	pending := make(map[string]map[string]int)
+	changes := make([]bulkChange, 0, len(issues))
+
+	for i := range issues {
+		issue := &issues[i]
+		if issue.Status == status {
+			continue
+		}
+
+		prev := *issue
+		issue.Status = status
+
+		workflow, err := s.checkStatus(&prev, issue)
+		if err != nil {
+			return nil, fmt.Errorf("%w (issue %q)", err, issue.Title)
+		}
+
+		project, ok := projects[issue.ProjectID]
+		if !ok {
+			if project, err = s.projectRepo.GetIncludeDetail(issue.ProjectID); err != nil {
+				return nil, err
+			}
+			projects[issue.ProjectID] = project
+		}
+
+		reviewRequested, err := s.requestApproval(project, workflow, prev.Status, issue)
+		if err != nil {
+			return nil, fmt.Errorf("%w (issue %q)", err, issue.Title)
+		}
+		prevCategory := workflow.Category(prev.Status)
+		fillDates(&prev, prevCategory, issue)
+
+		// the reopened issues count again toward the assignee limit, as on `Update`
+		if issue.AssigneeID != nil && issue.StatusCategory != types.CategoryDone &&
+			(prev.Status == types.IssueStatusDraft || prevCategory == types.CategoryDone) {
+			if pending[issue.ProjectID] == nil {
+				pending[issue.ProjectID] = make(map[string]int)
+			}
+			pending[issue.ProjectID][*issue.AssigneeID]++
+		}
+
+		change := bulkChange{
+			issue:    issue,
+			activity: types.StatusChange,
+			old:      datatypes.JSONMap{"status": prev.Status},
+			new:      datatypes.JSONMap{"status": issue.Status},
+			updates: map[string]any{
+				"status":          issue.Status,
+				"status_category": issue.StatusCategory,
+				"start_date":      issue.StartDate,
+				"done_date":       issue.DoneDate,
+			},
+		}
+
+		if reviewRequested {
+			change.new["message"] = "approval requested"
+			change.review = true
+		}
+
+		changes = append(changes, change)
+	}
+
+	for projectID, counts := range pending {
+		if err := s.checkBulkTaskLimit(projects[projectID], counts); err != nil {
+			return nil, err
+		}
+	}
+
+	return changes, nil
+}
+
+// checkBulkTaskLimit reject the batch adding `pending` open issues per assignee over the project `TaskLimitPerUser`
+func (s *IssueService) checkBulkTaskLimit(project *model.Project, pending map[string]int) error {
+	limit := project.Setting.TaskLimitPerUser
+	if limit <= 0 {
+		return nil
+	}
+
+	counts, err := s.issueRepo.CountOpenByAssignees(project.ID, slices.Collect(maps.Keys(pending)))
+	if err != nil {
+		return err
+	}
+
+	for ID, count := range pending {
+		if counts[ID]+count > limit {
+			return fmt.Errorf("task limit reached: assignee already has %d open issues, %d more over the limit %d",
+				counts[ID], count, limit)
+		}
+	}
+
+	return nil
+}
+
+func (s *IssueService) bulkPriority(issues []model.Issue, priority types.IssuePriority) []bulkChange {
+	changes := make([]bulkChange, 0, len(issues))
+
+	for i := range issues {
+		issue := &issues[i]
+		if issue.Priority == priority {
+			continue
+		}
+
+		changes = append(changes, bulkChange{
+			issue:    issue,
+			activity: types.IssueUpdate,
+			old:      datatypes.JSONMap{"priority": issue.Priority},
+			new:      datatypes.JSONMap{"priority": priority},
+			updates:  map[string]any{"priority": priority},
+		})
+		issue.Priority = priority
+	}
+
+	return changes
+}
+
+// bulkAssignee the task limit counted with every open issue of the batch
+func (s *IssueService) bulkAssignee(issues []model.Issue, assigneeID *string) ([]bulkChange, error) {
+	if assigneeID != nil && *assigneeID == "" {
+		assigneeID = nil
+	}
+
+	members := make(map[string]bool)
+	pending := make(map[string]int)
+	changes := make([]bulkChange, 0, len(issues))
+
+	for i := range issues {
+		issue := &issues[i]
+		if issue.AssigneeID == nil && assigneeID == nil ||
+			issue.AssigneeID != nil && assigneeID != nil && *issue.AssigneeID == *assigneeID {
+			continue
+		}
+
+		if assigneeID != nil {
+			if _, ok := members[issue.ProjectID]; !ok {
+				members[issue.ProjectID] = s.userRepo.ValidatePermission(*assigneeID,
+					issue.ProjectID, types.RoleViewer) == nil
+			}
+
+			if !members[issue.ProjectID] {
+				return nil, fmt.Errorf("failed to assign: user is not a member of the project")
+			}
+
+			if issue.Status != types.IssueStatusDraft && issue.StatusCategory != types.CategoryDone {
+				pending[issue.ProjectID]++
+			}
+		}
+
+		changes = append(changes, bulkChange{
+			issue:    issue,
+			activity: types.IssueUpdate,
+			old:      datatypes.JSONMap{"assignee": issue.AssigneeID},
+			new:      datatypes.JSONMap{"assignee": assigneeID},
+			updates:  map[string]any{"assignee_id": assigneeID},
+			watch:    []*string{assigneeID},
+		})
+		issue.AssigneeID = assigneeID
+	}
+
+	for projectID, count := range pending {
+		project, err := s.projectRepo.GetIncludeDetail(projectID)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := s.checkBulkTaskLimit(project, map[string]int{*assigneeID: count}); err != nil {
+			return nil, err
+		}
+	}
+
+	return changes, nil
+}
+
+// bulkLabels add the labels to the issues, or remove them when `remove`
+func (s *IssueService) bulkLabels(issues []model.Issue, IDs []string, remove bool) ([]bulkChange, error) {
+	catalog := make(map[string][]model.Label)
+	changes := make([]bulkChange, 0, len(issues))
+
+	for i := range issues {
+		issue := &issues[i]
+
+		labels, ok := catalog[issue.ProjectID]
+		if !ok {
+			var err error
+			if labels, err = s.checkLabels(issue.ProjectID, IDs); err != nil {
+				return nil, err
+			}
+			catalog[issue.ProjectID] = labels
+		}
+
+		current := labelIDs(issue.Labels)
+		result := make([]model.Label, 0, len(issue.Labels)+len(labels))
+		for _, label := range issue.Labels {
+			if !remove || !slices.Contains(IDs, label.ID) {
+				result = append(result, label)
+			}
+		}
+
+		if !remove {
+			for _, label := range labels {
+				if !slices.Contains(current, label.ID) {
+					result = append(result, label)
+				}
+			}
+		}
+
+		if len(result) == len(issue.Labels) {
+			continue
+		}
+
+		changes = append(changes, bulkChange{
+			issue:    issue,
+			activity: types.IssueUpdate,
+			old:      datatypes.JSONMap{"labels": current},
+			new:      datatypes.JSONMap{"labels": labelIDs(result)},
+			labels:   result,
+		})
+	}
+
+	return changes, nil
+}
+
+// bulkParent move the issues under the parent in the batch order, the empty `parentID` detach them
+func (s *IssueService) bulkParent(issues []model.Issue, parentID *string) ([]bulkChange, error) {
+	var parent *model.Issue
+	if parentID != nil && *parentID != "" {
+		var err error
+		if parent, err = s.issueRepo.GetByID(*parentID); err != nil {
+			return nil, err
+		}
+
+		if parent.Parents != nil && *parent.Parents != "" {
+			return nil, fmt.Errorf("failed to update: parent is a child issue")
+		}
+	}
+
+	sequences := make(map[string]int)
+	changes := make([]bulkChange, 0, len(issues))
+
+	for i := range issues {
+		issue := &issues[i]
+		isChild := issue.Parents != nil && *issue.Parents != ""
+
+		if parent == nil && !isChild || parent != nil && isChild && *issue.Parents == parent.ID {
+			continue
+		}
+
+		if parent != nil && parent.ID == issue.ID {
+			return nil, fmt.Errorf("failed to update: cannot set parent to self")
+		}
+
+		if parent != nil && parent.ProjectID != issue.ProjectID {
+			return nil, fmt.Errorf("failed to update: cross-project not allowed")
+		}
+
+		var target *string
+		if parent != nil {
+			target = &parent.ID
+		}
+
+		// the next order under the target, following up within the batch
+		sequence, ok := sequences[issue.ProjectID]
+		if !ok {
+			var err error
+			if sequence, err = s.issueRepo.GetSequence(issue.ProjectID, target); err != nil {
+				return nil, err
+			}
+		}
+		sequences[issue.ProjectID] = sequence + 1
+
+		change := bulkChange{
+			issue:    issue,
+			activity: types.IssueMove,
+			old: datatypes.JSONMap{
+				"parents": issue.Parents,
+				"order":   issue.Order,
+				"type":    issue.Type,
+			},
+		}
+
+		issue.Parents = target
+		issue.Order = sequence
+		if parent != nil && issue.Type == types.IssueTypeTask {
+			issue.Type = types.IssueTypeSubtask
+		} else if parent == nil && issue.Type == types.IssueTypeSubtask {
+			issue.Type = types.IssueTypeTask
+		}
+
+		change.new = datatypes.JSONMap{
+			"parents": issue.Parents,
+			"order":   issue.Order,
+			"type":    issue.Type,
+		}
+		change.updates = map[string]any{
+			"parents":     issue.Parents,
+			"order_index": issue.Order,
+			"type":        issue.Type,
+		}
+		changes = append(changes, change)
+	}
+
+	return changes, nil
+}
+
+// bulkSprint the empty `sprintID` move the issues into the backlog
+func (s *IssueService) bulkSprint(issues []model.Issue, sprintID *string) ([]bulkChange, error) {
+	if sprintID != nil && *sprintID == "" {
+		sprintID = nil
+	}
+
+	var sprint *model.Sprint
+	if sprintID != nil {
+		var err error
+		if sprint, err = s.sprintRepo.GetByID(*sprintID); err != nil {
+			return nil, err
+		}
+
+		if sprint.State == types.SprintStateClosed {
+			return nil, fmt.Errorf("failed to assign sprint: sprint already closed")
+		}
+	}
+
+	changes := make([]bulkChange, 0, len(issues))
+	for i := range issues {
+		issue := &issues[i]
+		if issue.SprintID == nil && sprintID == nil ||
+			issue.SprintID != nil && sprintID != nil && *issue.SprintID == *sprintID {
+			continue
+		}
+
+		if sprint != nil && sprint.ProjectID != issue.ProjectID {
+			return nil, fmt.Errorf("failed to assign sprint: cross-project not allowed")
+		}
+
+		changes = append(changes, bulkChange{
+			issue:    issue,
+			activity: types.IssueUpdate,
+			old:      datatypes.JSONMap{"sprint": issue.SprintID},
+			new:      datatypes.JSONMap{"sprint": sprintID},
+			updates:  map[string]any{"sprint_id": sprintID},
+		})
+		issue.SprintID = sprintID
+	}
+
+	return changes, nil
+}
+
+// bulkDelete only the issues not started yet, same as `Delete`
+func (s *IssueService) bulkDelete(issues []model.Issue) ([]bulkChange, error) {
+	changes := make([]bulkChange, 0, len(issues))
+
+	for i := range issues {
+		issue := &issues[i]
+		if issue.StatusCategory != types.CategoryTodo {
+			message := "in progress"
+			if issue.StatusCategory == types.CategoryDone {
+				message = "completed"
+			}
+			return nil, fmt.Errorf("can't delete %s issue %q", message, issue.Title)
+		}
+
+		change := bulkChange{
+			issue:    issue,
+			activity: types.IssueDelete,
+			old: datatypes.JSONMap{
+				"title":       issue.Title,
+				"description": issue.Description,
+				"status":      issue.Status,
+				"priority":    issue.Priority,
+				"assignee":    issue.AssigneeID,
+				"reporter":    issue.ReporterID,
+				"parents":     issue.Parents,
+			},
+		}
+
+		if issue.Parents != nil {
+			change.activity = types.IssueChildrenDelete
+			change.old = datatypes.JSONMap{
+				"parent_issue_id":    issue.ID,
+				"parent_issue_title": issue.Title,
+			}
+		}
+
+		changes = append(changes, change)
+	}
+
+	return changes, nil
+}
+
+func (s *IssueService) prepare(userID string, issue *model.Issue, isCreate bool) (*model.Project, error) {
+	project, err := s.projectRepo.GetIncludeDetail(issue.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	allowed := types.RoleEditor
+	if isCreate {
+		allowed = types.RoleAdmin
+	}
+
+	if err := s.userRepo.ValidatePermission(userID, project.ID, allowed); err != nil {
+		return nil, err
+	}
+
+	if isCreate {
+		if issue.Parents == nil &&
+			project.Setting.RequireDescription &&
+			issue.Description == nil {
+			return nil, fmt.Errorf("description required")
+		}
+
+		if issue.AssigneeID == nil {
+			assignID, err := s.GetAssigment(issue, project)
+			if err != nil {
+				return nil, err
+			}
+			issue.AssigneeID = assignID
+		}
+
+		order, err := s.issueRepo.GetSequence(project.ID, issue.Parents)
+		if err != nil {
+			return nil, err
+		}
+		issue.Order = order
+
+		if issue.Type == "" && issue.Parents != nil {
+			issue.Type = types.IssueTypeSubtask
+		}
+
+		if issue.Priority == "" {
+			issue.Priority = project.Setting.DefaultIssuePriority
+		}
+
+		if issue.Status == "" {
+			issue.Status = project.Setting.DefaultIssueStatus
+		}
+	}
+
+	if err := s.prepareDueDate(project.Setting, issue, isCreate); err != nil {
+		return nil, err
+	}
+
+	return project, nil
+}
+
+// prepareDueDate fill the missing due date with `DefaultDueDateOffset` days ahead, on update only when it required
+// so the due date still able to be cleared
+func (s *IssueService) prepareDueDate(setting *model.ProjectSetting, issue *model.Issue, isCreate bool) error {
+	if issue.DueDate == nil && (isCreate || setting.RequireDueDate) {
+		if setting.DefaultDueDateOffset > 0 {
+			issue.DueDate = common.Ptr(time.Now().AddDate(0, 0, setting.DefaultDueDateOffset))
+		} else if setting.RequireDueDate {
+			return fmt.Errorf("due date required")
+		}
+	}
+
+	return nil
+}
+
+// checkDueDate the due date not before the start date, checked once the start date autofilled
+func checkDueDate(issue *model.Issue) error {
+	if issue.DueDate != nil && issue.StartDate != nil &&
+		issue.DueDate.Truncate(24*time.Hour).Before(issue.StartDate.Truncate(24*time.Hour)) {
+		return fmt.Errorf("due date must be after start date")
+	}
+
+	return nil
+}
+
+// approval workflow, completing an issue have to wait the approver sign-off on the `in_review` status.
+// the setting & the workflow both kept from dropping the status while enabled, its absence refused
+func (s *IssueService) requestApproval(project *model.Project, workflow *model.Workflow, prev types.IssueStatus, issue *model.Issue) (bool, error) {
+	if project.Setting == nil || !project.Setting.EnableApprovalWorkflow {
+		return false, nil
+	}
+
+	if workflow.IsDone(prev) || issue.StatusCategory != types.CategoryDone {
+		return false, nil
+	}
+
+	review := workflow.Status(types.IssueStatusInReview)
+	if review == nil {
+		return false, fmt.Errorf("approval workflow enabled but the project workflow has no %q status",
+			types.IssueStatusInReview)
+	}
+
+	issue.Status = review.Key
+	issue.StatusCategory = review.Category
+	return true, nil
+}
+
+// checkStatus resolve the issue status on the project workflow, `prev` nil on creation.
+// a change of status must follow one of the workflow transitions
+func (s *IssueService) checkStatus(prev *model.Issue, issue *model.Issue) (*model.Workflow, error) {
+	workflow, err := s.workflowRepo.GetByProjectID(issue.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if prev != nil && issue.Status == "" {
+		issue.Status = prev.Status
+	}
+
+	status := workflow.Status(issue.Status)
+	if status == nil {
+		return nil, fmt.Errorf("invalid status %q: not on the project workflow", issue.Status)
+	}
+
+	if prev != nil && !workflow.CanTransition(prev.Status, issue.Status) {
+		return nil, fmt.Errorf("invalid status %q: transition from %q not allowed", issue.Status, prev.Status)
+	}
+
+	issue.StatusCategory = status.Category
+	return workflow, nil
+}
+
+func (s *IssueService) recordApprovalRequest(tx *gorm.DB, userID string, prev types.IssueStatus, issue *model.Issue) error {
+	activity := model.RecentActivity{
+		UserID:       userID,
+		ProjectID:    &issue.ProjectID,
+		IssueID:      &issue.ID,
+		ActivityType: types.StatusChange,
+		OldValues:    &datatypes.JSONMap{"status": prev},
+		NewValues: &datatypes.JSONMap{
+			"status":  issue.Status,
+			"message": "approval requested",
+		},
+	}
+
+	return s.activityRepo.CreateTx(tx, &activity)
+}
+
+func (s *IssueService) review(userID, issueID string, approve bool, reason string) (*model.Issue, error) {
+	issue, err := s.issueRepo.GetByID(issueID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.ValidatePermission(userID,
+		issue.ProjectID, types.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	if issue.Status != types.IssueStatusInReview {
+		return nil, fmt.Errorf("failed to review: issue is not waiting for approval")
+	}
+
+	activity := model.RecentActivity{
+		UserID:       userID,
+		ProjectID:    &issue.ProjectID,
+		IssueID:      &issue.ID,
+		ActivityType: types.IssueApprove,
+		OldValues:    &datatypes.JSONMap{"status": issue.Status},
+	}
+
+	workflow, err := s.workflowRepo.GetByProjectID(issue.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	// approved into the first done status, rejected back to the first in progress one
+	target := workflow.First(types.CategoryDone)
+	if approve {
+		issue.DoneDate = common.Ptr(time.Now())
+	} else {
+		activity.ActivityType = types.IssueReject
+		issue.DoneDate = nil
+		if target = workflow.First(types.CategoryInProgress); target == nil {
+			target = workflow.First(types.CategoryTodo)
+		}
+	}
+
+	issue.Status = target.Key
+	issue.StatusCategory = target.Category
+
+	err = s.issueRepo.DB().Transaction(func(tx *gorm.DB) error {
+		if err := s.issueRepo.UpdateStatusTx(tx, issue); err != nil {
+			return err
+		}
+
+		activity.NewValues = &datatypes.JSONMap{
+			"status": issue.Status,
+			"reason": reason,
+		}
+
+		return s.activityRepo.CreateTx(tx, &activity)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if issue.Parents != nil && *issue.Parents != "" {
+		s.issueRepo.Heartbeat(*issue.Parents)
+	}
+
+	if approve {
+		s.warnBlocked(issue)
+	}
+
+	s.publishIssue(types.SyncIssueUpdate, userID, issue)
+
+	return issue, nil
+}
+
+// warnBlocked warn completing an issue while its blockers still open, it doesn't prevent the change
+func (s *IssueService) warnBlocked(issue *model.Issue) {
+	if issue.StatusCategory != types.CategoryDone && issue.Status != types.IssueStatusInReview {
+		return
+	}
+
+	blockers, err := s.itemRepo.GetOpenBlockers(issue.ID)
+	if err != nil {
+		logger.Errorf("failed to check blockers issue_id=%s: %s", issue.ID, err)
+		return
+	}
+
+	for _, blocker := range blockers {
+		issue.Warnings = append(issue.Warnings, fmt.Sprintf(
+			`completed while blocked by "%s" which is still %s`,
+			blocker.Title, blocker.Status.ToString()))
+	}
+}
+
+func (s *IssueService) checkSprint(issue *model.Issue) error {
+	if issue.SprintID == nil || *issue.SprintID == "" {
+		issue.SprintID = nil
+		return nil
+	}
+
+	sprint, err := s.sprintRepo.GetByID(*issue.SprintID)
+	if err != nil {
+		return err
+	}
+
+	if sprint.ProjectID != issue.ProjectID {
+		return fmt.Errorf("failed to assign sprint: cross-project not allowed")
+	}
+
+	if sprint.State == types.SprintStateClosed {
+		return fmt.Errorf("failed to assign sprint: sprint already closed")
+	}
+
+	return nil
+}
+
+// checkLabels resolve the label IDs, every label must belong to the issue project
+func (s *IssueService) checkLabels(projectID string, IDs []string) ([]model.Label, error) {
+	IDs = common.SliceUnique(IDs)
+
+	labels, err := s.labelRepo.GetByIDs(projectID, IDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(labels) != len(IDs) {
+		return nil, fmt.Errorf("failed to assign label: label not found")
+	}
+
+	return labels, nil
+}
+
+// checkCustomFields merge the `values` into the `prev` ones, null value clearing the field.
+// the required fields enforced on create, or on update when the field is changed
+func (s *IssueService) checkCustomFields(projectID string, prev datatypes.JSONMap, values map[string]any, isCreate bool) (datatypes.JSONMap, error) {
+	result := datatypes.JSONMap{}
+	if len(prev) == 0 && len(values) == 0 && !isCreate {
+		return result, nil
+	}
+
+	fields, err := s.fieldRepo.GetByProjectID(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	byKey := make(map[string]*model.CustomField, len(fields))
+	for i := range fields {
+		byKey[fields[i].Key] = &fields[i]
+		if value, ok := prev[fields[i].Key]; ok {
+			result[fields[i].Key] = value
+		}
+	}
+
+	for key, value := range values {
+		field, ok := byKey[key]
+		if !ok {
+			return nil, fmt.Errorf("invalid custom field %q: not defined on the project", key)
+		}
+
+		normalized, err := fieldValue(field, value)
+		if err != nil {
+			return nil, err
+		}
+
+		if normalized == nil {
+			delete(result, key)
+			continue
+		}
+
+		if field.Type == types.FieldUser {
+			if err := s.userRepo.ValidatePermission(normalized.(string),
+				projectID, types.RoleViewer); err != nil {
+				return nil, fmt.Errorf("invalid custom field %q: user is not a project member", key)
+			}
+		}
+		result[key] = normalized
+	}
+
+	for _, field := range fields {
+		if _, changed := values[field.Key]; field.Required && (isCreate || changed) && result[field.Key] == nil {
+			return nil, fmt.Errorf("invalid custom field %q: value required", field.Key)
+		}
+	}
+
+	return result, nil
+}
+
+func textOf(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+func labelIDs(labels []model.Label) []string {
+	return common.Map(labels, func(l model.Label) string { return l.ID })
+}
+
+// availableUsers filter out the members reaching the project `TaskLimitPerUser`, zero means unlimited
+func (s *IssueService) availableUsers(project *model.Project) ([]model.User, error) {
+	limit := project.Setting.TaskLimitPerUser
+	if limit <= 0 || len(project.Users) == 0 {
+		return project.Users, nil
+	}
+
+	ids := make([]string, len(project.Users))
+	for i, user := range project.Users {
+		ids[i] = user.ID
+	}
+
+	counts, err := s.issueRepo.CountOpenByAssignees(project.ID, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	return common.Filter(project.Users, func(user model.User) bool {
+		return counts[user.ID] < limit
+	}), nil
+}
+
+// fillDates autofill the start date once the issue leave todo & the done date once it closed,
+// a reopened issue keep its previous done date
+func fillDates(prev *model.Issue, prevCategory types.StatusCategory, issue *model.Issue) {
+	// optional add project_setting statement: enable_autofill_date
+	if prev.StartDate == nil && issue.StartDate == nil &&
+		prevCategory == types.CategoryTodo &&
+		issue.StatusCategory != types.CategoryTodo {
+		issue.StartDate = common.Ptr(time.Now())
+	}
+
+	if prevCategory != types.CategoryDone &&
+		issue.StatusCategory == types.CategoryDone {
+		issue.DoneDate = common.Ptr(time.Now())
+	}
+
+	if prevCategory == types.CategoryDone &&
+		issue.StatusCategory != types.CategoryDone &&
+		prev.DoneDate != nil {
+		issue.DoneDate = prev.DoneDate
+	}
+}
+
+// checkTaskLimit reject the manual assignment over the project `TaskLimitPerUser`, unless overridden by an admin
+func (s *IssueService) checkTaskLimit(userID string, project *model.Project, issue *model.Issue, override bool) error {
+	limit := project.Setting.TaskLimitPerUser
+	if limit <= 0 || issue.AssigneeID == nil || *issue.AssigneeID == "" ||
+		issue.Status == types.IssueStatusDraft ||
+		issue.StatusCategory == types.CategoryDone {
+		return nil
+	}
+
+	counts, err := s.issueRepo.CountOpenByAssignees(project.ID, []string{*issue.AssigneeID})
+	if err != nil {
+		return err
+	}
+
+	count := counts[*issue.AssigneeID]
+	if count < limit {
+		return nil
+	}
+
+	if override && s.userRepo.ValidatePermission(userID, project.ID, types.RoleAdmin) == nil {
+		return nil
+	}
+
+	return fmt.Errorf("task limit reached: assignee already has %d open issues (limit %d)", count, limit)
+}
 
 func (s *IssueService) random(users []model.User) *string {
 	if len(users) == 0 {
@@ -565,6 +1835,7 @@ func (s *IssueService) random(users []model.User) *string {
 	return &users[randomIndex].ID
 }
 
+// `users` the available members, the index keep following `project.Users` so the saturated one is skipped
 func (s *IssueService) raoundRobin(project *model.Project, users []model.User) (*string, error) {
 	if len(users) == 0 {
 		return nil, nil
@@ -577,45 +1848,48 @@ func (s *IssueService) raoundRobin(project *model.Project, users []model.User) (
 		currentIndex = -1
 	}
 
-	nextIndex := (currentIndex + 1) % len(users)
-
-	if err := s.projectRepo.UpdateLastAssigned(
-		project.ID, nextIndex); err != nil {
-		return nil, err
-	}
-
-	return &users[nextIndex].ID, nil
-}
-
-func (s *IssueService) leastBusy(users []model.User) (*string, error) {
-	if len(users) == 0 {
-		return nil, nil
+	available := make(map[string]bool, len(users))
+	for _, user := range users {
+		available[user.ID] = true
 	}
 
-	var counts []schemas.IssueCount
+	members := project.Users
+	for i := 1; i <= len(members); i++ {
+		nextIndex := (currentIndex + i) % len(members)
+		if !available[members[nextIndex].ID] {
+			continue
+		}
 
-	for _, user := range users {
-		count, err := s.issueRepo.CountByUser(user.ID, types.IssueStatusDone)
-		if err != nil {
+		if err := s.projectRepo.UpdateLastAssigned(
+			project.ID, nextIndex); err != nil {
 			return nil, err
 		}
 
-		counts = append(counts, schemas.IssueCount{
-			UserID: user.ID,
-			Count:  int(count),
-		})
+		return &members[nextIndex].ID, nil
 	}
 
-	if len(counts) == 0 {
+	return nil, nil
+}
+
+// leastBusy the available member with the fewest open issues on the project, `users` already
+// cleared of the saturated ones
+func (s *IssueService) leastBusy(project *model.Project, users []model.User) (*string, error) {
+	if len(users) == 0 {
 		return nil, nil
 	}
 
-	minUser := counts[0]
-	for _, count := range counts[1:] {
-		if count.Count < minUser.Count {
-			minUser = count
+	ids := common.Map(users, func(user model.User) string { return user.ID })
+	counts, err := s.issueRepo.CountOpenByAssignees(project.ID, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	minUser := ids[0]
+	for _, ID := range ids[1:] {
+		if counts[ID] < counts[minUser] {
+			minUser = ID
 		}
 	}
 
-	return &minUser.UserID, nil
+	return &minUser, nil
 }
@@ -1,33 +1,54 @@
 package services
 
 import (
+	"context"
+	"fmt"
+	"mime/multipart"
+	"path/filepath"
+	"regexp"
+	"strings"
 	"webservices/src/model"
+	"webservices/src/pkg/common"
+	"webservices/src/pkg/logger"
+	"webservices/src/pkg/storage"
 	"webservices/src/repo"
 	"webservices/src/types"
 	"webservices/src/types/schemas"
 
+	"github.com/zishang520/socket.io/v2/socket"
 	"gorm.io/datatypes"
 	"gorm.io/gorm"
 )
 
+var safeExtension = regexp.MustCompile(`^\.[a-z0-9]+$`)
+
 type IssueItemService struct {
+	*baseService
 	itemRepo     *repo.IssueItemRepository
 	issueRepo    *repo.IssueRepository
 	userRepo     *repo.UserRepository
+	settingRepo  *repo.ProjectSettingRepository
 	activityRepo *repo.ActivityRepository
+	storage      storage.Storage
 }
 
 func NewIssueItemService(
+	io *socket.Server,
 	itemRepo *repo.IssueItemRepository,
 	issueRepo *repo.IssueRepository,
 	userRepo *repo.UserRepository,
+	settingRepo *repo.ProjectSettingRepository,
 	activityRepo *repo.ActivityRepository,
+	storage storage.Storage,
 ) *IssueItemService {
 	return &IssueItemService{
+		baseService:  newBaseService(io),
 		itemRepo:     itemRepo,
 		issueRepo:    issueRepo,
 		userRepo:     userRepo,
+		settingRepo:  settingRepo,
 		activityRepo: activityRepo,
+		storage:      storage,
 	}
 }
 
@@ -46,6 +67,10 @@ func (s *IssueItemService) Create(userID, issueID string, value schemas.CreateIt
 		return nil, err
 	}
 
+	if value.LinkedIssueID != nil && *value.LinkedIssueID != "" {
+		return s.createLink(userID, issue, value)
+	}
+
 	item := model.IssueItem{
 		IssueID:  issue.ID,
 		Type:     value.Type,
@@ -81,6 +106,194 @@ func (s *IssueItemService) Create(userID, issueID string, value schemas.CreateIt
 	}
 
 	s.issueRepo.Heartbeat(issue.ID)
+	s.publishItem(types.SyncItemCreate, userID, issue.ProjectID, &item)
+
+	return &item, nil
+}
+
+// createLink link the issues both ways, the linked issue get the inverse relation
+func (s *IssueItemService) createLink(userID string, issue *model.Issue, value schemas.CreateItem) (*model.IssueItem, error) {
+	if value.Type != types.LinkWork {
+		return nil, fmt.Errorf("failed to link: linked issue only allowed on %s item", types.LinkWork)
+	}
+
+	if value.LinkType == nil {
+		return nil, fmt.Errorf("failed to link: link type required")
+	}
+
+	target, err := s.issueRepo.GetByID(*value.LinkedIssueID)
+	if err != nil {
+		return nil, err
+	}
+
+	if target.ID == issue.ID {
+		return nil, fmt.Errorf("failed to link: cannot link an issue to itself")
+	}
+
+	if target.ProjectID != issue.ProjectID {
+		if err := s.userRepo.ValidatePermission(userID,
+			target.ProjectID, types.RoleEditor); err != nil {
+			return nil, err
+		}
+	}
+
+	count, err := s.itemRepo.CountLink(issue.ID, target.ID, *value.LinkType)
+	if err != nil {
+		return nil, err
+	}
+
+	if count > 0 {
+		return nil, fmt.Errorf("failed to link: issue already linked as %s", *value.LinkType)
+	}
+
+	inverseType := value.LinkType.Inverse()
+
+	item := model.IssueItem{
+		IssueID:       issue.ID,
+		Type:          types.LinkWork,
+		Text:          value.Text,
+		LinkType:      value.LinkType,
+		LinkedIssueID: &target.ID,
+	}
+
+	inverse := model.IssueItem{
+		IssueID:       target.ID,
+		Type:          types.LinkWork,
+		Text:          value.Text,
+		LinkType:      &inverseType,
+		LinkedIssueID: &issue.ID,
+	}
+
+	err = s.itemRepo.DB().Transaction(func(tx *gorm.DB) error {
+		for _, v := range []*model.IssueItem{&item, &inverse} {
+			if err := s.itemRepo.CreateTx(tx, v); err != nil {
+				return err
+			}
+
+			activity := model.RecentActivity{
+				UserID:       userID,
+				ProjectID:    &issue.ProjectID,
+				IssueID:      &v.IssueID,
+				ActivityType: types.IssueItemCreate,
+				NewValues: &datatypes.JSONMap{
+					"type":            v.Type,
+					"link_type":       v.LinkType,
+					"linked_issue_id": v.LinkedIssueID,
+					"text":            v.Text,
+				},
+			}
+
+			if v.IssueID == target.ID {
+				activity.ProjectID = &target.ProjectID
+			}
+
+			if err := s.activityRepo.CreateTx(tx, &activity); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.issueRepo.Heartbeat(issue.ID)
+	s.issueRepo.Heartbeat(target.ID)
+
+	item.LinkedIssue = target
+	inverse.LinkedIssue = issue
+	s.publishItem(types.SyncItemCreate, userID, issue.ProjectID, &item)
+	s.publishItem(types.SyncItemCreate, userID, target.ProjectID, &inverse)
+
+	return &item, nil
+}
+
+// Upload store the file into the storage as an attachment item,
+// following the project `AllowAttachments` & `MaxAttachmentSize` settings
+func (s *IssueItemService) Upload(userID, issueID string, file *multipart.FileHeader) (*model.IssueItem, error) {
+	issue, err := s.issueRepo.GetByID(issueID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.ValidatePermission(userID,
+		issue.ProjectID, types.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	setting, err := s.settingRepo.GetByProjectID(issue.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !setting.AllowAttachments {
+		return nil, fmt.Errorf("attachments are disabled on this project")
+	}
+
+	if setting.MaxAttachmentSize > 0 && file.Size > int64(setting.MaxAttachmentSize)<<20 {
+		return nil, fmt.Errorf("attachment too large: the limit is %d MB", setting.MaxAttachmentSize)
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read attachment: %w", err)
+	}
+	defer src.Close()
+
+	mimeType := file.Header.Get("Content-Type")
+	if mimeType == "" {
+		mimeType = "application/octet-stream"
+	}
+
+	key := fmt.Sprintf("%s/%s/%s%s", issue.ProjectID, issue.ID,
+		common.Random(16), s.extension(file.Filename))
+
+	url, err := s.storage.Put(context.Background(), key, src, file.Size, mimeType)
+	if err != nil {
+		return nil, err
+	}
+
+	item := model.IssueItem{
+		IssueID:  issue.ID,
+		Type:     types.Attachment,
+		PublicID: &key,
+		Url:      &url,
+		Text:     common.Ptr(filepath.Base(file.Filename)),
+		Storage:  common.Ptr(s.storage.Driver()),
+		Size:     &file.Size,
+		MimeType: &mimeType,
+	}
+
+	activity := model.RecentActivity{
+		UserID:       userID,
+		ProjectID:    &issue.ProjectID,
+		IssueID:      &issue.ID,
+		ActivityType: types.IssueItemCreate,
+		NewValues: &datatypes.JSONMap{
+			"type":      item.Type,
+			"public_id": item.PublicID,
+			"text":      item.Text,
+			"size":      item.Size,
+		},
+	}
+
+	err = s.itemRepo.DB().Transaction(func(tx *gorm.DB) error {
+		if err := s.itemRepo.CreateTx(tx, &item); err != nil {
+			return err
+		}
+
+		return s.activityRepo.CreateTx(tx, &activity)
+	})
+
+	if err != nil {
+		s.deleteBlob(&item)
+		return nil, err
+	}
+
+	s.issueRepo.Heartbeat(issue.ID)
+	s.publishItem(types.SyncItemCreate, userID, issue.ProjectID, &item)
 
 	return &item, nil
 }
@@ -109,10 +322,13 @@ func (s *IssueItemService) Update(ID, userID, issueID string, value schemas.Crea
 		},
 	}
 
-	item.Type = value.Type
-	item.AssetID = value.AssetID
-	item.PublicID = value.PublicID
-	item.Url = value.Url
+	// uploaded file & issue link keep pointing to its target, only the text able to change
+	if item.Storage == nil && item.LinkedIssueID == nil {
+		item.Type = value.Type
+		item.AssetID = value.AssetID
+		item.PublicID = value.PublicID
+		item.Url = value.Url
+	}
 	item.Text = value.Text
 
 	err = s.itemRepo.DB().Transaction(func(tx *gorm.DB) error {
@@ -135,6 +351,7 @@ func (s *IssueItemService) Update(ID, userID, issueID string, value schemas.Crea
 	}
 
 	s.issueRepo.Heartbeat(item.IssueID)
+	s.publishItem(types.SyncItemUpdate, userID, item.Issue.ProjectID, item)
 
 	return item, nil
 }
@@ -150,6 +367,21 @@ func (s *IssueItemService) Delete(ID, userID string) (*model.IssueItem, error) {
 		return nil, err
 	}
 
+	var linked *model.Issue
+	if item.LinkedIssueID != nil && item.LinkType != nil {
+		linked, err = s.issueRepo.GetByID(*item.LinkedIssueID)
+		if err != nil {
+			return nil, err
+		}
+
+		if linked.ProjectID != item.Issue.ProjectID {
+			if err := s.userRepo.ValidatePermission(userID,
+				linked.ProjectID, types.RoleEditor); err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	activity := model.RecentActivity{
 		UserID:       userID,
 		ProjectID:    &item.Issue.ProjectID,
@@ -163,11 +395,25 @@ func (s *IssueItemService) Delete(ID, userID string) (*model.IssueItem, error) {
 		},
 	}
 
+	if linked != nil {
+		(*activity.OldValues)["link_type"] = item.LinkType
+		(*activity.OldValues)["linked_issue_id"] = item.LinkedIssueID
+	}
+
+	var inverses []model.IssueItem
 	err = s.itemRepo.DB().Transaction(func(tx *gorm.DB) error {
 		if err := s.itemRepo.DeleteTx(tx, item.ID); err != nil {
 			return err
 		}
 
+		// the inverse link removed along
+		if linked != nil {
+			if inverses, err = s.itemRepo.DeleteLinkTx(tx, linked.ID,
+				item.IssueID, item.LinkType.Inverse()); err != nil {
+				return err
+			}
+		}
+
 		return s.activityRepo.CreateTx(tx, &activity)
 	})
 
@@ -175,7 +421,58 @@ func (s *IssueItemService) Delete(ID, userID string) (*model.IssueItem, error) {
 		return nil, err
 	}
 
+	s.deleteBlob(item)
 	s.issueRepo.Heartbeat(item.IssueID)
+	s.publishItem(types.SyncItemDelete, userID, item.Issue.ProjectID, item)
+	if linked != nil {
+		s.issueRepo.Heartbeat(linked.ID)
+		for i := range inverses {
+			s.publishItem(types.SyncItemDelete, userID, linked.ProjectID, &inverses[i])
+		}
+	}
 
 	return item, nil
 }
+
+// publishItem push the item change to the board & the viewers of its issue
+func (s *IssueItemService) publishItem(event types.SyncEvent, userID, projectID string, item *model.IssueItem) {
+	delta := Delta{
+		Type:      event,
+		ProjectID: projectID,
+		IssueID:   item.IssueID,
+		ID:        item.ID,
+		UserID:    userID,
+	}
+
+	if event != types.SyncItemDelete {
+		delta.Data = item
+	}
+
+	s.publish(delta)
+}
+
+// deleteBlob remove the uploaded file from the storage, the client-supplied one is left untouched
+func (s *IssueItemService) deleteBlob(item *model.IssueItem) {
+	if item.Storage == nil || item.PublicID == nil {
+		return
+	}
+
+	if *item.Storage != s.storage.Driver() {
+		logger.Errorf("failed to delete attachment %s: stored on %s, current storage is %s",
+			*item.PublicID, *item.Storage, s.storage.Driver())
+		return
+	}
+
+	if err := s.storage.Delete(context.Background(), *item.PublicID); err != nil {
+		logger.Errorf("failed to delete attachment %s: %s", *item.PublicID, err)
+	}
+}
+
+// extension keep a sanitized file extension, so the storage key only has safe characters
+func (s *IssueItemService) extension(filename string) string {
+	ext := strings.ToLower(filepath.Ext(filename))
+	if len(ext) > 10 || !safeExtension.MatchString(ext) {
+		return ""
+	}
+	return ext
+}
@@ -0,0 +1,467 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"webservices/src/model"
+	"webservices/src/pkg/logger"
+	"webservices/src/pkg/rrule"
+	"webservices/src/repo"
+	"webservices/src/types"
+	"webservices/src/types/schemas"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+const maxIssueTemplates = 100
+
+type IssueTemplateService struct {
+	templateRepo   *repo.IssueTemplateRepository
+	recurrenceRepo *repo.RecurrenceRepository
+	userRepo       *repo.UserRepository
+	issueService   *IssueService
+}
+
+func NewIssueTemplateService(
+	templateRepo *repo.IssueTemplateRepository,
+	recurrenceRepo *repo.RecurrenceRepository,
+	userRepo *repo.UserRepository,
+	issueService *IssueService,
+) *IssueTemplateService {
+	return &IssueTemplateService{
+		templateRepo:   templateRepo,
+		recurrenceRepo: recurrenceRepo,
+		userRepo:       userRepo,
+		issueService:   issueService,
+	}
+}
+
+func (s *IssueTemplateService) GetTemplates(userID, projectID string) ([]model.IssueTemplate, error) {
+	if err := s.userRepo.ValidatePermission(userID,
+		projectID, types.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	return s.templateRepo.GetByProjectID(projectID)
+}
+
+func (s *IssueTemplateService) CreateTemplate(userID, projectID string, value schemas.CreateIssueTemplate) (*model.IssueTemplate, error) {
+	if err := s.userRepo.ValidatePermission(userID,
+		projectID, types.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	templates, err := s.templateRepo.GetByProjectID(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(templates) >= maxIssueTemplates {
+		return nil, fmt.Errorf("invalid template: a project holds up to %d templates", maxIssueTemplates)
+	}
+
+	template := model.IssueTemplate{
+		ProjectID: projectID,
+		CreatorID: userID,
+	}
+
+	if err := s.fill(&template, value); err != nil {
+		return nil, err
+	}
+
+	if err := s.templateRepo.DB().Transaction(func(tx *gorm.DB) error {
+		return s.templateRepo.CreateTx(tx, &template)
+	}); err != nil {
+		return nil, err
+	}
+
+	return &template, nil
+}
+
+func (s *IssueTemplateService) UpdateTemplate(userID, projectID, ID string, value schemas.CreateIssueTemplate) (*model.IssueTemplate, error) {
+	template, err := s.getTemplate(projectID, ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.ValidatePermission(userID,
+		template.ProjectID, types.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	if err := s.fill(template, value); err != nil {
+		return nil, err
+	}
+
+	if err := s.templateRepo.DB().Transaction(func(tx *gorm.DB) error {
+		return s.templateRepo.UpdateTx(tx, template)
+	}); err != nil {
+		return nil, err
+	}
+
+	return template, nil
+}
+
+// DeleteTemplate the recurrences of the template are removed along
+func (s *IssueTemplateService) DeleteTemplate(userID, projectID, ID string) error {
+	template, err := s.getTemplate(projectID, ID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.userRepo.ValidatePermission(userID,
+		template.ProjectID, types.RoleAdmin); err != nil {
+		return err
+	}
+
+	return s.templateRepo.DB().Transaction(func(tx *gorm.DB) error {
+		return s.templateRepo.DeleteTx(tx, template.ID)
+	})
+}
+
+// Apply create the issue from the template, the title placeholders filled with the `at` date
+func (s *IssueTemplateService) Apply(userID, projectID, ID string, at time.Time) (*model.Issue, error) {
+	template, err := s.getTemplate(projectID, ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.apply(userID, template, at)
+}
+
+// apply create the issue through `IssueService.Create` then the checklist as its subtasks.
+// the issue returned even when a subtask failed, the failure listed on its warnings
+func (s *IssueTemplateService) apply(userID string, template *model.IssueTemplate, at time.Time) (*model.Issue, error) {
+	value := schemas.CreateIssue{
+		ProjectID:    &template.ProjectID,
+		Title:        template.RenderTitle(at),
+		Type:         template.Type,
+		Description:  template.Description,
+		AssigneeID:   template.AssigneeID,
+		CustomFields: template.CustomFields,
+	}
+
+	if template.Priority != nil {
+		value.Priority = *template.Priority
+	}
+
+	issue, err := s.issueService.Create(userID, value)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range template.Checklist {
+		if _, err := s.issueService.Create(userID, schemas.CreateIssue{
+			ProjectID:  &template.ProjectID,
+			Title:      item,
+			Type:       types.IssueTypeSubtask,
+			Priority:   issue.Priority,
+			AssigneeID: issue.AssigneeID,
+			Parents:    &issue.ID,
+		}); err != nil {
+			issue.Warnings = append(issue.Warnings, fmt.Sprintf("failed to create subtask %q: %s", item, err))
+		}
+	}
+
+	return issue, nil
+}
+
+func (s *IssueTemplateService) GetRecurrences(userID, projectID string) ([]model.IssueRecurrence, error) {
+	if err := s.userRepo.ValidatePermission(userID,
+		projectID, types.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	return s.recurrenceRepo.GetByProjectID(projectID)
+}
+
+// CreateRecurrence the issues created on behalf of the user, from the start date or today whichever later
+func (s *IssueTemplateService) CreateRecurrence(userID, projectID string, value schemas.CreateRecurrence) (*model.IssueRecurrence, error) {
+	if err := s.userRepo.ValidatePermission(userID,
+		projectID, types.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	recurrence := model.IssueRecurrence{
+		ProjectID: projectID,
+		CreatorID: userID,
+		Active:    true,
+	}
+
+	if err := s.fillRecurrence(&recurrence, value); err != nil {
+		return nil, err
+	}
+
+	if err := s.recurrenceRepo.DB().Transaction(func(tx *gorm.DB) error {
+		return s.recurrenceRepo.CreateTx(tx, &recurrence)
+	}); err != nil {
+		return nil, err
+	}
+
+	return &recurrence, nil
+}
+
+// UpdateRecurrence the occurrences already generated are never generated again
+func (s *IssueTemplateService) UpdateRecurrence(userID, projectID, ID string, value schemas.CreateRecurrence) (*model.IssueRecurrence, error) {
+	recurrence, err := s.getRecurrence(projectID, ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.ValidatePermission(userID,
+		recurrence.ProjectID, types.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	if err := s.fillRecurrence(recurrence, value); err != nil {
+		return nil, err
+	}
+
+	if err := s.recurrenceRepo.DB().Transaction(func(tx *gorm.DB) error {
+		return s.recurrenceRepo.UpdateTx(tx, recurrence)
+	}); err != nil {
+		return nil, err
+	}
+
+	return recurrence, nil
+}
+
+func (s *IssueTemplateService) DeleteRecurrence(userID, projectID, ID string) error {
+	recurrence, err := s.getRecurrence(projectID, ID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.userRepo.ValidatePermission(userID,
+		recurrence.ProjectID, types.RoleAdmin); err != nil {
+		return err
+	}
+
+	return s.recurrenceRepo.DB().Transaction(func(tx *gorm.DB) error {
+		return s.recurrenceRepo.DeleteTx(tx, recurrence.ID)
+	})
+}
+
+// Run generate the issues of the due recurrences, returning the number of issue created.
+// a recurrence missing several occurrences, e.g. the server was down, only catch up the latest one.
+// the occurrence claimed on the recurrence runs before the issue created, so it safe to call repeatedly
+// and a crash in between skip the occurrence rather than duplicate it
+func (s *IssueTemplateService) Run(ctx context.Context, now time.Time) (int, error) {
+	today := localDay(now)
+	recurrences, err := s.recurrenceRepo.GetDue(today)
+	if err != nil {
+		return 0, err
+	}
+
+	created := 0
+	for i := range recurrences {
+		if err := ctx.Err(); err != nil {
+			return created, err
+		}
+
+		recurrence := &recurrences[i]
+		rule, err := rrule.Parse(recurrence.Rule)
+		if err != nil || recurrence.Template == nil {
+			logger.Errorf("failed to run recurrence recurrence_id=%s: invalid rule or template", recurrence.ID)
+			continue
+		}
+
+		start := localDay(recurrence.StartDate)
+		occurrence := localDay(*recurrence.NextRunAt)
+		for {
+			next, ok := rule.Next(start, occurrence)
+			if !ok || next.After(today) {
+				break
+			}
+			occurrence = next
+		}
+
+		ok, err := s.generate(recurrence, occurrence)
+		if err != nil {
+			logger.Errorf("failed to run recurrence recurrence_id=%s: %s", recurrence.ID, err)
+			continue
+		}
+
+		if ok {
+			created++
+		}
+
+		var nextRun *time.Time
+		if next, ok := rule.Next(start, occurrence); ok {
+			nextRun = &next
+		}
+
+		if err := s.recurrenceRepo.DB().Transaction(func(tx *gorm.DB) error {
+			return s.recurrenceRepo.AdvanceTx(tx, recurrence.ID, occurrence, nextRun)
+		}); err != nil {
+			return created, err
+		}
+	}
+
+	return created, nil
+}
+
+// generate false when the occurrence already claimed by an earlier run, the claim is released
+// when the issue failed to create so the occurrence retried on the next run
+func (s *IssueTemplateService) generate(recurrence *model.IssueRecurrence, occurrence time.Time) (bool, error) {
+	run := model.RecurrenceRun{
+		RecurrenceID: recurrence.ID,
+		Occurrence:   occurrence,
+	}
+
+	var claimed bool
+	if err := s.recurrenceRepo.DB().Transaction(func(tx *gorm.DB) (err error) {
+		claimed, err = s.recurrenceRepo.ClaimTx(tx, &run)
+		return err
+	}); err != nil || !claimed {
+		return false, err
+	}
+
+	issue, err := s.apply(recurrence.CreatorID, recurrence.Template, occurrence)
+	if err != nil {
+		if releaseErr := s.recurrenceRepo.DB().Transaction(func(tx *gorm.DB) error {
+			return s.recurrenceRepo.ReleaseTx(tx, &run)
+		}); releaseErr != nil {
+			logger.Errorf("failed to release recurrence run recurrence_id=%s: %s", recurrence.ID, releaseErr)
+		}
+		return false, err
+	}
+
+	for _, warning := range issue.Warnings {
+		logger.Warnf("recurrence recurrence_id=%s issue_id=%s: %s", recurrence.ID, issue.ID, warning)
+	}
+
+	return true, s.recurrenceRepo.DB().Transaction(func(tx *gorm.DB) error {
+		return s.recurrenceRepo.SetRunIssueTx(tx, &run, issue.ID)
+	})
+}
+
+func (s *IssueTemplateService) fill(template *model.IssueTemplate, value schemas.CreateIssueTemplate) error {
+	name := strings.TrimSpace(value.Name)
+	exists, err := s.templateRepo.ExistsByName(template.ProjectID, name, template.ID)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		return fmt.Errorf("template %q already exists", name)
+	}
+
+	if value.AssigneeID != nil && *value.AssigneeID != "" {
+		if err := s.userRepo.ValidatePermission(*value.AssigneeID,
+			template.ProjectID, types.RoleViewer); err != nil {
+			return fmt.Errorf("invalid template: assignee is not a project member")
+		}
+	} else {
+		value.AssigneeID = nil
+	}
+
+	checklist := make([]string, 0, len(value.Checklist))
+	for _, item := range value.Checklist {
+		if item = strings.TrimSpace(item); item != "" {
+			checklist = append(checklist, item)
+		}
+	}
+
+	// the required fields enforced once the issue created, the project may define them later
+	fields, err := s.issueService.checkCustomFields(template.ProjectID, nil, value.CustomFields, false)
+	if err != nil {
+		return err
+	}
+
+	template.Name = name
+	template.TitlePattern = strings.TrimSpace(value.TitlePattern)
+	template.Description = value.Description
+	template.Type = value.Type
+	template.Priority = value.Priority
+	template.AssigneeID = value.AssigneeID
+	template.Checklist = checklist
+	template.CustomFields = fields
+
+	if template.Type == "" {
+		template.Type = types.IssueTypeTask
+	}
+
+	if template.CustomFields == nil {
+		template.CustomFields = datatypes.JSONMap{}
+	}
+
+	return nil
+}
+
+// fillRecurrence the next run starting from the start date, today, or after the last run whichever later
+func (s *IssueTemplateService) fillRecurrence(recurrence *model.IssueRecurrence, value schemas.CreateRecurrence) error {
+	template, err := s.getTemplate(recurrence.ProjectID, value.TemplateID)
+	if err != nil {
+		return err
+	}
+
+	rule, err := rrule.Parse(value.Rule)
+	if err != nil {
+		return err
+	}
+
+	today := localDay(time.Now())
+	start := today
+	if value.StartDate != nil {
+		start = localDay(value.StartDate.Time)
+	}
+
+	after := start.AddDate(0, 0, -1)
+	if yesterday := today.AddDate(0, 0, -1); yesterday.After(after) {
+		after = yesterday
+	}
+
+	if recurrence.LastRunAt != nil && localDay(*recurrence.LastRunAt).After(after) {
+		after = localDay(*recurrence.LastRunAt)
+	}
+
+	recurrence.TemplateID = template.ID
+	recurrence.Template = template
+	recurrence.Rule = rule.String()
+	recurrence.StartDate = start
+	recurrence.NextRunAt = nil
+	if next, ok := rule.Next(start, after); ok {
+		recurrence.NextRunAt = &next
+	}
+
+	if value.Active != nil {
+		recurrence.Active = *value.Active
+	}
+
+	return nil
+}
+
+func (s *IssueTemplateService) getTemplate(projectID, ID string) (*model.IssueTemplate, error) {
+	template, err := s.templateRepo.GetByID(ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if template.ProjectID != projectID {
+		return nil, fmt.Errorf("failed to fetch issue template: record not found")
+	}
+
+	return template, nil
+}
+
+func (s *IssueTemplateService) getRecurrence(projectID, ID string) (*model.IssueRecurrence, error) {
+	recurrence, err := s.recurrenceRepo.GetByID(ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if recurrence.ProjectID != projectID {
+		return nil, fmt.Errorf("failed to fetch recurrence: record not found")
+	}
+
+	return recurrence, nil
+}
+
+// localDay the midnight of the day in the server timezone, the date columns read back as UTC
+func localDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"webservices/src/model"
+	"webservices/src/repo"
+	"webservices/src/types"
+	"webservices/src/types/schemas"
+
+	"gorm.io/gorm"
+)
+
+type LabelService struct {
+	labelRepo *repo.LabelRepository
+	userRepo  *repo.UserRepository
+}
+
+func NewLabelService(
+	labelRepo *repo.LabelRepository,
+	userRepo *repo.UserRepository,
+) *LabelService {
+	return &LabelService{
+		labelRepo: labelRepo,
+		userRepo:  userRepo,
+	}
+}
+
+func (s *LabelService) GetByProject(userID, projectID string) ([]model.Label, error) {
+	if err := s.userRepo.ValidatePermission(userID,
+		projectID, types.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	return s.labelRepo.GetByProjectID(projectID)
+}
+
+func (s *LabelService) Create(userID, projectID string, value schemas.CreateLabel) (*model.Label, error) {
+	if err := s.userRepo.ValidatePermission(userID,
+		projectID, types.RoleEditor); err != nil {
+		return nil, err
+	}
+
+	label := model.Label{
+		ProjectID: projectID,
+		Name:      strings.TrimSpace(value.Name),
+		Color:     strings.ToLower(value.Color),
+	}
+
+	if err := s.checkName(&label, nil); err != nil {
+		return nil, err
+	}
+
+	if err := s.labelRepo.DB().Transaction(func(tx *gorm.DB) error {
+		return s.labelRepo.CreateTx(tx, &label)
+	}); err != nil {
+		return nil, err
+	}
+
+	return &label, nil
+}
+
+func (s *LabelService) Update(userID, projectID, ID string, value schemas.CreateLabel) (*model.Label, error) {
+	label, err := s.get(projectID, ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.ValidatePermission(userID,
+		label.ProjectID, types.RoleEditor); err != nil {
+		return nil, err
+	}
+
+	label.Name = strings.TrimSpace(value.Name)
+	label.Color = strings.ToLower(value.Color)
+
+	if err := s.checkName(label, &label.ID); err != nil {
+		return nil, err
+	}
+
+	if err := s.labelRepo.DB().Transaction(func(tx *gorm.DB) error {
+		return s.labelRepo.UpdateTx(tx, label)
+	}); err != nil {
+		return nil, err
+	}
+
+	return label, nil
+}
+
+// Delete remove the label from the catalog, the issues using it lose it through the cascade
+func (s *LabelService) Delete(userID, projectID, ID string) error {
+	label, err := s.get(projectID, ID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.userRepo.ValidatePermission(userID,
+		label.ProjectID, types.RoleAdmin); err != nil {
+		return err
+	}
+
+	return s.labelRepo.DB().Transaction(func(tx *gorm.DB) error {
+		return s.labelRepo.DeleteTx(tx, label.ID)
+	})
+}
+
+func (s *LabelService) get(projectID, ID string) (*model.Label, error) {
+	label, err := s.labelRepo.GetByID(ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if label.ProjectID != projectID {
+		return nil, fmt.Errorf("failed to fetch label: record not found")
+	}
+
+	return label, nil
+}
+
+func (s *LabelService) checkName(label *model.Label, excludeID *string) error {
+	if label.Name == "" {
+		return fmt.Errorf("label name required")
+	}
+
+	exists, err := s.labelRepo.ExistsName(label.ProjectID, label.Name, excludeID)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		return fmt.Errorf("label %q already exists", label.Name)
+	}
+
+	return nil
+}
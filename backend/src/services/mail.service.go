@@ -201,6 +201,43 @@ Click here to view details: %s/issue/%s
 	return s.send(options)
 }
 
+// Mention tell the user they were mentioned, on the comment when given or else on the issue description
+func (s *MailService) Mention(user *model.User, receiver *model.User, issue *model.Issue, comment *model.Comment) error {
+	from := fmt.Sprintf(
+		"%s Notifications <noreply@%s>", s.AppName,
+		strings.ToLower(strings.ReplaceAll(s.AppName, " ", "")),
+	)
+
+	place, excerpt := "the description of", textOf(issue.Description)
+	if comment != nil {
+		place, excerpt = "a comment on", comment.Message
+	}
+
+	message := fmt.Sprintf(`
+%s mentioned you in %s "%s":
+
+%s
+
+Click here to view details: %s/issue/%s
+`,
+		user.Name,
+		place,
+		issue.Title,
+		c.Truncate(excerpt, 500),
+		s.BaseUrl,
+		issue.ID,
+	)
+
+	options := MailOptions{
+		From:    from,
+		To:      []string{receiver.Email},
+		Subject: fmt.Sprintf("💬 %s mentioned you: %s", user.Name, issue.Title),
+		Body:    strings.TrimSpace(message),
+	}
+
+	return s.send(options)
+}
+
 func (s *MailService) VerifyToken(token string) (map[string]any, error) {
 	decrypted, err := cipher.Decrypt[map[string]any](s.Secret, token)
 	if err != nil {
@@ -2,14 +2,18 @@ package services
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 	"webservices/src/model"
 	"webservices/src/pkg/common"
 	"webservices/src/pkg/logger"
+	"webservices/src/pkg/pagination"
 	"webservices/src/repo"
 	"webservices/src/types"
 
 	"github.com/zishang520/socket.io/v2/socket"
 	"gorm.io/datatypes"
+	"gorm.io/gorm"
 )
 
 type NotificationService struct {
@@ -20,6 +24,9 @@ type NotificationService struct {
 	commentRepo     *repo.CommentRepository
 	notifRepo       *repo.NotificationRepository
 	userProjectRepo *repo.UserProjectRepository
+	reminderRepo    *repo.IssueReminderRepository
+	watcherRepo     *repo.WatcherRepository
+	mail            *MailService
 }
 
 func NewNotificationService(
@@ -30,6 +37,9 @@ func NewNotificationService(
 	commentRepo *repo.CommentRepository,
 	notifRepo *repo.NotificationRepository,
 	userProjectRepo *repo.UserProjectRepository,
+	reminderRepo *repo.IssueReminderRepository,
+	watcherRepo *repo.WatcherRepository,
+	mail *MailService,
 ) *NotificationService {
 	return &NotificationService{
 		baseService:     newBaseService(io),
@@ -39,11 +49,14 @@ func NewNotificationService(
 		commentRepo:     commentRepo,
 		notifRepo:       notifRepo,
 		userProjectRepo: userProjectRepo,
+		reminderRepo:    reminderRepo,
+		watcherRepo:     watcherRepo,
+		mail:            mail,
 	}
 }
 
-func (s *NotificationService) GetByUser(userID string) ([]model.Notification, error) {
-	return s.notifRepo.GetByUserID(userID)
+func (s *NotificationService) GetByUser(userID string, params pagination.Params) (*pagination.Page[model.Notification], error) {
+	return s.notifRepo.GetByUserID(userID, params)
 }
 
 func (s *NotificationService) Read(ID string) error {
@@ -54,65 +67,85 @@ func (s *NotificationService) ReadAll(userID string) error {
 	return s.notifRepo.ReadByUserID(userID)
 }
 
+// PushIssue notify the issue watchers, the assignee of a new issue told about the assignment
+// following the project `NotifyOnAssignment` setting. the assignee subscribed along the assignment,
+// one who unwatched the issue no longer notified. the mentioned users receive the mention instead
 func (s *NotificationService) PushIssue(user *model.User, issue *model.Issue, isNew bool) {
 	project := user.Project
-	settings := project.Setting
-
-	notificationType := types.NotificationSystem
-	if issue.AssigneeID != nil {
-		notificationType = types.NotificationTask
+	if project == nil || project.ID != issue.ProjectID {
+		var err error
+		if project, err = s.projectRepo.GetIncludeDetail(issue.ProjectID); err != nil {
+			logger.Errorf("failed to fetch issue project: %s", err)
+			return
+		}
 	}
 
-	var title, message string
+	settings := project.Setting
+	notifyAssignment := settings == nil || settings.NotifyOnAssignment
 
-	if issue.Status == types.IssueStatusDone {
-		title = fmt.Sprintf("✅ Completed: %s", issue.Title)
-		message = fmt.Sprintf(
-			`Great work! %s marked this issue as completed: "%s"`,
-			user.Name,
-			issue.Title,
-		)
-	} else if isNew {
-		title = fmt.Sprintf("📌 New Task: %s", issue.Title)
-		message = fmt.Sprintf(
-			`You've been assigned to a new task in project %s: "%s" Priority: %s. Click to view details`,
-			project.Name,
-			issue.Title,
-			issue.Priority,
-		)
-	} else {
-		title = fmt.Sprintf("🔄 Updated: %s", issue.Title)
-		message = fmt.Sprintf(`The issue "%s" was updated: Click to see changes`, issue.Title)
+	watchers, err := s.watcherRepo.GetUserIDs(issue.ID, user.ID)
+	if err != nil {
+		logger.Errorf("failed to fetch issue watchers: %s", err)
+		return
 	}
 
+	// the mentioned users notified apart
+	recipients := common.Filter(watchers, func(ID string) bool {
+		return !slices.Contains(issue.Mentions, ID)
+	})
+
 	action := "updated"
 	if isNew {
 		action = "created"
 	}
 
-	notification := model.Notification{
-		Type:    notificationType,
-		Title:   title,
-		Message: message,
-		Metadata: datatypes.JSONMap{
-			"action":     action,
-			"issue_id":   issue.ID,
-			"parents":    issue.Parents,
-			"project_id": issue.ProjectID,
-			"link":       "/issues/" + issue.ID,
-		},
-	}
+	for _, userID := range recipients {
+		assigned := notifyAssignment && issue.AssigneeID != nil && *issue.AssigneeID == userID
+
+		notificationType := types.NotificationSystem
+		if assigned {
+			notificationType = types.NotificationTask
+		}
 
-	recipients := make([]string, 0)
+		var title, message string
 
-	if (settings == nil || settings.NotifyOnAssignment) &&
-		issue.AssigneeID != nil &&
-		*issue.AssigneeID != user.ID {
-		recipients = append(recipients, *issue.AssigneeID)
-	}
+		if issue.StatusCategory == types.CategoryDone {
+			title = fmt.Sprintf("✅ Completed: %s", issue.Title)
+			message = fmt.Sprintf(
+				`Great work! %s marked this issue as completed: "%s"`,
+				user.Name,
+				issue.Title,
+			)
+		} else if isNew && assigned {
+			title = fmt.Sprintf("📌 New Task: %s", issue.Title)
+			message = fmt.Sprintf(
+				`You've been assigned to a new task in project %s: "%s" Priority: %s. Click to view details`,
+				project.Name,
+				issue.Title,
+				issue.Priority,
+			)
+		} else if isNew {
+			title = fmt.Sprintf("🆕 New issue: %s", issue.Title)
+			message = fmt.Sprintf(`%s created "%s" in project %s`, user.Name, issue.Title, project.Name)
+		} else {
+			title = fmt.Sprintf("🔄 Updated: %s", issue.Title)
+			message = fmt.Sprintf(`The issue "%s" was updated: Click to see changes`, issue.Title)
+		}
+
+		notification := model.Notification{
+			UserID:  userID,
+			Type:    notificationType,
+			Title:   title,
+			Message: message,
+			Metadata: datatypes.JSONMap{
+				"action":     action,
+				"issue_id":   issue.ID,
+				"parents":    issue.Parents,
+				"project_id": issue.ProjectID,
+				"link":       "/issues/" + issue.ID,
+			},
+		}
 
-	for _, userID := range recipients {
-		notification.UserID = userID
 		if err := s.notifRepo.Create(&notification); err != nil {
 			logger.Errorf("Failed to create notification for user %s: %v", userID, err)
 			continue
@@ -121,36 +154,257 @@ func (s *NotificationService) PushIssue(user *model.User, issue *model.Issue, is
 	}
 }
 
-func (s *NotificationService) PushComment(user model.User, comment model.Comment) error {
-	issue, err := s.issueRepo.GetByID(comment.IssueID)
+// PushBulk notify every user concerned by a bulk change once, listing their issues of the batch
+func (s *NotificationService) PushBulk(user *model.User, result *BulkResult) {
+	titles := make(map[string]*model.Issue, len(result.Issues))
+	for i := range result.Issues {
+		titles[result.Issues[i].ID] = &result.Issues[i]
+	}
+
+	verb := map[types.BulkAction]string{
+		types.BulkStatus:   "changed the status of",
+		types.BulkPriority: "changed the priority of",
+		types.BulkAssignee: "reassigned",
+		types.BulkLabel:    "labeled",
+		types.BulkUnlabel:  "removed labels from",
+		types.BulkParent:   "moved",
+		types.BulkSprint:   "changed the sprint of",
+		types.BulkDelete:   "deleted",
+	}[result.Action]
+
+	for ID, issueIDs := range result.Recipients {
+		names := make([]string, 0, 3)
+		for _, issueID := range issueIDs {
+			if len(names) < 3 {
+				names = append(names, fmt.Sprintf("%q", titles[issueID].Title))
+			}
+		}
+
+		noun := "issues"
+		if len(issueIDs) == 1 {
+			noun = "issue"
+		}
+
+		message := fmt.Sprintf("%s %s %d %s: %s", user.Name, verb, len(issueIDs), noun, strings.Join(names, ", "))
+		if len(issueIDs) > len(names) {
+			message = fmt.Sprintf("%s and %d more", message, len(issueIDs)-len(names))
+		}
+
+		notification := model.Notification{
+			UserID:  ID,
+			Type:    types.NotificationTask,
+			Title:   fmt.Sprintf("🗂️ %d %s updated", len(issueIDs), noun),
+			Message: message,
+			Metadata: datatypes.JSONMap{
+				"action":     "bulk_" + result.Action.String(),
+				"issue_ids":  issueIDs,
+				"project_id": titles[issueIDs[0]].ProjectID,
+				"sender_id":  user.ID,
+				"link":       "/issues",
+			},
+		}
+
+		if err := s.notifRepo.Create(&notification); err != nil {
+			logger.Errorf("failed to create bulk notification: %s to %s", err, ID)
+			continue
+		}
+
+		s.emit(ID, "notification:push", notification)
+	}
+}
+
+// PushReviewRequest notify the project approvers (admin/owner) an issue waiting their sign-off
+func (s *NotificationService) PushReviewRequest(user *model.User, issue *model.Issue) error {
+	ids, err := s.userProjectRepo.GetUserIDsByRole(issue.ProjectID, types.RoleAdmin, types.RoleOwner)
 	if err != nil {
 		return err
 	}
 
-	recipients := make([]string, 0)
+	for _, ID := range ids {
+		if ID == user.ID {
+			continue
+		}
+
+		notification := model.Notification{
+			UserID: ID,
+			Type:   types.NotificationReview,
+			Title:  fmt.Sprintf("📝 Approval needed: %s", issue.Title),
+			Message: fmt.Sprintf(`%s marked "%s" as done and it's waiting for your approval`,
+				user.Name,
+				issue.Title),
+			Metadata: datatypes.JSONMap{
+				"action":     "review_request",
+				"issue_id":   issue.ID,
+				"parents":    issue.Parents,
+				"project_id": issue.ProjectID,
+				"sender_id":  user.ID,
+				"link":       "/issues/" + issue.ID,
+			},
+		}
+
+		if err := s.notifRepo.Create(&notification); err != nil {
+			logger.Errorf("failed to create review notification: %s to %s", err, ID)
+			continue
+		}
 
-	// a) Issue assignee (if exists and not the commenter)
-	if issue.AssigneeID != nil && *issue.AssigneeID != user.ID {
-		recipients = append(recipients, *issue.AssigneeID)
+		s.emit(ID, "notification:push", notification)
 	}
 
-	// b) Issue reporter (if not the commenter)
-	if issue.ReporterID != nil && *issue.ReporterID != user.ID {
-		recipients = append(recipients, *issue.ReporterID)
+	return nil
+}
+
+// PushReviewResult notify the issue watchers about the approver decision
+func (s *NotificationService) PushReviewResult(user *model.User, issue *model.Issue, approved bool, reason string) {
+	title := fmt.Sprintf("✅ Approved: %s", issue.Title)
+	message := fmt.Sprintf(`%s approved "%s"`, user.Name, issue.Title)
+	action := "review_approved"
+
+	if !approved {
+		title = fmt.Sprintf("↩️ Rejected: %s", issue.Title)
+		message = fmt.Sprintf(`%s rejected "%s"`, user.Name, issue.Title)
+		action = "review_rejected"
 	}
 
-	// c) Issue creator (if not the commenter)
-	if issue.CreatorID != nil && *issue.CreatorID != user.ID {
-		recipients = append(recipients, *issue.CreatorID)
+	if reason != "" {
+		message = fmt.Sprintf("%s: %s", message, common.Truncate(reason, 120))
 	}
 
-	// d) Other commenters on the issue
-	ids, err := s.commentRepo.GetUserIDs(issue.ID, user.ID)
+	recipients, err := s.watcherRepo.GetUserIDs(issue.ID, user.ID)
+	if err != nil {
+		logger.Errorf("failed to fetch issue watchers: %s", err)
+		return
+	}
+
+	for _, ID := range recipients {
+		notification := model.Notification{
+			UserID:  ID,
+			Type:    types.NotificationReview,
+			Title:   title,
+			Message: message,
+			Metadata: datatypes.JSONMap{
+				"action":     action,
+				"issue_id":   issue.ID,
+				"parents":    issue.Parents,
+				"project_id": issue.ProjectID,
+				"sender_id":  user.ID,
+				"reason":     reason,
+				"link":       "/issues/" + issue.ID,
+			},
+		}
+
+		if err := s.notifRepo.Create(&notification); err != nil {
+			logger.Errorf("failed to create review notification: %s to %s", err, ID)
+			continue
+		}
+
+		s.emit(ID, "notification:push", notification)
+	}
+}
+
+// PushDueReminder alert the issue watchers about the issue due date, the issue reminder
+// recorded along each notification so a watcher alerted once per issue per threshold
+func (s *NotificationService) PushDueReminder(issue *model.Issue, threshold types.ReminderThreshold) error {
+	if issue.DueDate == nil {
+		return nil
+	}
+
+	recipients, err := s.watcherRepo.GetUserIDs(issue.ID, "")
+	if err != nil {
+		return err
+	}
+
+	title := fmt.Sprintf("⏰ Due soon: %s", issue.Title)
+	message := fmt.Sprintf(`"%s" is due on %s`, issue.Title, issue.DueDate.Format("Jan 2, 2006 15:04"))
+	if threshold == types.ReminderOverdue {
+		title = fmt.Sprintf("🚨 Overdue: %s", issue.Title)
+		message = fmt.Sprintf(`"%s" was due on %s and is still %s`,
+			issue.Title,
+			issue.DueDate.Format("Jan 2, 2006 15:04"),
+			issue.Status.ToString())
+	}
+
+	for _, recipient := range recipients {
+		notification := model.Notification{
+			UserID:  recipient,
+			Type:    types.NotificationReminder,
+			Title:   title,
+			Message: message,
+			Metadata: datatypes.JSONMap{
+				"action":     threshold,
+				"issue_id":   issue.ID,
+				"parents":    issue.Parents,
+				"project_id": issue.ProjectID,
+				"due_date":   issue.DueDate,
+				"link":       "/issues/" + issue.ID,
+			},
+		}
+
+		sent := false
+		err := s.notifRepo.DB().Transaction(func(tx *gorm.DB) error {
+			reminder := model.IssueReminder{
+				IssueID:   issue.ID,
+				UserID:    recipient,
+				Threshold: threshold,
+				DueDate:   *issue.DueDate,
+			}
+
+			created, err := s.reminderRepo.CreateTx(tx, &reminder)
+			if err != nil || !created {
+				return err
+			}
+
+			sent = true
+			return s.notifRepo.CreateTx(tx, &notification)
+		})
+
+		if err != nil {
+			return fmt.Errorf("failed to push %s reminder: %w", threshold, err)
+		}
+
+		if sent {
+			s.emit(recipient, "notification:push", notification)
+		}
+	}
+
+	return nil
+}
+
+// PushComment notify the issue watchers, the commenter subscribed along the comment.
+// the mentioned users receive the mention instead, and the replied comment author a reply
+func (s *NotificationService) PushComment(user model.User, comment model.Comment) error {
+	issue, err := s.issueRepo.GetByID(comment.IssueID)
 	if err != nil {
 		return err
 	}
 
-	recipients = common.SliceUnique(append(recipients, ids...))
+	if err := s.PushMention(&user, issue, &comment, comment.Mentions); err != nil {
+		logger.Errorf("failed to push comment mentions: %s", err)
+	}
+
+	watchers, err := s.watcherRepo.GetUserIDs(issue.ID, user.ID)
+	if err != nil {
+		return err
+	}
+
+	var repliedTo string
+	if comment.ParentID != nil {
+		parent, err := s.commentRepo.GetByID(*comment.ParentID)
+		if err != nil {
+			return err
+		}
+
+		// the replied author told only while still member of the project
+		_, err = s.userProjectRepo.Get(issue.ProjectID, parent.UserID)
+		if parent.UserID != user.ID && err == nil {
+			repliedTo = parent.UserID
+			watchers = append([]string{repliedTo}, watchers...)
+		}
+	}
+
+	recipients := common.Filter(common.SliceUnique(watchers), func(ID string) bool {
+		return !slices.Contains(comment.Mentions, ID)
+	})
+
 	for _, ID := range recipients {
 		notification := model.Notification{
 			UserID: ID,
@@ -167,6 +421,14 @@ func (s *NotificationService) PushComment(user model.User, comment model.Comment
 			},
 		}
 
+		if ID == repliedTo {
+			notification.Title = fmt.Sprintf("↩️ New reply on %s", issue.Title)
+			notification.Message = fmt.Sprintf("%s replied to your comment: %s",
+				user.Name,
+				common.Truncate(comment.Message, 120))
+			notification.Metadata["parent_id"] = comment.ParentID
+		}
+
 		if err := s.notifRepo.Create(&notification); err != nil {
 			logger.Errorf("failed to create comment notification: %s to %s", err, ID)
 			continue
@@ -178,6 +440,57 @@ func (s *NotificationService) PushComment(user model.User, comment model.Comment
 	return nil
 }
 
+// PushMention notify the mentioned users over socket & email, on the comment when given
+// or else on the issue description. the mentions are resolved to the project members beforehand
+func (s *NotificationService) PushMention(user *model.User, issue *model.Issue, comment *model.Comment, userIDs []string) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	receivers, err := s.userRepo.GetByIDs(userIDs)
+	if err != nil {
+		return err
+	}
+
+	excerpt := textOf(issue.Description)
+	metadata := datatypes.JSONMap{
+		"action":     "mention",
+		"issue_id":   issue.ID,
+		"parents":    issue.Parents,
+		"project_id": issue.ProjectID,
+		"sender_id":  user.ID,
+		"link":       "/issues/" + issue.ID,
+	}
+
+	if comment != nil {
+		excerpt = comment.Message
+		metadata["comment_id"] = comment.ID
+	}
+
+	for _, receiver := range receivers {
+		notification := model.Notification{
+			UserID:   receiver.ID,
+			Type:     types.NotificationMention,
+			Title:    fmt.Sprintf("🔔 %s mentioned you on %s", user.Name, issue.Title),
+			Message:  common.Truncate(excerpt, 120),
+			Metadata: metadata,
+		}
+
+		if err := s.notifRepo.Create(&notification); err != nil {
+			logger.Errorf("failed to create mention notification: %s to %s", err, receiver.ID)
+			continue
+		}
+
+		s.emit(receiver.ID, "notification:push", notification)
+
+		if err := s.mail.Mention(user, &receiver, issue, comment); err != nil {
+			logger.Errorf("failed to send mention email: %s to %s", err, receiver.ID)
+		}
+	}
+
+	return nil
+}
+
 func (s *NotificationService) PushProjectInvite(project *model.Project, sender *model.User, receiver *model.User) error {
 	notification := model.Notification{
 		UserID: receiver.ID,
@@ -0,0 +1,318 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+	"webservices/src/model"
+	"webservices/src/pkg/common"
+	"webservices/src/repo"
+	"webservices/src/types"
+
+	"github.com/zishang520/socket.io/v2/socket"
+	"gorm.io/gorm"
+)
+
+// presenceTTL a session without any event for so long is stale, the clients ping well below it
+const presenceTTL = 2 * time.Minute
+
+type PresenceService struct {
+	*baseService
+	presenceRepo    *repo.PresenceRepository
+	userRepo        *repo.UserRepository
+	userProjectRepo *repo.UserProjectRepository
+	issueRepo       *repo.IssueRepository
+}
+
+func NewPresenceService(
+	io *socket.Server,
+	presenceRepo *repo.PresenceRepository,
+	userRepo *repo.UserRepository,
+	userProjectRepo *repo.UserProjectRepository,
+	issueRepo *repo.IssueRepository,
+) *PresenceService {
+	return &PresenceService{
+		baseService:     newBaseService(io),
+		presenceRepo:    presenceRepo,
+		userRepo:        userRepo,
+		userProjectRepo: userProjectRepo,
+		issueRepo:       issueRepo,
+	}
+}
+
+func (s *PresenceService) GetByProject(userID, projectID string) (*model.ProjectPresence, error) {
+	if err := s.userRepo.ValidatePermission(userID, projectID, types.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	return s.snapshot(projectID, time.Now())
+}
+
+// Connect open the socket session, the user online on every project
+func (s *PresenceService) Connect(userID, socketID string) error {
+	session := model.PresenceSession{
+		ID:     socketID,
+		UserID: userID,
+		Status: types.PresenceOnline,
+		SeenAt: time.Now(),
+	}
+
+	if err := s.presenceRepo.Save(&session); err != nil {
+		return err
+	}
+
+	return s.publishUser(userID)
+}
+
+func (s *PresenceService) Disconnect(socketID string) error {
+	session, err := s.presenceRepo.Delete(socketID)
+	if err != nil || session == nil {
+		return err
+	}
+
+	return s.publishUser(session.UserID)
+}
+
+// Ping keep the session alive, reopened when it already expired
+func (s *PresenceService) Ping(userID, socketID string) error {
+	alive, err := s.presenceRepo.Update(socketID, map[string]any{"seen_at": time.Now()})
+	if err != nil {
+		return err
+	}
+
+	if !alive {
+		return s.Connect(userID, socketID)
+	}
+
+	return nil
+}
+
+// SetStatus the client going idle or back, as reported by its focus & visibility
+func (s *PresenceService) SetStatus(userID, socketID string, status types.PresenceStatus) error {
+	if status != types.PresenceOnline && status != types.PresenceAway {
+		return fmt.Errorf("invalid presence status: %s", status)
+	}
+
+	session, err := s.session(userID, socketID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.presenceRepo.Update(session.ID, map[string]any{
+		"status":  status,
+		"seen_at": time.Now(),
+	}); err != nil {
+		return err
+	}
+
+	if session.Status == status {
+		return nil
+	}
+
+	return s.publishUser(userID)
+}
+
+// Focus the issue open on the socket, `issueID` nil once closed. `editing` the description
+func (s *PresenceService) Focus(userID, socketID string, issueID *string, editing bool) error {
+	session, err := s.session(userID, socketID)
+	if err != nil {
+		return err
+	}
+
+	var projectID *string
+	if issueID != nil && *issueID != "" {
+		issue, err := s.issueRepo.GetByID(*issueID)
+		if err != nil {
+			return err
+		}
+
+		if err := s.userRepo.ValidatePermission(userID, issue.ProjectID, types.RoleViewer); err != nil {
+			return err
+		}
+
+		projectID = &issue.ProjectID
+	} else {
+		issueID = nil
+		editing = false
+	}
+
+	if _, err := s.presenceRepo.Update(session.ID, map[string]any{
+		"project_id": projectID,
+		"issue_id":   issueID,
+		"editing":    editing,
+		"status":     types.PresenceOnline,
+		"seen_at":    time.Now(),
+	}); err != nil {
+		return err
+	}
+
+	if session.Status != types.PresenceOnline {
+		return s.publishUser(userID)
+	}
+
+	// only the projects of the previous & the new issue affected
+	projects := make([]string, 0, 2)
+	for _, ID := range []*string{session.ProjectID, projectID} {
+		if ID != nil && !slices.Contains(projects, *ID) {
+			projects = append(projects, *ID)
+		}
+	}
+
+	for _, ID := range projects {
+		if err := s.publishProject(ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Expire remove the sessions not seen within the `presenceTTL`, the sockets lost without a disconnect
+func (s *PresenceService) Expire(now time.Time) (int, error) {
+	sessions, err := s.presenceRepo.DeleteStale(now.Add(-presenceTTL))
+	if err != nil {
+		return 0, err
+	}
+
+	userIDs := common.SliceUnique(common.Map(sessions, func(v model.PresenceSession) string { return v.UserID }))
+	for _, userID := range userIDs {
+		if err := s.publishUser(userID); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(sessions), nil
+}
+
+// session the socket session, reopened when it already expired
+func (s *PresenceService) session(userID, socketID string) (*model.PresenceSession, error) {
+	session, err := s.presenceRepo.GetByID(socketID)
+	if err == nil {
+		if session.UserID != userID {
+			return nil, fmt.Errorf("permission denied: session of another user")
+		}
+		return session, nil
+	}
+
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	session = &model.PresenceSession{
+		ID:     socketID,
+		UserID: userID,
+		Status: types.PresenceOnline,
+		SeenAt: time.Now(),
+	}
+
+	if err := s.presenceRepo.Save(session); err != nil {
+		return nil, err
+	}
+
+	// already expired, the other members see the user offline
+	session.Status = types.PresenceOffline
+	return session, nil
+}
+
+// publishUser push the presence to every project of the user
+func (s *PresenceService) publishUser(userID string) error {
+	projectIDs, err := s.userProjectRepo.GetProjectIDs(userID)
+	if err != nil {
+		return err
+	}
+
+	for _, projectID := range projectIDs {
+		if err := s.publishProject(projectID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *PresenceService) publishProject(projectID string) error {
+	presence, err := s.snapshot(projectID, time.Now())
+	if err != nil {
+		return err
+	}
+
+	s.emit(ProjectRoom(projectID), "presence:update", presence)
+	return nil
+}
+
+// snapshot the status of every member & the issues open in the project
+func (s *PresenceService) snapshot(projectID string, now time.Time) (*model.ProjectPresence, error) {
+	userIDs, err := s.userProjectRepo.GetUserIDs(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := s.userRepo.GetByIDs(userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := s.presenceRepo.GetByUserIDs(userIDs, now.Add(-presenceTTL))
+	if err != nil {
+		return nil, err
+	}
+
+	presence := &model.ProjectPresence{
+		ProjectID: projectID,
+		Members:   make([]model.MemberPresence, 0, len(users)),
+		Issues:    make([]model.IssuePresence, 0),
+	}
+
+	for _, user := range users {
+		member := model.MemberPresence{
+			UserID: user.ID,
+			Status: types.PresenceOffline,
+			SeenAt: common.Ptr(user.UpdatedAt),
+		}
+
+		for _, session := range sessions {
+			if session.UserID != user.ID {
+				continue
+			}
+
+			// online on any session wins over away
+			if member.Status != types.PresenceOnline {
+				member.Status = session.Status
+			}
+
+			if session.SeenAt.After(*member.SeenAt) {
+				member.SeenAt = common.Ptr(session.SeenAt)
+			}
+		}
+
+		presence.Members = append(presence.Members, member)
+	}
+
+	for _, session := range sessions {
+		if session.IssueID == nil || session.ProjectID == nil || *session.ProjectID != projectID {
+			continue
+		}
+
+		i := slices.IndexFunc(presence.Issues, func(v model.IssuePresence) bool { return v.IssueID == *session.IssueID })
+		if i < 0 {
+			presence.Issues = append(presence.Issues, model.IssuePresence{
+				IssueID: *session.IssueID,
+				Viewers: make([]string, 0),
+				Editors: make([]string, 0),
+			})
+			i = len(presence.Issues) - 1
+		}
+
+		issue := &presence.Issues[i]
+		if !slices.Contains(issue.Viewers, session.UserID) {
+			issue.Viewers = append(issue.Viewers, session.UserID)
+		}
+
+		if session.Editing && !slices.Contains(issue.Editors, session.UserID) {
+			issue.Editors = append(issue.Editors, session.UserID)
+		}
+	}
+
+	return presence, nil
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"fmt"
+	"strings"
 	"webservices/src/model"
 	c "webservices/src/pkg/common"
 	"webservices/src/pkg/logger"
@@ -21,6 +22,8 @@ type ProjectService struct {
 	settingRepo     *repo.ProjectSettingRepository
 	activityRepo    *repo.ActivityRepository
 	userProjectRepo *repo.UserProjectRepository
+	workflowRepo    *repo.WorkflowRepository
+	issueRepo       *repo.IssueRepository
 }
 
 func NewProjectService(
@@ -30,6 +33,8 @@ func NewProjectService(
 	settingRepo *repo.ProjectSettingRepository,
 	activityRepo *repo.ActivityRepository,
 	userProjectRepo *repo.UserProjectRepository,
+	workflowRepo *repo.WorkflowRepository,
+	issueRepo *repo.IssueRepository,
 ) *ProjectService {
 	return &ProjectService{
 		baseService:     newBaseService(io),
@@ -38,6 +43,8 @@ func NewProjectService(
 		settingRepo:     settingRepo,
 		activityRepo:    activityRepo,
 		userProjectRepo: userProjectRepo,
+		workflowRepo:    workflowRepo,
+		issueRepo:       issueRepo,
 	}
 }
 
@@ -49,6 +56,11 @@ func (s *ProjectService) GetIncludeUsers(userID, projectID string) (*model.Proje
 	return s.projectRepo.GetIncludeUsers(projectID, userID)
 }
 
+// ValidateAccess the user a member of the project
+func (s *ProjectService) ValidateAccess(userID, projectID string) error {
+	return s.userRepo.ValidatePermission(userID, projectID, types.RoleViewer)
+}
+
 func (s *ProjectService) Create(userID, name string, image, color, desc *string) (*model.Project, error) {
 	project := model.Project{
 		Name:        name,
@@ -172,6 +184,44 @@ func (s *ProjectService) UpdateSetting(userID, projectID string, values map[stri
 		return nil, err
 	}
 
+	// the default status must be one of the project workflow
+	if value, ok := values["defaultIssueStatus"]; ok {
+		workflow, err := s.workflowRepo.GetByProjectID(projectID)
+		if err != nil {
+			return nil, err
+		}
+
+		status, _ := value.(string)
+		if workflow.Status(types.IssueStatus(status)) == nil {
+			return nil, fmt.Errorf("invalid default status %q: not on the project workflow", status)
+		}
+	}
+
+	// the approval park the completed issues on `in_review`, the default workflow bring it along the setting
+	// while a custom one must have it. turned off, the default one drop it once no issue is left in review
+	if enabled, ok := values["enableApprovalWorkflow"].(bool); ok {
+		workflow, err := s.workflowRepo.GetByProjectID(projectID)
+		if err != nil {
+			return nil, err
+		}
+
+		if enabled && workflow.Custom && workflow.Status(types.IssueStatusInReview) == nil {
+			return nil, fmt.Errorf("invalid approval workflow: the project workflow has no %q status",
+				types.IssueStatusInReview)
+		}
+
+		if !enabled && !workflow.Custom {
+			counts, err := s.workflowRepo.CountIssuesByStatus(projectID, []types.IssueStatus{types.IssueStatusInReview})
+			if err != nil {
+				return nil, err
+			}
+
+			if count := counts[types.IssueStatusInReview]; count > 0 {
+				return nil, fmt.Errorf("invalid approval workflow: %d issues are still waiting for approval", count)
+			}
+		}
+	}
+
 	return s.settingRepo.Updates(projectID, c.Apply(schemas.SettingMap, values))
 }
 
@@ -256,9 +306,52 @@ func (s *ProjectService) RemoveFromTeam(projectID, writerID, teamID string) (*mo
 		return nil, err
 	}
 
+	// the removed member stop receiving the board changes
+	s.leaveProject(teamID, projectID)
+
 	return team, nil
 }
 
+// leaveProject the sockets of the user leave the project room & the rooms of the project issues it joined.
+// every role keep the view access, only the removal revoke it
+func (s *ProjectService) leaveProject(userID, projectID string) {
+	sockets := s.io.In(socket.Room(userID))
+	sockets.SocketsLeave(socket.Room(ProjectRoom(projectID)))
+
+	sockets.FetchSockets()(func(remotes []*socket.RemoteSocket, err error) {
+		if err != nil {
+			logger.Errorf("failed to fetch user sockets: %s", err)
+			return
+		}
+
+		IDs := make([]string, 0)
+		for _, remote := range remotes {
+			for _, room := range remote.Rooms().Keys() {
+				if ID, ok := strings.CutPrefix(string(room), IssueRoom("")); ok {
+					IDs = append(IDs, ID)
+				}
+			}
+		}
+
+		issues, err := s.issueRepo.GetByIDs(c.SliceUnique(IDs))
+		if err != nil {
+			logger.Errorf("failed to fetch joined issues: %s", err)
+			return
+		}
+
+		rooms := make([]socket.Room, 0, len(issues))
+		for _, issue := range issues {
+			if issue.ProjectID == projectID {
+				rooms = append(rooms, socket.Room(IssueRoom(issue.ID)))
+			}
+		}
+
+		if len(rooms) > 0 {
+			sockets.SocketsLeave(rooms...)
+		}
+	})
+}
+
 func (s *ProjectService) Delete(userID, projectID string) (*model.Project, error) {
 	project, err := s.projectRepo.GetByID(projectID)
 	if err != nil {
@@ -305,6 +398,8 @@ func (s *ProjectService) Delete(userID, projectID string) (*model.Project, error
 		return nil, err
 	}
 
+	s.io.In(socket.Room(ProjectRoom(project.ID))).SocketsLeave(socket.Room(ProjectRoom(project.ID)))
+
 	return replacment, nil
 }
 
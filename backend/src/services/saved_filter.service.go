@@ -0,0 +1,195 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"webservices/src/model"
+	"webservices/src/pkg/common"
+	"webservices/src/pkg/jql"
+	"webservices/src/repo"
+	"webservices/src/types"
+	"webservices/src/types/schemas"
+
+	"gorm.io/gorm"
+)
+
+type SavedFilterService struct {
+	filterRepo   *repo.SavedFilterRepository
+	userRepo     *repo.UserRepository
+	workflowRepo *repo.WorkflowRepository
+	fieldRepo    *repo.CustomFieldRepository
+}
+
+func NewSavedFilterService(
+	filterRepo *repo.SavedFilterRepository,
+	userRepo *repo.UserRepository,
+	workflowRepo *repo.WorkflowRepository,
+	fieldRepo *repo.CustomFieldRepository,
+) *SavedFilterService {
+	return &SavedFilterService{
+		filterRepo:   filterRepo,
+		userRepo:     userRepo,
+		workflowRepo: workflowRepo,
+		fieldRepo:    fieldRepo,
+	}
+}
+
+func (s *SavedFilterService) GetByProject(userID, projectID string) ([]model.SavedFilter, error) {
+	if err := s.userRepo.ValidatePermission(userID,
+		projectID, types.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	return s.filterRepo.GetVisible(projectID, userID)
+}
+
+// GetFilter fetch the filter visible to the user, the private filter of others is treated as not found
+func (s *SavedFilterService) GetFilter(userID, projectID, ID string) (*model.SavedFilter, error) {
+	if err := s.userRepo.ValidatePermission(userID,
+		projectID, types.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	filter, err := s.filterRepo.GetByID(ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if filter.ProjectID != projectID ||
+		(filter.Visibility == types.FilterPrivate && filter.OwnerID != userID) {
+		return nil, fmt.Errorf("failed to fetch filter: record not found")
+	}
+
+	return filter, nil
+}
+
+func (s *SavedFilterService) Create(userID, projectID string, value schemas.CreateSavedFilter) (*model.SavedFilter, error) {
+	if err := s.userRepo.ValidatePermission(userID,
+		projectID, types.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	filter := model.SavedFilter{
+		ProjectID: projectID,
+		OwnerID:   userID,
+	}
+
+	if err := s.fill(userID, &filter, value); err != nil {
+		return nil, err
+	}
+
+	if err := s.filterRepo.DB().Transaction(func(tx *gorm.DB) error {
+		return s.filterRepo.CreateTx(tx, &filter)
+	}); err != nil {
+		return nil, err
+	}
+
+	return &filter, nil
+}
+
+func (s *SavedFilterService) Update(userID, projectID, ID string, value schemas.CreateSavedFilter) (*model.SavedFilter, error) {
+	filter, err := s.editable(userID, projectID, ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.fill(userID, filter, value); err != nil {
+		return nil, err
+	}
+
+	if err := s.filterRepo.DB().Transaction(func(tx *gorm.DB) error {
+		return s.filterRepo.UpdateTx(tx, filter)
+	}); err != nil {
+		return nil, err
+	}
+
+	return filter, nil
+}
+
+func (s *SavedFilterService) Delete(userID, projectID, ID string) error {
+	filter, err := s.editable(userID, projectID, ID)
+	if err != nil {
+		return err
+	}
+
+	return s.filterRepo.DB().Transaction(func(tx *gorm.DB) error {
+		return s.filterRepo.DeleteTx(tx, filter.ID)
+	})
+}
+
+// editable only the owner or the project admins able to change the filter
+func (s *SavedFilterService) editable(userID, projectID, ID string) (*model.SavedFilter, error) {
+	filter, err := s.GetFilter(userID, projectID, ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if filter.OwnerID != userID {
+		if err := s.userRepo.ValidatePermission(userID,
+			filter.ProjectID, types.RoleAdmin); err != nil {
+			return nil, err
+		}
+	}
+
+	return filter, nil
+}
+
+// fill validate the query & sort, the *jql.Error returned holding the field name
+func (s *SavedFilterService) fill(userID string, filter *model.SavedFilter, value schemas.CreateSavedFilter) error {
+	filter.Name = strings.TrimSpace(value.Name)
+	filter.Query = strings.TrimSpace(value.Query)
+	filter.Sort = strings.TrimSpace(value.Sort)
+	filter.GroupBy = value.GroupBy
+	filter.Visibility = value.Visibility
+
+	if filter.Name == "" {
+		return fmt.Errorf("filter name required")
+	}
+
+	if filter.GroupBy == "" {
+		filter.GroupBy = types.GroupByStatus
+	}
+
+	if !common.Include(types.BoardGroupings, filter.GroupBy) {
+		return fmt.Errorf("invalid group by %q", filter.GroupBy)
+	}
+
+	if filter.Visibility == "" {
+		filter.Visibility = types.FilterPrivate
+	}
+
+	if !common.Include(types.FilterVisibilities, filter.Visibility) {
+		return fmt.Errorf("invalid visibility %q", filter.Visibility)
+	}
+
+	var queryErr *jql.Error
+
+	ctx, err := queryContext(s.workflowRepo, s.fieldRepo, userID, filter.ProjectID)
+	if err != nil {
+		return err
+	}
+
+	query, err := jql.Parse(filter.Query)
+	if err == nil {
+		// compiled once so the invalid values are rejected on save instead of on every run
+		_, err = jql.Compile(query, ctx)
+	}
+
+	if errors.As(err, &queryErr) {
+		queryErr.Field = "query"
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if _, err := jql.ParseOrder(filter.Sort); err != nil {
+		if errors.As(err, &queryErr) {
+			queryErr.Field = "sort"
+		}
+		return err
+	}
+
+	return nil
+}
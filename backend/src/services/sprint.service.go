@@ -0,0 +1,386 @@
+package services
+
+import (
+	"fmt"
+	"time"
+	"webservices/src/model"
+	"webservices/src/pkg/common"
+	"webservices/src/repo"
+	"webservices/src/types"
+	"webservices/src/types/schemas"
+
+	"github.com/zishang520/socket.io/v2/socket"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+type SprintService struct {
+	*baseService
+	sprintRepo   *repo.SprintRepository
+	issueRepo    *repo.IssueRepository
+	userRepo     *repo.UserRepository
+	activityRepo *repo.ActivityRepository
+}
+
+func NewSprintService(
+	io *socket.Server,
+	sprintRepo *repo.SprintRepository,
+	issueRepo *repo.IssueRepository,
+	userRepo *repo.UserRepository,
+	activityRepo *repo.ActivityRepository,
+) *SprintService {
+	return &SprintService{
+		baseService:  newBaseService(io),
+		sprintRepo:   sprintRepo,
+		issueRepo:    issueRepo,
+		userRepo:     userRepo,
+		activityRepo: activityRepo,
+	}
+}
+
+func (s *SprintService) GetByProject(projectID string, state *types.SprintState) ([]model.Sprint, error) {
+	return s.sprintRepo.GetByProjectID(projectID, state)
+}
+
+func (s *SprintService) GetSprint(ID string) (*model.Sprint, error) {
+	return s.sprintRepo.GetIncludeIssues(ID)
+}
+
+func (s *SprintService) Create(userID string, value schemas.CreateSprint) (*model.Sprint, error) {
+	if err := s.userRepo.ValidatePermission(userID,
+		*value.ProjectID, types.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	sprint := model.Sprint{
+		ProjectID: *value.ProjectID,
+		Name:      value.Name,
+		Goal:      value.Goal,
+		State:     types.SprintStatePlanned,
+	}
+
+	if value.StartDate != nil {
+		sprint.StartDate = &value.StartDate.Time
+	}
+
+	if value.EndDate != nil {
+		sprint.EndDate = &value.EndDate.Time
+	}
+
+	if err := s.validateDates(&sprint); err != nil {
+		return nil, err
+	}
+
+	if err := s.sprintRepo.DB().Transaction(func(tx *gorm.DB) error {
+		return s.sprintRepo.CreateTx(tx, &sprint)
+	}); err != nil {
+		return nil, err
+	}
+
+	return &sprint, nil
+}
+
+func (s *SprintService) Update(userID string, value schemas.CreateSprint) (*model.Sprint, error) {
+	if value.ID == nil || *value.ID == "" {
+		return nil, fmt.Errorf("failed to update sprint: invalid parameter")
+	}
+
+	sprint, err := s.sprintRepo.GetByID(*value.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.ValidatePermission(userID,
+		sprint.ProjectID, types.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	if sprint.State == types.SprintStateClosed {
+		return nil, fmt.Errorf("failed to update: sprint already closed")
+	}
+
+	sprint.Name = value.Name
+	sprint.Goal = value.Goal
+	sprint.StartDate = nil
+	sprint.EndDate = nil
+
+	if value.StartDate != nil {
+		sprint.StartDate = &value.StartDate.Time
+	}
+
+	if value.EndDate != nil {
+		sprint.EndDate = &value.EndDate.Time
+	}
+
+	if err := s.validateDates(sprint); err != nil {
+		return nil, err
+	}
+
+	if err := s.sprintRepo.DB().Transaction(func(tx *gorm.DB) error {
+		return s.sprintRepo.UpdateTx(tx, sprint)
+	}); err != nil {
+		return nil, err
+	}
+
+	return sprint, nil
+}
+
+func (s *SprintService) Start(userID, ID string) (*model.Sprint, error) {
+	sprint, err := s.sprintRepo.GetByID(ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.ValidatePermission(userID,
+		sprint.ProjectID, types.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	if sprint.State != types.SprintStatePlanned {
+		return nil, fmt.Errorf("failed to start: sprint is %s", sprint.State)
+	}
+
+	active, err := s.sprintRepo.GetActive(sprint.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if active != nil {
+		return nil, fmt.Errorf("failed to start: sprint '%s' is still active", active.Name)
+	}
+
+	sprint.State = types.SprintStateActive
+	if sprint.StartDate == nil {
+		sprint.StartDate = common.Ptr(time.Now())
+	}
+
+	if err := s.validateDates(sprint); err != nil {
+		return nil, err
+	}
+
+	err = s.sprintRepo.DB().Transaction(func(tx *gorm.DB) error {
+		if err := s.sprintRepo.UpdateTx(tx, sprint); err != nil {
+			return err
+		}
+
+		activity := model.RecentActivity{
+			UserID:       userID,
+			ProjectID:    &sprint.ProjectID,
+			ActivityType: types.SprintStart,
+			NewValues: &datatypes.JSONMap{
+				"sprint_id":  sprint.ID,
+				"name":       sprint.Name,
+				"goal":       sprint.Goal,
+				"start_date": sprint.StartDate,
+				"end_date":   sprint.EndDate,
+			},
+		}
+
+		return s.activityRepo.CreateTx(tx, &activity)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return sprint, nil
+}
+
+// Close ending the active sprint, unfinished issues moved into `nextSprintID` or the backlog when nil
+func (s *SprintService) Close(userID, ID string, nextSprintID *string) (*model.Sprint, []model.Issue, error) {
+	sprint, err := s.sprintRepo.GetByID(ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.userRepo.ValidatePermission(userID,
+		sprint.ProjectID, types.RoleAdmin); err != nil {
+		return nil, nil, err
+	}
+
+	if sprint.State != types.SprintStateActive {
+		return nil, nil, fmt.Errorf("failed to close: sprint is not active")
+	}
+
+	if nextSprintID != nil && *nextSprintID == "" {
+		nextSprintID = nil
+	}
+
+	if nextSprintID != nil {
+		if *nextSprintID == sprint.ID {
+			return nil, nil, fmt.Errorf("failed to close: cannot rollover into the same sprint")
+		}
+
+		next, err := s.sprintRepo.GetByID(*nextSprintID)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if next.ProjectID != sprint.ProjectID {
+			return nil, nil, fmt.Errorf("failed to close: cross-project not allowed")
+		}
+
+		if next.State == types.SprintStateClosed {
+			return nil, nil, fmt.Errorf("failed to close: sprint '%s' already closed", next.Name)
+		}
+	}
+
+	unfinished, err := s.sprintRepo.GetUnfinishedIssues(sprint.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ids := make([]string, len(unfinished))
+	for i, issue := range unfinished {
+		ids[i] = issue.ID
+	}
+
+	now := time.Now()
+	sprint.State = types.SprintStateClosed
+	sprint.ClosedAt = &now
+
+	err = s.sprintRepo.DB().Transaction(func(tx *gorm.DB) error {
+		if err := s.sprintRepo.UpdateTx(tx, sprint); err != nil {
+			return err
+		}
+
+		if err := s.sprintRepo.MoveIssuesTx(tx, ids, nextSprintID); err != nil {
+			return err
+		}
+
+		activity := model.RecentActivity{
+			UserID:       userID,
+			ProjectID:    &sprint.ProjectID,
+			ActivityType: types.SprintEnd,
+			NewValues: &datatypes.JSONMap{
+				"sprint_id":      sprint.ID,
+				"name":           sprint.Name,
+				"closed_at":      sprint.ClosedAt,
+				"next_sprint_id": nextSprintID,
+				"unfinished":     ids,
+			},
+		}
+
+		return s.activityRepo.CreateTx(tx, &activity)
+	})
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for i := range unfinished {
+		unfinished[i].SprintID = nextSprintID
+		s.publishIssue(types.SyncIssueUpdate, userID, &unfinished[i])
+	}
+
+	return sprint, unfinished, nil
+}
+
+// MoveIssues assign issues into sprint, `sprintID` nil moving it back to the backlog
+func (s *SprintService) MoveIssues(userID, projectID string, sprintID *string, issueIDs []string) ([]model.Issue, error) {
+	if err := s.userRepo.ValidatePermission(userID,
+		projectID, types.RoleEditor); err != nil {
+		return nil, err
+	}
+
+	if sprintID != nil && *sprintID == "" {
+		sprintID = nil
+	}
+
+	if sprintID != nil {
+		sprint, err := s.sprintRepo.GetByID(*sprintID)
+		if err != nil {
+			return nil, err
+		}
+
+		if sprint.ProjectID != projectID {
+			return nil, fmt.Errorf("failed to move: cross-project not allowed")
+		}
+
+		if sprint.State == types.SprintStateClosed {
+			return nil, fmt.Errorf("failed to move: sprint already closed")
+		}
+	}
+
+	issues := make([]model.Issue, 0, len(issueIDs))
+	for _, ID := range common.SliceUnique(issueIDs) {
+		issue, err := s.issueRepo.GetByID(ID)
+		if err != nil {
+			return nil, err
+		}
+
+		if issue.ProjectID != projectID {
+			return nil, fmt.Errorf("failed to move: cross-project not allowed")
+		}
+
+		issues = append(issues, *issue)
+	}
+
+	err := s.sprintRepo.DB().Transaction(func(tx *gorm.DB) error {
+		ids := make([]string, len(issues))
+		for i, issue := range issues {
+			ids[i] = issue.ID
+		}
+
+		if err := s.sprintRepo.MoveIssuesTx(tx, ids, sprintID); err != nil {
+			return err
+		}
+
+		for i, issue := range issues {
+			activity := model.RecentActivity{
+				UserID:       userID,
+				ProjectID:    &issue.ProjectID,
+				IssueID:      &issue.ID,
+				ActivityType: types.IssueUpdate,
+				OldValues:    &datatypes.JSONMap{"sprint": issue.SprintID},
+				NewValues:    &datatypes.JSONMap{"sprint": sprintID},
+			}
+
+			if err := s.activityRepo.CreateTx(tx, &activity); err != nil {
+				return err
+			}
+
+			issues[i].SprintID = sprintID
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range issues {
+		s.publishIssue(types.SyncIssueUpdate, userID, &issues[i])
+	}
+
+	return issues, nil
+}
+
+func (s *SprintService) Delete(userID, ID string) error {
+	sprint, err := s.sprintRepo.GetByID(ID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.userRepo.ValidatePermission(userID,
+		sprint.ProjectID, types.RoleAdmin); err != nil {
+		return err
+	}
+
+	if sprint.State != types.SprintStatePlanned {
+		return fmt.Errorf("can't delete %s sprint", sprint.State)
+	}
+
+	return s.sprintRepo.DB().Transaction(func(tx *gorm.DB) error {
+		return s.sprintRepo.DeleteTx(tx, sprint.ID)
+	})
+}
+
+func (s *SprintService) validateDates(sprint *model.Sprint) error {
+	if sprint.StartDate != nil && sprint.EndDate != nil &&
+		sprint.EndDate.Before(*sprint.StartDate) {
+		return fmt.Errorf("end date must be after start date")
+	}
+	return nil
+}
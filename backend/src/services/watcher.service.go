@@ -0,0 +1,71 @@
+package services
+
+import (
+	"webservices/src/model"
+	"webservices/src/repo"
+	"webservices/src/types"
+
+	"gorm.io/gorm"
+)
+
+type WatcherService struct {
+	watcherRepo *repo.WatcherRepository
+	issueRepo   *repo.IssueRepository
+	userRepo    *repo.UserRepository
+}
+
+func NewWatcherService(
+	watcherRepo *repo.WatcherRepository,
+	issueRepo *repo.IssueRepository,
+	userRepo *repo.UserRepository,
+) *WatcherService {
+	return &WatcherService{
+		watcherRepo: watcherRepo,
+		issueRepo:   issueRepo,
+		userRepo:    userRepo,
+	}
+}
+
+func (s *WatcherService) GetByIssue(userID, issueID string) ([]model.IssueWatcher, error) {
+	issue, err := s.issueRepo.GetByID(issueID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.ValidatePermission(userID,
+		issue.ProjectID, types.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	return s.watcherRepo.GetByIssueID(issue.ID)
+}
+
+// Watch subscribe the user to the issue notifications, returning the watchers
+func (s *WatcherService) Watch(userID, issueID string) ([]model.IssueWatcher, error) {
+	return s.set(userID, issueID, true)
+}
+
+// Unwatch opt the user out of the issue notifications, kept even the user later assigned or commenting
+func (s *WatcherService) Unwatch(userID, issueID string) ([]model.IssueWatcher, error) {
+	return s.set(userID, issueID, false)
+}
+
+func (s *WatcherService) set(userID, issueID string, watching bool) ([]model.IssueWatcher, error) {
+	issue, err := s.issueRepo.GetByID(issueID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.ValidatePermission(userID,
+		issue.ProjectID, types.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	if err := s.watcherRepo.DB().Transaction(func(tx *gorm.DB) error {
+		return s.watcherRepo.SetTx(tx, issue.ID, userID, watching)
+	}); err != nil {
+		return nil, err
+	}
+
+	return s.watcherRepo.GetByIssueID(issue.ID)
+}
@@ -0,0 +1,187 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"webservices/src/model"
+	"webservices/src/repo"
+	"webservices/src/types"
+	"webservices/src/types/schemas"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+var statusKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+type WorkflowService struct {
+	workflowRepo *repo.WorkflowRepository
+	settingRepo  *repo.ProjectSettingRepository
+	userRepo     *repo.UserRepository
+	activityRepo *repo.ActivityRepository
+}
+
+func NewWorkflowService(
+	workflowRepo *repo.WorkflowRepository,
+	settingRepo *repo.ProjectSettingRepository,
+	userRepo *repo.UserRepository,
+	activityRepo *repo.ActivityRepository,
+) *WorkflowService {
+	return &WorkflowService{
+		workflowRepo: workflowRepo,
+		settingRepo:  settingRepo,
+		userRepo:     userRepo,
+		activityRepo: activityRepo,
+	}
+}
+
+func (s *WorkflowService) GetByProject(userID, projectID string) (*model.Workflow, error) {
+	if err := s.userRepo.ValidatePermission(userID,
+		projectID, types.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	return s.workflowRepo.GetByProjectID(projectID)
+}
+
+// Update replace the project workflow, the statuses still holding issues or being the project default can't be removed,
+// nor the `in_review` one while the approval workflow enabled
+func (s *WorkflowService) Update(userID, projectID string, value schemas.UpdateWorkflow) (*model.Workflow, error) {
+	if err := s.userRepo.ValidatePermission(userID,
+		projectID, types.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	current, err := s.workflowRepo.GetByProjectID(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	workflow, err := s.build(projectID, value)
+	if err != nil {
+		return nil, err
+	}
+
+	removed := make([]types.IssueStatus, 0)
+	for _, status := range current.Statuses {
+		if workflow.Status(status.Key) == nil {
+			removed = append(removed, status.Key)
+		}
+	}
+
+	counts, err := s.workflowRepo.CountIssuesByStatus(projectID, removed)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, key := range removed {
+		if counts[key] > 0 {
+			return nil, fmt.Errorf("invalid workflow: %d issues are still in status %q", counts[key], key)
+		}
+	}
+
+	setting, err := s.settingRepo.GetByProjectID(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if workflow.Status(setting.DefaultIssueStatus) == nil {
+		return nil, fmt.Errorf("invalid workflow: %q is the project default status", setting.DefaultIssueStatus)
+	}
+
+	if setting.EnableApprovalWorkflow && workflow.Status(types.IssueStatusInReview) == nil {
+		return nil, fmt.Errorf("invalid workflow: %q status required by the approval workflow", types.IssueStatusInReview)
+	}
+
+	err = s.workflowRepo.DB().Transaction(func(tx *gorm.DB) error {
+		if err := s.workflowRepo.ReplaceTx(tx, workflow); err != nil {
+			return err
+		}
+
+		activity := model.RecentActivity{
+			UserID:       userID,
+			ProjectID:    &projectID,
+			ActivityType: types.ProjectUpdate,
+			OldValues:    &datatypes.JSONMap{"workflow": current.Keys()},
+			NewValues:    &datatypes.JSONMap{"workflow": workflow.Keys()},
+		}
+
+		return s.activityRepo.CreateTx(tx, &activity)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return workflow, nil
+}
+
+func (s *WorkflowService) build(projectID string, value schemas.UpdateWorkflow) (*model.Workflow, error) {
+	workflow := &model.Workflow{
+		ProjectID:   projectID,
+		Custom:      true,
+		Statuses:    make([]model.WorkflowStatus, 0, len(value.Statuses)),
+		Transitions: make([]model.WorkflowTransition, 0, len(value.Transitions)),
+	}
+
+	categories := make(map[types.StatusCategory]bool)
+	for i, status := range value.Statuses {
+		key := types.IssueStatus(strings.ToLower(strings.TrimSpace(string(status.Key))))
+		if !statusKeyPattern.MatchString(string(key)) {
+			return nil, fmt.Errorf("invalid workflow: status key %q must be lowercase letters, digits or underscores", status.Key)
+		}
+
+		if workflow.Status(key) != nil {
+			return nil, fmt.Errorf("invalid workflow: duplicate status %q", key)
+		}
+
+		workflow.Statuses = append(workflow.Statuses, model.WorkflowStatus{
+			ProjectID: projectID,
+			Key:       key,
+			Name:      strings.TrimSpace(status.Name),
+			Category:  status.Category,
+			Position:  i,
+		})
+		categories[status.Category] = true
+	}
+
+	if !categories[types.CategoryTodo] || !categories[types.CategoryDone] {
+		return nil, fmt.Errorf("invalid workflow: at least one %s and one %s status required",
+			types.CategoryTodo, types.CategoryDone)
+	}
+
+	seen := make(map[string]bool)
+	for _, transition := range value.Transitions {
+		if workflow.Status(transition.To) == nil {
+			return nil, fmt.Errorf("invalid workflow: unknown status %q on transition", transition.To)
+		}
+
+		if transition.From != nil && *transition.From == "" {
+			transition.From = nil
+		}
+
+		from := "*"
+		if transition.From != nil {
+			if workflow.Status(*transition.From) == nil {
+				return nil, fmt.Errorf("invalid workflow: unknown status %q on transition", *transition.From)
+			}
+
+			if *transition.From == transition.To {
+				return nil, fmt.Errorf("invalid workflow: transition from %q into itself", transition.To)
+			}
+			from = string(*transition.From)
+		}
+
+		if key := from + ">" + string(transition.To); !seen[key] {
+			seen[key] = true
+			workflow.Transitions = append(workflow.Transitions, model.WorkflowTransition{
+				ProjectID:  projectID,
+				FromStatus: transition.From,
+				ToStatus:   transition.To,
+			})
+		}
+	}
+
+	return workflow, nil
+}
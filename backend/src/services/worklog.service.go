@@ -0,0 +1,438 @@
+package services
+
+import (
+	"fmt"
+	"time"
+	"webservices/src/model"
+	"webservices/src/pkg/common"
+	"webservices/src/repo"
+	"webservices/src/types"
+	"webservices/src/types/schemas"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+type WorklogService struct {
+	worklogRepo  *repo.WorklogRepository
+	issueRepo    *repo.IssueRepository
+	userRepo     *repo.UserRepository
+	settingRepo  *repo.ProjectSettingRepository
+	activityRepo *repo.ActivityRepository
+}
+
+func NewWorklogService(
+	worklogRepo *repo.WorklogRepository,
+	issueRepo *repo.IssueRepository,
+	userRepo *repo.UserRepository,
+	settingRepo *repo.ProjectSettingRepository,
+	activityRepo *repo.ActivityRepository,
+) *WorklogService {
+	return &WorklogService{
+		worklogRepo:  worklogRepo,
+		issueRepo:    issueRepo,
+		userRepo:     userRepo,
+		settingRepo:  settingRepo,
+		activityRepo: activityRepo,
+	}
+}
+
+func (s *WorklogService) GetByIssue(userID, issueID string) (*schemas.TimeTracking, []model.Worklog, error) {
+	issue, err := s.issueRepo.GetByID(issueID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.userRepo.ValidatePermission(userID,
+		issue.ProjectID, types.RoleViewer); err != nil {
+		return nil, nil, err
+	}
+
+	unit, err := s.unit(issue.ProjectID, false)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	worklogs, err := s.worklogRepo.GetByIssueID(issue.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for i := range worklogs {
+		worklogs[i].Duration = unit.FromMinutes(worklogs[i].Minutes)
+	}
+
+	summary, err := s.summary(unit, issue)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return summary, worklogs, nil
+}
+
+func (s *WorklogService) Create(userID, issueID string, value schemas.CreateWorklog) (*model.Worklog, error) {
+	issue, err := s.issueRepo.GetByID(issueID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.ValidatePermission(userID,
+		issue.ProjectID, types.RoleEditor); err != nil {
+		return nil, err
+	}
+
+	unit, err := s.unit(issue.ProjectID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	worklog := model.Worklog{
+		IssueID: issue.ID,
+		UserID:  userID,
+		Minutes: unit.ToMinutes(value.Duration),
+		Date:    time.Now(),
+		Note:    value.Note,
+	}
+
+	if value.Date != nil {
+		worklog.Date = value.Date.Time
+	}
+
+	if worklog.Minutes <= 0 {
+		return nil, fmt.Errorf("duration is too short")
+	}
+
+	err = s.worklogRepo.DB().Transaction(func(tx *gorm.DB) error {
+		if err := s.worklogRepo.CreateTx(tx, &worklog); err != nil {
+			return err
+		}
+
+		remaining := issue.RemainingEstimate
+		if err := s.adjustRemaining(tx, issue, worklog.Minutes); err != nil {
+			return err
+		}
+
+		activity := model.RecentActivity{
+			UserID:       userID,
+			ProjectID:    &issue.ProjectID,
+			IssueID:      &issue.ID,
+			ActivityType: types.WorklogCreate,
+			OldValues: &datatypes.JSONMap{
+				"remaining_estimate": remaining,
+			},
+			NewValues: &datatypes.JSONMap{
+				"worklog_id":         worklog.ID,
+				"minutes":            worklog.Minutes,
+				"date":               worklog.Date,
+				"note":               worklog.Note,
+				"remaining_estimate": issue.RemainingEstimate,
+			},
+		}
+
+		return s.activityRepo.CreateTx(tx, &activity)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.issueRepo.Heartbeat(issue.ID)
+
+	worklog.Duration = unit.FromMinutes(worklog.Minutes)
+	return &worklog, nil
+}
+
+func (s *WorklogService) Update(ID, userID string, value schemas.CreateWorklog) (*model.Worklog, error) {
+	worklog, err := s.worklogRepo.GetByID(ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.validateOwner(userID, worklog); err != nil {
+		return nil, err
+	}
+
+	unit, err := s.unit(worklog.Issue.ProjectID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	activity := model.RecentActivity{
+		UserID:       userID,
+		ProjectID:    &worklog.Issue.ProjectID,
+		IssueID:      &worklog.IssueID,
+		ActivityType: types.WorklogUpdate,
+		OldValues: &datatypes.JSONMap{
+			"worklog_id": worklog.ID,
+			"minutes":    worklog.Minutes,
+			"date":       worklog.Date,
+			"note":       worklog.Note,
+		},
+	}
+
+	prevMinutes := worklog.Minutes
+	worklog.Minutes = unit.ToMinutes(value.Duration)
+	worklog.Note = value.Note
+	if value.Date != nil {
+		worklog.Date = value.Date.Time
+	}
+
+	if worklog.Minutes <= 0 {
+		return nil, fmt.Errorf("duration is too short")
+	}
+
+	err = s.worklogRepo.DB().Transaction(func(tx *gorm.DB) error {
+		if err := s.worklogRepo.UpdateTx(tx, worklog); err != nil {
+			return err
+		}
+
+		(*activity.OldValues)["remaining_estimate"] = worklog.Issue.RemainingEstimate
+		if err := s.adjustRemaining(tx, &worklog.Issue, worklog.Minutes-prevMinutes); err != nil {
+			return err
+		}
+
+		activity.NewValues = &datatypes.JSONMap{
+			"worklog_id":         worklog.ID,
+			"minutes":            worklog.Minutes,
+			"date":               worklog.Date,
+			"note":               worklog.Note,
+			"remaining_estimate": worklog.Issue.RemainingEstimate,
+		}
+
+		return s.activityRepo.CreateTx(tx, &activity)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.issueRepo.Heartbeat(worklog.IssueID)
+
+	worklog.Duration = unit.FromMinutes(worklog.Minutes)
+	return worklog, nil
+}
+
+func (s *WorklogService) Delete(ID, userID string) (*model.Worklog, error) {
+	worklog, err := s.worklogRepo.GetByID(ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.validateOwner(userID, worklog); err != nil {
+		return nil, err
+	}
+
+	unit, err := s.unit(worklog.Issue.ProjectID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	activity := model.RecentActivity{
+		UserID:       userID,
+		ProjectID:    &worklog.Issue.ProjectID,
+		IssueID:      &worklog.IssueID,
+		ActivityType: types.WorklogDelete,
+		OldValues: &datatypes.JSONMap{
+			"worklog_id": worklog.ID,
+			"user_id":    worklog.UserID,
+			"minutes":    worklog.Minutes,
+			"date":       worklog.Date,
+			"note":       worklog.Note,
+		},
+	}
+
+	err = s.worklogRepo.DB().Transaction(func(tx *gorm.DB) error {
+		if err := s.worklogRepo.DeleteTx(tx, worklog.ID); err != nil {
+			return err
+		}
+
+		(*activity.OldValues)["remaining_estimate"] = worklog.Issue.RemainingEstimate
+		if err := s.adjustRemaining(tx, &worklog.Issue, -worklog.Minutes); err != nil {
+			return err
+		}
+
+		activity.NewValues = &datatypes.JSONMap{
+			"remaining_estimate": worklog.Issue.RemainingEstimate,
+		}
+
+		return s.activityRepo.CreateTx(tx, &activity)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.issueRepo.Heartbeat(worklog.IssueID)
+
+	worklog.Duration = unit.FromMinutes(worklog.Minutes)
+	return worklog, nil
+}
+
+func (s *WorklogService) UpdateEstimate(userID, issueID string, value schemas.UpdateEstimate) (*schemas.TimeTracking, error) {
+	issue, err := s.issueRepo.GetByID(issueID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.ValidatePermission(userID,
+		issue.ProjectID, types.RoleEditor); err != nil {
+		return nil, err
+	}
+
+	unit, err := s.unit(issue.ProjectID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	activity := model.RecentActivity{
+		UserID:       userID,
+		ProjectID:    &issue.ProjectID,
+		IssueID:      &issue.ID,
+		ActivityType: types.IssueUpdate,
+		OldValues: &datatypes.JSONMap{
+			"original_estimate":  issue.OriginalEstimate,
+			"remaining_estimate": issue.RemainingEstimate,
+		},
+	}
+
+	issue.OriginalEstimate = nil
+	if value.OriginalEstimate != nil {
+		issue.OriginalEstimate = common.Ptr(unit.ToMinutes(*value.OriginalEstimate))
+	}
+
+	// remaining follows the original estimate when it's not provided
+	issue.RemainingEstimate = issue.OriginalEstimate
+	if value.RemainingEstimate != nil {
+		issue.RemainingEstimate = common.Ptr(unit.ToMinutes(*value.RemainingEstimate))
+	}
+
+	err = s.issueRepo.DB().Transaction(func(tx *gorm.DB) error {
+		if err := s.issueRepo.UpdateEstimateTx(tx, issue); err != nil {
+			return err
+		}
+
+		activity.NewValues = &datatypes.JSONMap{
+			"original_estimate":  issue.OriginalEstimate,
+			"remaining_estimate": issue.RemainingEstimate,
+		}
+
+		return s.activityRepo.CreateTx(tx, &activity)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return s.summary(unit, issue)
+}
+
+// summary issue estimates and time spent, the subtasks totals rollup into the parent
+func (s *WorklogService) summary(unit types.TimeUnit, issue *model.Issue) (*schemas.TimeTracking, error) {
+	spent, err := s.worklogRepo.SumByIssueIDs([]string{issue.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	childs, err := s.issueRepo.GetByProjectID(false, issue.ProjectID, &issue.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	childIDs := make([]string, len(childs))
+	var original, remaining *int
+	for i, child := range childs {
+		childIDs[i] = child.ID
+		original = sumMinutes(original, child.OriginalEstimate)
+		remaining = sumMinutes(remaining, child.RemainingEstimate)
+	}
+
+	childSpent, err := s.worklogRepo.SumByIssueIDs(childIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	convert := func(minutes *int) *float64 {
+		if minutes == nil {
+			return nil
+		}
+		return common.Ptr(unit.FromMinutes(*minutes))
+	}
+
+	return &schemas.TimeTracking{
+		Unit: unit,
+		Issue: schemas.TimeEstimate{
+			OriginalEstimate:  convert(issue.OriginalEstimate),
+			RemainingEstimate: convert(issue.RemainingEstimate),
+			TimeSpent:         unit.FromMinutes(spent),
+		},
+		Subtasks: schemas.TimeEstimate{
+			OriginalEstimate:  convert(original),
+			RemainingEstimate: convert(remaining),
+			TimeSpent:         unit.FromMinutes(childSpent),
+		},
+		Total: schemas.TimeEstimate{
+			OriginalEstimate:  convert(sumMinutes(issue.OriginalEstimate, original)),
+			RemainingEstimate: convert(sumMinutes(issue.RemainingEstimate, remaining)),
+			TimeSpent:         unit.FromMinutes(spent + childSpent),
+		},
+	}, nil
+}
+
+// logged time reduce the remaining estimate, never below zero
+func (s *WorklogService) adjustRemaining(tx *gorm.DB, issue *model.Issue, minutes int) error {
+	if issue.RemainingEstimate == nil || minutes == 0 {
+		return nil
+	}
+
+	remaining := max(0, *issue.RemainingEstimate-minutes)
+	if issue.OriginalEstimate != nil {
+		remaining = min(remaining, *issue.OriginalEstimate)
+	}
+
+	issue.RemainingEstimate = &remaining
+	return s.issueRepo.UpdateEstimateTx(tx, issue)
+}
+
+func (s *WorklogService) validateOwner(userID string, worklog *model.Worklog) error {
+	if worklog.UserID == userID {
+		return s.userRepo.ValidatePermission(userID,
+			worklog.Issue.ProjectID, types.RoleEditor)
+	}
+
+	if err := s.userRepo.ValidatePermission(userID,
+		worklog.Issue.ProjectID, types.RoleAdmin); err != nil {
+		return fmt.Errorf("you can only change your own worklogs")
+	}
+
+	return nil
+}
+
+// unit project time_tracking_unit, `enabled` reject when time tracking is turned off
+func (s *WorklogService) unit(projectID string, enabled bool) (types.TimeUnit, error) {
+	setting, err := s.settingRepo.GetByProjectID(projectID)
+	if err != nil {
+		return "", err
+	}
+
+	if enabled && !setting.EnableTimeTracking {
+		return "", fmt.Errorf("time tracking is disabled on this project")
+	}
+
+	if setting.TimeTrackingUnit == nil || *setting.TimeTrackingUnit == "" {
+		return types.TimeUnitHours, nil
+	}
+
+	return *setting.TimeTrackingUnit, nil
+}
+
+func sumMinutes(a, b *int) *int {
+	if a == nil {
+		return b
+	}
+
+	if b == nil {
+		return a
+	}
+
+	return common.Ptr(*a + *b)
+}
@@ -1,6 +1,9 @@
 package types
 
-import "strings"
+import (
+	"math"
+	"strings"
+)
 
 type ProjectStatus string
 
@@ -10,12 +13,15 @@ const (
 	ProjectStatusDone     ProjectStatus = "done"
 )
 
+// IssueStatus the key of a project workflow status, the constants are the default workflow.
+// `draft` & `in_review` keep their special meaning on the custom workflows defining them
 type IssueStatus string
 
 const (
 	IssueStatusDraft      IssueStatus = "draft"
 	IssueStatusTodo       IssueStatus = "todo"
 	IssueStatusOnProgress IssueStatus = "on_progress"
+	IssueStatusInReview   IssueStatus = "in_review" // waiting approver sign-off
 	IssueStatusDone       IssueStatus = "done"
 )
 
@@ -23,6 +29,31 @@ func (s IssueStatus) ToString() string {
 	return strings.ReplaceAll(string(s), "_", " ")
 }
 
+// DefaultCategory the category of the status on the default workflow
+func (s IssueStatus) DefaultCategory() StatusCategory {
+	switch s {
+	case IssueStatusOnProgress, IssueStatusInReview:
+		return CategoryInProgress
+	case IssueStatusDone:
+		return CategoryDone
+	default:
+		return CategoryTodo
+	}
+}
+
+// StatusCategory the meaning of a workflow status, the date autofill & reports rely on it instead of the status
+type StatusCategory string
+
+const (
+	CategoryTodo       StatusCategory = "todo"
+	CategoryInProgress StatusCategory = "in_progress"
+	CategoryDone       StatusCategory = "done"
+)
+
+func (c StatusCategory) String() string {
+	return string(c)
+}
+
 type IssuePriority string
 
 const (
@@ -49,6 +80,31 @@ const (
 	TimeUnitDays    TimeUnit = "days"
 )
 
+// working day used to convert `days`, same as the common tracker convention
+const WorkingHoursPerDay = 8
+
+func (u TimeUnit) minutes() int {
+	switch u {
+	case TimeUnitMinutes:
+		return 1
+	case TimeUnitDays:
+		return WorkingHoursPerDay * 60
+	default:
+		return 60
+	}
+}
+
+// ToMinutes convert value on this unit into minutes (stored value)
+func (u TimeUnit) ToMinutes(value float64) int {
+	return int(math.Round(value * float64(u.minutes())))
+}
+
+// FromMinutes convert stored minutes into this unit
+func (u TimeUnit) FromMinutes(minutes int) float64 {
+	value := float64(minutes) / float64(u.minutes())
+	return math.Round(value*100) / 100
+}
+
 type ProjectSort string
 
 const (
@@ -66,6 +122,14 @@ const (
 	DirectionBottom MoveDirection = "bottom"
 )
 
+type SprintState string
+
+const (
+	SprintStatePlanned SprintState = "planned"
+	SprintStateActive  SprintState = "active"
+	SprintStateClosed  SprintState = "closed"
+)
+
 type IssueItemType string
 
 const (
@@ -74,6 +138,42 @@ const (
 	LinkWork   IssueItemType = "link_work"
 )
 
+type IssueLinkType string
+
+const (
+	LinkBlocks       IssueLinkType = "blocks"
+	LinkBlockedBy    IssueLinkType = "is_blocked_by"
+	LinkDuplicates   IssueLinkType = "duplicates"
+	LinkDuplicatedBy IssueLinkType = "is_duplicated_by"
+	LinkRelatesTo    IssueLinkType = "relates_to"
+	LinkClones       IssueLinkType = "clones"
+	LinkClonedBy     IssueLinkType = "is_cloned_by"
+)
+
+func (l IssueLinkType) String() string {
+	return string(l)
+}
+
+// Inverse the relation seen from the linked issue
+func (l IssueLinkType) Inverse() IssueLinkType {
+	switch l {
+	case LinkBlocks:
+		return LinkBlockedBy
+	case LinkBlockedBy:
+		return LinkBlocks
+	case LinkDuplicates:
+		return LinkDuplicatedBy
+	case LinkDuplicatedBy:
+		return LinkDuplicates
+	case LinkClones:
+		return LinkClonedBy
+	case LinkClonedBy:
+		return LinkClones
+	default:
+		return l
+	}
+}
+
 type ActivityType string
 
 const (
@@ -87,15 +187,21 @@ const (
 	CommentCreate       ActivityType = "comment_create"
 	CommentUpdate       ActivityType = "comment_update"
 	CommentDelete       ActivityType = "comment_delete"
+	CommentRestore      ActivityType = "comment_restore"
 	SprintStart         ActivityType = "sprint_start"
 	SprintEnd           ActivityType = "sprint_end"
 	StatusChange        ActivityType = "status_change"
+	IssueApprove        ActivityType = "issue_approve"
+	IssueReject         ActivityType = "issue_reject"
 	IssueChildrenCreate ActivityType = "issue_children_create"
 	IssueChildrenUpdate ActivityType = "issue_children_update"
 	IssueChildrenDelete ActivityType = "issue_children_delete"
 	IssueItemCreate     ActivityType = "issue_item_create"
 	IssueItemUpdate     ActivityType = "issue_item_update"
 	IssueItemDelete     ActivityType = "issue_item_delete"
+	WorklogCreate       ActivityType = "worklog_create"
+	WorklogUpdate       ActivityType = "worklog_update"
+	WorklogDelete       ActivityType = "worklog_delete"
 	UserProjectUpdate   ActivityType = "user_project_update"
 	UserProjectDelete   ActivityType = "user_project_delete"
 )
@@ -111,10 +217,13 @@ func (a ActivityType) StringQ() string {
 type NotificationType string
 
 const (
-	NotificationSystem  NotificationType = "system"
-	NotificationMessage NotificationType = "message"
-	NotificationTask    NotificationType = "task"
-	NotificationComment NotificationType = "comment"
+	NotificationSystem   NotificationType = "system"
+	NotificationMessage  NotificationType = "message"
+	NotificationTask     NotificationType = "task"
+	NotificationComment  NotificationType = "comment"
+	NotificationReview   NotificationType = "review"
+	NotificationReminder NotificationType = "reminder"
+	NotificationMention  NotificationType = "mention"
 )
 
 func (n NotificationType) String() string {
@@ -125,6 +234,13 @@ func (n NotificationType) StringQ() string {
 	return string("'" + n + "'")
 }
 
+type ReminderThreshold string
+
+const (
+	ReminderDueSoon ReminderThreshold = "due_soon"
+	ReminderOverdue ReminderThreshold = "overdue"
+)
+
 type UserProjectRole string
 
 const (
@@ -171,3 +287,103 @@ const (
 	ReportTypeFeature  ReportType = "feature"
 	ReportTypeOther    ReportType = "other"
 )
+
+type FilterVisibility string
+
+const (
+	FilterPrivate FilterVisibility = "private" // only the owner
+	FilterProject FilterVisibility = "project" // shared with the project members
+)
+
+func (v FilterVisibility) String() string {
+	return string(v)
+}
+
+// BoardGrouping the board columns of a saved filter
+type BoardGrouping string
+
+const (
+	GroupByNone     BoardGrouping = "none"
+	GroupByStatus   BoardGrouping = "status"
+	GroupByAssignee BoardGrouping = "assignee"
+	GroupByPriority BoardGrouping = "priority"
+	GroupByType     BoardGrouping = "type"
+	GroupByLabel    BoardGrouping = "label"
+)
+
+func (v BoardGrouping) String() string {
+	return string(v)
+}
+
+// CustomFieldType the value type of a project custom field
+type CustomFieldType string
+
+const (
+	FieldText        CustomFieldType = "text"
+	FieldNumber      CustomFieldType = "number"
+	FieldSelect      CustomFieldType = "select"
+	FieldMultiSelect CustomFieldType = "multi_select"
+	FieldDate        CustomFieldType = "date"
+	FieldUser        CustomFieldType = "user"
+)
+
+func (v CustomFieldType) String() string {
+	return string(v)
+}
+
+// HasOptions the select types picking from `CustomField.Options`
+func (v CustomFieldType) HasOptions() bool {
+	return v == FieldSelect || v == FieldMultiSelect
+}
+
+// BulkAction the change applied by `POST /issue/bulk` to every selected issue
+type BulkAction string
+
+const (
+	BulkStatus   BulkAction = "status"
+	BulkPriority BulkAction = "priority"
+	BulkAssignee BulkAction = "assignee"
+	BulkLabel    BulkAction = "label"   // add the labels
+	BulkUnlabel  BulkAction = "unlabel" // remove the labels
+	BulkParent   BulkAction = "parent"
+	BulkSprint   BulkAction = "sprint"
+	BulkDelete   BulkAction = "delete"
+)
+
+func (v BulkAction) String() string {
+	return string(v)
+}
+
+// SyncEvent the socket event of a realtime change, pushed to the project & issue rooms
+type SyncEvent string
+
+const (
+	SyncIssueCreate   SyncEvent = "issue:created"
+	SyncIssueUpdate   SyncEvent = "issue:updated"
+	SyncIssueMove     SyncEvent = "issue:moved"   // the parent changed
+	SyncIssueOrder    SyncEvent = "issue:ordered" // the sequence changed, one delta per affected issue
+	SyncIssueDelete   SyncEvent = "issue:deleted"
+	SyncCommentCreate SyncEvent = "comment:created"
+	SyncCommentUpdate SyncEvent = "comment:updated"
+	SyncCommentDelete SyncEvent = "comment:deleted"
+	SyncItemCreate    SyncEvent = "item:created"
+	SyncItemUpdate    SyncEvent = "item:updated"
+	SyncItemDelete    SyncEvent = "item:deleted"
+)
+
+func (v SyncEvent) String() string {
+	return string(v)
+}
+
+// PresenceStatus the member availability, offline once no session left
+type PresenceStatus string
+
+const (
+	PresenceOnline  PresenceStatus = "online"
+	PresenceAway    PresenceStatus = "away" // the client idle or hidden
+	PresenceOffline PresenceStatus = "offline"
+)
+
+func (v PresenceStatus) String() string {
+	return string(v)
+}
@@ -8,8 +8,26 @@ import (
 
 type KeyValue[T any] map[string]any
 
+// Partial the optional JSON field telling the omitted key from the explicit null, `Set` once the key present
 type Partial[T any] struct {
 	Value *T
+	Set   bool
+}
+
+func (p *Partial[T]) UnmarshalJSON(b []byte) error {
+	p.Set = true
+	if string(b) == "null" {
+		p.Value = nil
+		return nil
+	}
+
+	var value T
+	if err := json.Unmarshal(b, &value); err != nil {
+		return err
+	}
+
+	p.Value = &value
+	return nil
 }
 
 type JSONPatch struct {
@@ -1,5 +1,27 @@
 package types
 
+var IssueStatuses = []IssueStatus{
+	IssueStatusDraft,
+	IssueStatusTodo,
+	IssueStatusOnProgress,
+	IssueStatusInReview,
+	IssueStatusDone,
+}
+
+var StatusCategories = []StatusCategory{
+	CategoryTodo,
+	CategoryInProgress,
+	CategoryDone,
+}
+
+var IssuePriorities = []IssuePriority{
+	IssuePriorityLowest,
+	IssuePriorityLow,
+	IssuePriorityMedium,
+	IssuePriorityHigh,
+	IssuePriorityHighest,
+}
+
 var IssueTypes = []IssueType{
 	IssueTypeTask,
 	IssueTypeSubtask,
@@ -8,6 +30,16 @@ var IssueTypes = []IssueType{
 	IssueTypeEpic,
 }
 
+var IssueLinkTypes = []IssueLinkType{
+	LinkBlocks,
+	LinkBlockedBy,
+	LinkDuplicates,
+	LinkDuplicatedBy,
+	LinkRelatesTo,
+	LinkClones,
+	LinkClonedBy,
+}
+
 var ActivityTypes = []ActivityType{
 	ProjectCreate,
 	ProjectUpdate,
@@ -19,15 +51,21 @@ var ActivityTypes = []ActivityType{
 	CommentCreate,
 	CommentUpdate,
 	CommentDelete,
+	CommentRestore,
 	SprintStart,
 	SprintEnd,
 	StatusChange,
+	IssueApprove,
+	IssueReject,
 	IssueChildrenCreate,
 	IssueChildrenUpdate,
 	IssueChildrenDelete,
 	IssueItemCreate,
 	IssueItemUpdate,
 	IssueItemDelete,
+	WorklogCreate,
+	WorklogUpdate,
+	WorklogDelete,
 	UserProjectUpdate,
 	UserProjectDelete,
 }
@@ -37,4 +75,36 @@ var NotificationTypes = []NotificationType{
 	NotificationMessage,
 	NotificationTask,
 	NotificationComment,
+	NotificationReview,
+	NotificationReminder,
+	NotificationMention,
+}
+
+var FilterVisibilities = []FilterVisibility{
+	FilterPrivate,
+	FilterProject,
+}
+
+var BoardGroupings = []BoardGrouping{
+	GroupByNone,
+	GroupByStatus,
+	GroupByAssignee,
+	GroupByPriority,
+	GroupByType,
+	GroupByLabel,
+}
+
+var CustomFieldTypes = []CustomFieldType{
+	FieldText,
+	FieldNumber,
+	FieldSelect,
+	FieldMultiSelect,
+	FieldDate,
+	FieldUser,
+}
+
+var PresenceStatuses = []PresenceStatus{
+	PresenceOnline,
+	PresenceAway,
+	PresenceOffline,
 }
@@ -0,0 +1,96 @@
+package schemas
+
+import (
+	"time"
+	"webservices/src/types"
+)
+
+// AnalyticQuery the inclusive date range, default the last 30 days
+type AnalyticQuery struct {
+	From *time.Time `form:"from" time_format:"2006-01-02" binding:"omitempty"`
+	To   *time.Time `form:"to" time_format:"2006-01-02" binding:"omitempty"`
+}
+
+type AnalyticCount struct {
+	Key   string  `json:"key"`
+	Label *string `json:"label,omitempty"`
+	Count int64   `json:"count"`
+}
+
+type AnalyticDaily struct {
+	Date     time.Time `json:"date"`
+	Created  int64     `json:"created"`
+	Resolved int64     `json:"resolved"`
+}
+
+// IssueAnalytics the breakdowns cover the issues created within the range,
+// the cycle & lead time (hours) the ones resolved within it
+type IssueAnalytics struct {
+	ProjectID  string          `json:"projectId"`
+	From       time.Time       `json:"from"`
+	To         time.Time       `json:"to"`
+	Total      int64           `json:"total"`
+	Resolved   int64           `json:"resolved"`
+	Overdue    int64           `json:"overdue" comment:"counted on every call, never cached"`
+	CycleTime  *float64        `json:"cycleTime"`
+	LeadTime   *float64        `json:"leadTime"`
+	ByStatus   []AnalyticCount `json:"byStatus"`
+	ByPriority []AnalyticCount `json:"byPriority"`
+	ByType     []AnalyticCount `json:"byType"`
+	ByAssignee []AnalyticCount `json:"byAssignee"`
+	Daily      []AnalyticDaily `json:"daily"`
+	CachedAt   time.Time       `json:"cachedAt"`
+}
+
+// BurndownQuery either the sprint (its start & end date) or the inclusive date range over the whole project
+type BurndownQuery struct {
+	SprintID *string    `form:"sprintId" binding:"omitempty,uuid"`
+	From     *time.Time `form:"from" time_format:"2006-01-02" binding:"omitempty"`
+	To       *time.Time `form:"to" time_format:"2006-01-02" binding:"omitempty"`
+	Measure  string     `form:"measure" binding:"omitempty,oneof=count estimate" comment:"default count"`
+}
+
+// BurndownPoint the remaining work at the end of the day, nil for the days still ahead
+type BurndownPoint struct {
+	Date      time.Time `json:"date"`
+	Remaining *float64  `json:"remaining"`
+	Scope     *float64  `json:"scope" comment:"remaining + completed, moves when issues are added or removed"`
+	Ideal     float64   `json:"ideal"`
+}
+
+// Burndown the estimates are on the project time tracking unit
+type Burndown struct {
+	ProjectID string          `json:"projectId"`
+	SprintID  *string         `json:"sprintId,omitempty"`
+	From      time.Time       `json:"from"`
+	To        time.Time       `json:"to"`
+	Measure   string          `json:"measure"`
+	Unit      *types.TimeUnit `json:"unit,omitempty"`
+	Points    []BurndownPoint `json:"points"`
+}
+
+// VelocityQuery the last `sprints` closed sprints, or the weeks of the inclusive date range when given
+type VelocityQuery struct {
+	Sprints int        `form:"sprints" binding:"omitempty,min=1,max=20" comment:"default 6"`
+	From    *time.Time `form:"from" time_format:"2006-01-02" binding:"omitempty"`
+	To      *time.Time `form:"to" time_format:"2006-01-02" binding:"omitempty"`
+	Measure string     `form:"measure" binding:"omitempty,oneof=count estimate"`
+}
+
+// VelocityPeriod `Committed` is the work planned at the sprint start, nil on the weekly periods
+type VelocityPeriod struct {
+	SprintID  *string   `json:"sprintId,omitempty"`
+	Name      string    `json:"name"`
+	From      time.Time `json:"from"`
+	To        time.Time `json:"to"`
+	Committed *float64  `json:"committed"`
+	Completed float64   `json:"completed"`
+}
+
+type Velocity struct {
+	ProjectID string           `json:"projectId"`
+	Measure   string           `json:"measure"`
+	Unit      *types.TimeUnit  `json:"unit,omitempty"`
+	Periods   []VelocityPeriod `json:"periods"`
+	Average   float64          `json:"average"`
+}
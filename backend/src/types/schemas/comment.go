@@ -3,5 +3,10 @@ package schemas
 type CreateComment struct {
 	// ID string `json:"id" binding:"omitempty"`
 	// IssueID string `json:"issueId" binding:"omitempty"`
-	Message string `json:"message" binding:"required,min=1"`
+	Message  string  `json:"message" binding:"required,min=1"`
+	ParentID *string `json:"parentId" binding:"omitempty,uuid"`
+}
+
+type ToggleReaction struct {
+	Emoji string `json:"emoji" binding:"required,max=32"`
 }
@@ -0,0 +1,19 @@
+package schemas
+
+import "webservices/src/types"
+
+type CreateCustomField struct {
+	Key      string                `json:"key" binding:"required,max=40"`
+	Name     string                `json:"name" binding:"required,max=50"`
+	Type     types.CustomFieldType `json:"type" binding:"required,oneof=text number select multi_select date user"`
+	Options  []string              `json:"options" binding:"omitempty,max=100,dive,required,max=50" comments:"select & multi_select only"`
+	Required bool                  `json:"required" binding:"omitempty"`
+}
+
+// UpdateCustomField the key & type are immutable, the values already stored rely on them
+type UpdateCustomField struct {
+	Name     string   `json:"name" binding:"required,max=50"`
+	Options  []string `json:"options" binding:"omitempty,max=100,dive,required,max=50"`
+	Required bool     `json:"required" binding:"omitempty"`
+	Position *int     `json:"position" binding:"omitempty,min=0"`
+}
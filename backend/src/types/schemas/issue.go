@@ -18,19 +18,23 @@ type IssueOrder struct {
 }
 
 type CreateIssue struct {
-	ID          *string             `json:"id" binding:"omitempty"`
-	ProjectID   *string             `json:"projectId" binding:"omitempty" comments:"when empty fill with user.projectID"`
-	Title       string              `json:"title" binding:"required"`
-	Type        types.IssueType     `json:"type" binding:"omitempty"`
-	Priority    types.IssuePriority `json:"priority" binding:"omitempty"`
-	Status      types.IssueStatus   `json:"status" binding:"omitempty"`
-	AssigneeID  *string             `json:"assigneeId" binding:"omitempty"`
-	StartDate   *types.Date         `json:"startDate" binding:"omitempty"`
-	DueDate     *types.Date         `json:"dueDate" binding:"omitempty"`
-	Label       *string             `json:"label" binding:"omitempty"`
-	Description *string             `json:"description" binding:"omitempty"`
-	Goal        *string             `json:"goal" binding:"omitempty"`
-	Parents     *string             `json:"parents" binding:"omitempty"`
+	ID           *string               `json:"id" binding:"omitempty"`
+	ProjectID    *string               `json:"projectId" binding:"omitempty" comments:"when empty fill with user.projectID"`
+	Title        string                `json:"title" binding:"required"`
+	Type         types.IssueType       `json:"type" binding:"omitempty"`
+	Priority     types.IssuePriority   `json:"priority" binding:"omitempty"`
+	Status       types.IssueStatus     `json:"status" binding:"omitempty"`
+	AssigneeID   *string               `json:"assigneeId" binding:"omitempty"`
+	SprintID     types.Partial[string] `json:"sprintId" comments:"omitted keep the current sprint on update, null or empty move into the backlog"`
+	StartDate    *types.Date           `json:"startDate" binding:"omitempty"`
+	DueDate      *types.Date           `json:"dueDate" binding:"omitempty"`
+	LabelIDs     []string              `json:"labelIds" binding:"omitempty,dive,uuid" comments:"nil keep the current labels on update"`
+	Description  *string               `json:"description" binding:"omitempty"`
+	Goal         *string               `json:"goal" binding:"omitempty"`
+	Parents      *string               `json:"parents" binding:"omitempty"`
+	CustomFields map[string]any        `json:"customFields" binding:"omitempty" comments:"by the field key, omitted keys keep their value on update & null clear it"`
+
+	OverrideTaskLimit bool `json:"overrideTaskLimit" binding:"omitempty" comments:"admin only, assign over the TaskLimitPerUser"`
 }
 
 type MoveParent struct {
@@ -38,7 +42,29 @@ type MoveParent struct {
 	Parents string `json:"parents" binding:"required"`
 }
 
+type ReviewIssue struct {
+	Reason string `json:"reason" binding:"omitempty,max=500"`
+}
+
 type FilterIssue struct {
-	Search *string `json:"search" binding:"omitempty"`
-	UserID *string `json:"userId" binding:"omitempty"`
+	Search   *string  `json:"search" binding:"omitempty"`
+	UserID   *string  `json:"userId" binding:"omitempty"`
+	LabelIDs []string `json:"labelIds" binding:"omitempty,dive,uuid"`
+	Query    *string  `json:"query" binding:"omitempty,max=2000" comments:"see jql.Parse"`
+	FilterID *string  `json:"filterId" binding:"omitempty,uuid" comments:"run the saved filter"`
+	Limit    int      `json:"limit" binding:"omitempty,min=1,max=200" comments:"paginate the board, see pagination.New"`
+	Cursor   string   `json:"cursor" binding:"omitempty" comments:"paginate the board, see pagination.New"`
+}
+
+// BulkIssue the `Action` picking which of the value fields is used, the empty
+// `AssigneeID`, `ParentID` & `SprintID` unassign, detach & move into the backlog
+type BulkIssue struct {
+	IDs        []string            `json:"ids" binding:"required,min=1,max=100,dive,uuid"`
+	Action     types.BulkAction    `json:"action" binding:"required,oneof=status priority assignee label unlabel parent sprint delete"`
+	Status     types.IssueStatus   `json:"status" binding:"required_if=Action status"`
+	Priority   types.IssuePriority `json:"priority" binding:"required_if=Action priority,omitempty,oneof=lowest low medium high highest"`
+	AssigneeID *string             `json:"assigneeId" binding:"omitempty"`
+	LabelIDs   []string            `json:"labelIds" binding:"required_if=Action label,required_if=Action unlabel,omitempty,dive,uuid"`
+	ParentID   *string             `json:"parentId" binding:"omitempty"`
+	SprintID   *string             `json:"sprintId" binding:"omitempty"`
 }
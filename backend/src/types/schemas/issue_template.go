@@ -0,0 +1,25 @@
+package schemas
+
+import "webservices/src/types"
+
+type CreateIssueTemplate struct {
+	Name         string               `json:"name" binding:"required,max=100"`
+	TitlePattern string               `json:"titlePattern" binding:"required,max=255" comments:"placeholders {{date}}, {{week}}, {{month}} & {{year}}"`
+	Description  *string              `json:"description" binding:"omitempty"`
+	Type         types.IssueType      `json:"type" binding:"omitempty,oneof=task bug story epic"`
+	Priority     *types.IssuePriority `json:"priority" binding:"omitempty,oneof=lowest low medium high highest"`
+	AssigneeID   *string              `json:"assigneeId" binding:"omitempty,uuid"`
+	Checklist    []string             `json:"checklist" binding:"omitempty,max=50,dive,max=255" comments:"the subtask titles"`
+	CustomFields map[string]any       `json:"customFields" binding:"omitempty"`
+}
+
+type ApplyIssueTemplate struct {
+	Date *types.Date `json:"date" binding:"omitempty" comments:"filling the title placeholders, today when empty"`
+}
+
+type CreateRecurrence struct {
+	TemplateID string      `json:"templateId" binding:"required,uuid"`
+	Rule       string      `json:"rule" binding:"required,max=255" comments:"see rrule.Parse, e.g. FREQ=MONTHLY;BYMONTHDAY=1;UNTIL=20271231"`
+	StartDate  *types.Date `json:"startDate" binding:"omitempty" comments:"today when empty"`
+	Active     *bool       `json:"active" binding:"omitempty"`
+}
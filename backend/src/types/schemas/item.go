@@ -8,4 +8,8 @@ type CreateItem struct {
 	Text     *string             `json:"text" binding:"omitempty"`
 	AssetID  *string             `json:"assetId" binding:"omitempty"`
 	PublicID *string             `json:"publicId" binding:"omitempty"`
+
+	// link_work only, the inverse link created on the linked issue
+	LinkedIssueID *string              `json:"linkedIssueId" binding:"omitempty"`
+	LinkType      *types.IssueLinkType `json:"linkType" binding:"omitempty,oneof=blocks is_blocked_by duplicates is_duplicated_by relates_to clones is_cloned_by"`
 }
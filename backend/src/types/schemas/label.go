@@ -0,0 +1,6 @@
+package schemas
+
+type CreateLabel struct {
+	Name  string `json:"name" binding:"required,max=50"`
+	Color string `json:"color" binding:"required,hexcolor"`
+}
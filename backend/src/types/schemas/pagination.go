@@ -0,0 +1,7 @@
+package schemas
+
+// PageQuery the cursor pagination of the list endpoints, see pagination.New
+type PageQuery struct {
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=200"`
+	Cursor string `form:"cursor" binding:"omitempty"`
+}
@@ -0,0 +1,7 @@
+package schemas
+
+// FocusPresence the `presence:focus` socket payload
+type FocusPresence struct {
+	IssueID *string `json:"issueId" comments:"empty once the issue closed"`
+	Editing bool    `json:"editing" comments:"editing the issue description"`
+}
@@ -0,0 +1,11 @@
+package schemas
+
+import "webservices/src/types"
+
+type CreateSavedFilter struct {
+	Name       string                 `json:"name" binding:"required,max=100"`
+	Query      string                 `json:"query" binding:"omitempty,max=2000"`
+	Sort       string                 `json:"sort" binding:"omitempty,max=200" comments:"ORDER BY part, e.g. priority DESC, created"`
+	GroupBy    types.BoardGrouping    `json:"groupBy" binding:"omitempty"`
+	Visibility types.FilterVisibility `json:"visibility" binding:"omitempty"`
+}
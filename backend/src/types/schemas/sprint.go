@@ -0,0 +1,25 @@
+package schemas
+
+import "webservices/src/types"
+
+type CreateSprint struct {
+	ID        *string     `json:"id" binding:"omitempty"`
+	ProjectID *string     `json:"projectId" binding:"omitempty" comments:"when empty fill with user.projectID"`
+	Name      string      `json:"name" binding:"required,max=60"`
+	Goal      *string     `json:"goal" binding:"omitempty"`
+	StartDate *types.Date `json:"startDate" binding:"omitempty"`
+	EndDate   *types.Date `json:"endDate" binding:"omitempty"`
+}
+
+type FilterSprint struct {
+	State *types.SprintState `form:"state" binding:"omitempty,oneof=planned active closed"`
+}
+
+type CloseSprint struct {
+	// where unfinished issues goes, empty moving it to the backlog
+	NextSprintID *string `json:"nextSprintId" binding:"omitempty"`
+}
+
+type AssignSprint struct {
+	IssueIDs []string `json:"issueIds" binding:"required,min=1"`
+}
@@ -0,0 +1,20 @@
+package schemas
+
+import "webservices/src/types"
+
+type WorkflowStatus struct {
+	Key      types.IssueStatus    `json:"key" binding:"required,max=40"`
+	Name     string               `json:"name" binding:"required,max=50"`
+	Category types.StatusCategory `json:"category" binding:"required,oneof=todo in_progress done"`
+}
+
+type WorkflowTransition struct {
+	From *types.IssueStatus `json:"from" binding:"omitempty" comment:"empty allowing it from any status"`
+	To   types.IssueStatus  `json:"to" binding:"required"`
+}
+
+// UpdateWorkflow the statuses in the board order, no transition allowing every change
+type UpdateWorkflow struct {
+	Statuses    []WorkflowStatus     `json:"statuses" binding:"required,min=1,max=30,dive"`
+	Transitions []WorkflowTransition `json:"transitions" binding:"omitempty,max=300,dive"`
+}
@@ -0,0 +1,28 @@
+package schemas
+
+import "webservices/src/types"
+
+// duration & estimates are on the project time_tracking_unit
+type CreateWorklog struct {
+	Duration float64     `json:"duration" binding:"required,gt=0"`
+	Date     *types.Date `json:"date" binding:"omitempty"`
+	Note     *string     `json:"note" binding:"omitempty,max=500"`
+}
+
+type UpdateEstimate struct {
+	OriginalEstimate  *float64 `json:"originalEstimate" binding:"omitempty,gte=0"`
+	RemainingEstimate *float64 `json:"remainingEstimate" binding:"omitempty,gte=0"`
+}
+
+type TimeEstimate struct {
+	OriginalEstimate  *float64 `json:"originalEstimate"`
+	RemainingEstimate *float64 `json:"remainingEstimate"`
+	TimeSpent         float64  `json:"timeSpent"`
+}
+
+type TimeTracking struct {
+	Unit     types.TimeUnit `json:"unit"`
+	Issue    TimeEstimate   `json:"issue"`
+	Subtasks TimeEstimate   `json:"subtasks" comment:"rollup of the child issues"`
+	Total    TimeEstimate   `json:"total"`
+}
@@ -45,4 +45,31 @@ export async function service<T = object>(
             error: err instanceof Error ? err.message : 'Unknown error occurred',
         } as Response<T>;
     }
-}
\ No newline at end of file
+}
+type Page<T> = {
+    data: T[]
+    pagination: { nextCursor: string | null }
+}
+
+// walk the cursor paginated list endpoint, every page fetched
+export async function servicePages<T>(endpoint: string): Promise<Response<{ data: T[] }>> {
+    const items: T[] = [];
+    const [path, search] = endpoint.split('?');
+    let cursor: string | null = null;
+
+    do {
+        const query = new URLSearchParams(search);
+        query.set('limit', '200');
+        if (cursor) query.set('cursor', cursor);
+
+        const res: Response<Page<T>> = await service<Page<T>>(`${path}?${query}`);
+        if (res.error) {
+            return res;
+        }
+
+        items.push(...res.data);
+        cursor = res.pagination.nextCursor;
+    } while (cursor);
+
+    return { data: items, code: 200 };
+}
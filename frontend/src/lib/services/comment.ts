@@ -1,13 +1,13 @@
 'use server'
 import { logger } from '@/lib/logger';
-import { service } from '@/lib/service';
+import { service, servicePages } from '@/lib/service';
 import { Comment } from '@/types/schemas/comment';
 
 type Data<T = Comment> = { data: T }
 
 export async function getComments(issueId: string) {
     const url = `/issue/${encodeURIComponent(issueId)}/comment`;
-    const { data, code, error } = await service<Data<Comment[]>>(url);
+    const { data, code, error } = await servicePages<Comment>(url);
 
     if (error) {
         logger.debug(code, error)
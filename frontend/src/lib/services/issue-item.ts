@@ -1,6 +1,6 @@
 'use server'
 import { logger } from '@/lib/logger';
-import { service } from '@/lib/service';
+import { service, servicePages } from '@/lib/service';
 import { Activity } from '@/types/schemas/activity';
 import { IssueItem, IssueItemSchema } from '@/types/schemas/issue-item';
 
@@ -20,7 +20,7 @@ export async function getIssueItems(issueId: string) {
 
 export async function getIssueActivities(issueId: string) {
     const url = `/issue/${encodeURIComponent(issueId)}/activity`;
-    const { data, code, error } = await service<Data<Activity[]>>(url);
+    const { data, code, error } = await servicePages<Activity>(url);
 
     if (error) {
         logger.debug(code, error)
@@ -1,6 +1,6 @@
 'use server'
 import { logger } from '@/lib/logger';
-import { service } from '@/lib/service';
+import { service, servicePages } from '@/lib/service';
 import { GetIssueQuery } from '@/types/query';
 import { Issue, IssueSchema } from '@/types/schemas/issue';
 
@@ -8,7 +8,7 @@ type Data<T = Issue> = { data: T }
 
 export async function getIssues(parents?: string) {
     const url = `/issue${parents ? `?id=${encodeURIComponent(parents)}` : ''}`
-    const { data, code, error } = await service<Data<Issue[]>>(url);
+    const { data, code, error } = await servicePages<Issue>(url);
 
     if (error) {
         logger.debug(code, error)
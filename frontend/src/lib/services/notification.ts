@@ -1,6 +1,6 @@
 'use server'
 import { logger } from '@/lib/logger';
-import { service } from '@/lib/service';
+import { service, servicePages } from '@/lib/service';
 import { UserRoles } from '@/types/misc';
 import { Notification } from '@/types/schemas/notification';
 
@@ -13,7 +13,7 @@ type VerifyProject = Data<{
 }>
 
 export async function getNotifications() {
-    const { data, code, error } = await service<Data<Notification[]>>('/notifications');
+    const { data, code, error } = await servicePages<Notification>('/notifications');
 
     if (error) {
         logger.debug(code, error)